@@ -61,6 +61,11 @@ func Do(staticConfiguration static.Configuration) (*http.Response, error) {
 		return nil, fmt.Errorf("ping: missing %s entry point", ep)
 	}
 
+	address, err := pingEntryPoint.GetFirstAddress()
+	if err != nil {
+		return nil, fmt.Errorf("ping: invalid %s entry point address: %w", ep, err)
+	}
+
 	client := &http.Client{Timeout: 5 * time.Second}
 	protocol := "http"
 
@@ -75,5 +80,5 @@ func Do(staticConfiguration static.Configuration) (*http.Response, error) {
 
 	path := "/"
 
-	return client.Head(protocol + "://" + pingEntryPoint.GetAddress() + path + "ping")
+	return client.Head(protocol + "://" + address + path + "ping")
 }
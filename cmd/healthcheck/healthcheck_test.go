@@ -0,0 +1,65 @@
+package healthcheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/ping"
+)
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/ping" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	testCases := []struct {
+		desc          string
+		address       string
+		expectedError bool
+	}{
+		{
+			desc:    "single address",
+			address: server.Listener.Addr().String(),
+		},
+		{
+			desc:    "list of addresses",
+			address: server.Listener.Addr().String() + ",127.0.0.1:10000-10002/tcp",
+		},
+		{
+			desc:          "invalid address",
+			address:       "127.0.0.1",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := Do(static.Configuration{
+				Ping: &ping.Handler{},
+				EntryPoints: static.EntryPoints{
+					"traefik": {Address: test.address},
+				},
+			})
+			if test.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = resp.Body.Close() })
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package needle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/traefik/paerser/cli"
+	"github.com/traefik/paerser/flag"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/metrics"
+	"github.com/traefik/traefik/v3/pkg/needleware"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"github.com/traefik/traefik/v3/pkg/provider/file"
+)
+
+// CheckConfiguration holds the configuration of the check command.
+type CheckConfiguration struct {
+	ConfigFile    string            `description:"Dynamic configuration file holding the needle." export:"true"`
+	Protocol      string            `description:"Protocol of the synthetic connection: tcp or udp." export:"true"`
+	RemoteAddress string            `description:"Client address of the synthetic connection." export:"true"`
+	LocalAddress  string            `description:"Local address of the synthetic connection." export:"true"`
+	Metadata      map[string]string `description:"Metadata sent along with the synthetic connection." export:"true"`
+}
+
+func newCheckConfiguration() *CheckConfiguration {
+	return &CheckConfiguration{
+		Protocol:      "tcp",
+		RemoteAddress: "127.0.0.1:40000",
+		LocalAddress:  "127.0.0.1:8000",
+	}
+}
+
+func newCheckCmd() *cli.Command {
+	config := newCheckConfiguration()
+
+	return &cli.Command{
+		Name: "check",
+		Description: `Builds the given needle from a dynamic configuration file, and asks its decision service about a synthetic connection.
+Usage: traefik needle check [flags] <needle name>`,
+		Configuration: config,
+		AllowArg:      true,
+		Run: func(args []string) error {
+			var flags, names []string
+			for _, arg := range args {
+				if strings.HasPrefix(arg, "-") {
+					flags = append(flags, arg)
+				} else {
+					names = append(names, arg)
+				}
+			}
+
+			if len(flags) > 0 {
+				if err := flag.Decode(flags, config); err != nil {
+					return fmt.Errorf("failed to decode configuration from flags: %w", err)
+				}
+			}
+
+			if len(names) != 1 {
+				return errors.New("exactly one needle name is expected")
+			}
+
+			return runCheck(os.Stdout, config, names[0])
+		},
+	}
+}
+
+func runCheck(w io.Writer, config *CheckConfiguration, name string) error {
+	if config.ConfigFile == "" {
+		return errors.New("a configuration file is required")
+	}
+
+	conf, err := (&file.Provider{}).DecodeConfiguration(config.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	// The needles of the file are not qualified by the provider name.
+	name = strings.TrimSuffix(name, "@file")
+
+	var needleConf *dynamic.Needle
+	if conf.Needleware != nil {
+		needleConf = conf.Needleware.Needles[name]
+	}
+	if needleConf == nil {
+		return fmt.Errorf("needle %q not found in %s", name, config.ConfigFile)
+	}
+
+	// The decision has to be waited for, whatever the mode of the needle.
+	needleConf = needleConf.DeepCopy()
+	if needleConf.Decision != nil {
+		needleConf.Decision.Mode = ""
+	}
+
+	manager := needleware.NewManager(metrics.NewVoidRegistry())
+	manager.BuildNeedles(context.Background(), &runtime.Configuration{
+		Needles: map[string]*runtime.NeedleInfo{name: {Needle: needleConf}},
+	})
+
+	needle, err := manager.GetNeedle(name, config.Metadata)
+	if err != nil {
+		return fmt.Errorf("needle %q cannot be built, see the logs for details: %w", name, err)
+	}
+
+	criteria, err := needle.NewUDPCriteria(config.RemoteAddress, config.LocalAddress)
+	if err != nil {
+		return fmt.Errorf("invalid synthetic connection: %w", err)
+	}
+
+	switch strings.ToLower(config.Protocol) {
+	case "tcp":
+		criteria.Protocol = client.ProtocolTCP
+	case "udp":
+		criteria.Protocol = client.ProtocolUDP
+	default:
+		return fmt.Errorf("unknown protocol: %s", config.Protocol)
+	}
+
+	start := time.Now()
+	decision, err := needle.Decide(criteria)
+	if err != nil {
+		return err
+	}
+	latency := time.Since(start)
+
+	printDecision(w, name, criteria, decision, latency)
+
+	if decision.Status == client.StatusDecisionError {
+		return errors.New("the decision service returned an error")
+	}
+	return nil
+}
+
+func printDecision(w io.Writer, name string, criteria *client.DecisionCriteria, decision *needleware.DecisionWrapper, latency time.Duration) {
+	_, _ = fmt.Fprintf(w, "Needle:        %s\n", name)
+	_, _ = fmt.Fprintf(w, "Connection ID: %s\n", criteria.ConnUID)
+	_, _ = fmt.Fprintf(w, "Status:        %s\n", statusName(decision.Status))
+
+	if decision.ConnAccepted() {
+		_, _ = fmt.Fprintln(w, "Decision:      accept")
+	} else {
+		_, _ = fmt.Fprintln(w, "Decision:      reject")
+	}
+
+	if target := decision.Target; target != nil {
+		_, _ = fmt.Fprintf(w, "Target:        server=%q service=%q\n", target.ServerAddress, target.Service)
+	}
+
+	if limits := decision.Limits; limits != nil {
+		_, _ = fmt.Fprintf(w, "Limits:        upload=%dB/s download=%dB/s maxTotalBytes=%d maxDuration=%s\n",
+			limits.UploadBytesPerSecond, limits.DownloadBytesPerSecond, limits.MaxTotalBytes, limits.MaxDuration)
+	}
+
+	_, _ = fmt.Fprintf(w, "Latency:       %s\n", latency)
+}
+
+func statusName(status client.DecisionStatus) string {
+	switch status {
+	case client.StatusDecisionError:
+		return "error"
+	case client.StatusDecisionTimeout:
+		return "timeout"
+	case client.StatusDecisionCanceled:
+		return "canceled"
+	case client.StatusDecisionOverloaded:
+		return "overloaded"
+	default:
+		return "loaded"
+	}
+}
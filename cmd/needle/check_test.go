@@ -0,0 +1,97 @@
+package needle
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/needleware/client/pb"
+	"github.com/traefik/traefik/v3/pkg/needleware/refserver"
+	"google.golang.org/grpc"
+)
+
+func TestRunCheck(t *testing.T) {
+	rules, err := refserver.ParseRules([]byte(`
+rules:
+  - match:
+      remoteCIDRs: ["10.0.0.0/8"]
+    decision: reject
+  - match:
+      metadata:
+        tenant: foo
+    target:
+      service: db@file
+`))
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	pb.RegisterNeedlewareServer(server, refserver.NewServer(rules))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	configFile := filepath.Join(t.TempDir(), "dynamic.yml")
+	content := fmt.Sprintf("needleware:\n  needles:\n    foo:\n      endpoint: %s\n", listener.Addr())
+	require.NoError(t, os.WriteFile(configFile, []byte(content), 0o600))
+
+	testCases := []struct {
+		desc          string
+		name          string
+		remoteAddress string
+		metadata      map[string]string
+		expected      []string
+		expectedErr   bool
+	}{
+		{
+			desc:          "rejected connection",
+			name:          "foo@file",
+			remoteAddress: "10.0.0.1:40000",
+			expected:      []string{"Needle:        foo", "Status:        loaded", "Decision:      reject"},
+		},
+		{
+			desc:          "accepted connection with target",
+			name:          "foo",
+			remoteAddress: "192.168.0.1:40000",
+			metadata:      map[string]string{"tenant": "foo"},
+			expected:      []string{"Decision:      accept", `Target:        server="" service="db@file"`},
+		},
+		{
+			desc:        "unknown needle",
+			name:        "bar",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			config := newCheckConfiguration()
+			config.ConfigFile = configFile
+			config.Metadata = test.metadata
+			if test.remoteAddress != "" {
+				config.RemoteAddress = test.remoteAddress
+			}
+
+			var out bytes.Buffer
+			err := runCheck(&out, config, test.name)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, line := range test.expected {
+				assert.Contains(t, out.String(), line)
+			}
+		})
+	}
+}
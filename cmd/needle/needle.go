@@ -0,0 +1,24 @@
+// Package needle implements the commands helping to develop and debug needles.
+package needle
+
+import (
+	"github.com/traefik/paerser/cli"
+)
+
+// NewCmd builds a new Needle command, holding the check and serve sub-commands.
+func NewCmd() (*cli.Command, error) {
+	cmd := &cli.Command{
+		Name:        "needle",
+		Description: `Checks needles, or runs a reference decision server.`,
+	}
+
+	if err := cmd.AddCommand(newCheckCmd()); err != nil {
+		return nil, err
+	}
+
+	if err := cmd.AddCommand(newServeCmd()); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
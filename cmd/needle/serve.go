@@ -0,0 +1,66 @@
+package needle
+
+import (
+	"errors"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/paerser/cli"
+	"github.com/traefik/traefik/v3/pkg/needleware/client/pb"
+	"github.com/traefik/traefik/v3/pkg/needleware/refserver"
+	"google.golang.org/grpc"
+)
+
+// ServeConfiguration holds the configuration of the serve command.
+type ServeConfiguration struct {
+	Address   string `description:"Address the decision server listens on." export:"true"`
+	RulesFile string `description:"YAML file holding the rules of the decision server." export:"true"`
+}
+
+func newServeCmd() *cli.Command {
+	config := &ServeConfiguration{Address: ":50051"}
+
+	return &cli.Command{
+		Name:          "serve",
+		Description:   `Runs a reference Needleware decision server, answering according to a YAML rules file.`,
+		Configuration: config,
+		Resources:     []cli.ResourceLoader{&cli.FlagLoader{}},
+		Run: func(_ []string) error {
+			return runServe(config)
+		},
+	}
+}
+
+func runServe(config *ServeConfiguration) error {
+	if config.RulesFile == "" {
+		return errors.New("a rules file is required")
+	}
+
+	rules, err := refserver.LoadRules(config.RulesFile)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", config.Address)
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer()
+	pb.RegisterNeedlewareServer(server, refserver.NewServer(rules))
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		log.Info().Msg("Stopping the decision server")
+		server.GracefulStop()
+	}()
+
+	log.Info().Msgf("Decision server listening on %s with %d rules", listener.Addr(), len(rules.Rules))
+
+	return server.Serve(listener)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/traefik/paerser/cli"
 	"github.com/traefik/traefik/v3/cmd"
 	"github.com/traefik/traefik/v3/cmd/healthcheck"
+	"github.com/traefik/traefik/v3/cmd/needle"
 	cmdVersion "github.com/traefik/traefik/v3/cmd/version"
 	tcli "github.com/traefik/traefik/v3/pkg/cli"
 	"github.com/traefik/traefik/v3/pkg/collector"
@@ -77,6 +78,18 @@ Complete documentation is available at https://traefik.io`,
 		os.Exit(1)
 	}
 
+	cmdNeedle, err := needle.NewCmd()
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
+	err = cmdTraefik.AddCommand(cmdNeedle)
+	if err != nil {
+		stdlog.Println(err)
+		os.Exit(1)
+	}
+
 	err = cli.Execute(cmdTraefik)
 	if err != nil {
 		log.Error().Err(err).Msg("Command error")
@@ -351,7 +364,7 @@ func setupServer(staticConfiguration *static.Configuration) (*server.Server, err
 		}
 	})
 
-	return server.NewServer(routinesPool, serverEntryPointsTCP, serverEntryPointsUDP, watcher, chainBuilder, accessLog), nil
+	return server.NewServer(routinesPool, serverEntryPointsTCP, serverEntryPointsUDP, watcher, chainBuilder, accessLog, routerFactory.NeedlewareManager()), nil
 }
 
 func getHTTPChallengeHandler(acmeProviders []*acme.Provider, httpChallengeProvider http.Handler) http.Handler {
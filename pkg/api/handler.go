@@ -33,16 +33,21 @@ type serviceInfoRepresentation struct {
 	ServerStatus map[string]string `json:"serverStatus,omitempty"`
 }
 
+type tcpServiceInfoRepresentation struct {
+	*runtime.TCPServiceInfo
+	ServerStatus map[string]string `json:"serverStatus,omitempty"`
+}
+
 // RunTimeRepresentation is the configuration information exposed by the API handler.
 type RunTimeRepresentation struct {
-	Routers        map[string]*runtime.RouterInfo        `json:"routers,omitempty"`
-	Middlewares    map[string]*runtime.MiddlewareInfo    `json:"middlewares,omitempty"`
-	Services       map[string]*serviceInfoRepresentation `json:"services,omitempty"`
-	TCPRouters     map[string]*runtime.TCPRouterInfo     `json:"tcpRouters,omitempty"`
-	TCPMiddlewares map[string]*runtime.TCPMiddlewareInfo `json:"tcpMiddlewares,omitempty"`
-	TCPServices    map[string]*runtime.TCPServiceInfo    `json:"tcpServices,omitempty"`
-	UDPRouters     map[string]*runtime.UDPRouterInfo     `json:"udpRouters,omitempty"`
-	UDPServices    map[string]*runtime.UDPServiceInfo    `json:"udpServices,omitempty"`
+	Routers        map[string]*runtime.RouterInfo           `json:"routers,omitempty"`
+	Middlewares    map[string]*runtime.MiddlewareInfo       `json:"middlewares,omitempty"`
+	Services       map[string]*serviceInfoRepresentation    `json:"services,omitempty"`
+	TCPRouters     map[string]*runtime.TCPRouterInfo        `json:"tcpRouters,omitempty"`
+	TCPMiddlewares map[string]*runtime.TCPMiddlewareInfo    `json:"tcpMiddlewares,omitempty"`
+	TCPServices    map[string]*tcpServiceInfoRepresentation `json:"tcpServices,omitempty"`
+	UDPRouters     map[string]*runtime.UDPRouterInfo        `json:"udpRouters,omitempty"`
+	UDPServices    map[string]*runtime.UDPServiceInfo       `json:"udpServices,omitempty"`
 }
 
 // Handler serves the configuration and status of Traefik on API endpoints.
@@ -123,13 +128,21 @@ func (h Handler) getRuntimeConfiguration(rw http.ResponseWriter, request *http.R
 		}
 	}
 
+	tcpSiRepr := make(map[string]*tcpServiceInfoRepresentation, len(h.runtimeConfiguration.TCPServices))
+	for k, v := range h.runtimeConfiguration.TCPServices {
+		tcpSiRepr[k] = &tcpServiceInfoRepresentation{
+			TCPServiceInfo: v,
+			ServerStatus:   v.GetAllStatus(),
+		}
+	}
+
 	result := RunTimeRepresentation{
 		Routers:        h.runtimeConfiguration.Routers,
 		Middlewares:    h.runtimeConfiguration.Middlewares,
 		Services:       siRepr,
 		TCPRouters:     h.runtimeConfiguration.TCPRouters,
 		TCPMiddlewares: h.runtimeConfiguration.TCPMiddlewares,
-		TCPServices:    h.runtimeConfiguration.TCPServices,
+		TCPServices:    tcpSiRepr,
 		UDPRouters:     h.runtimeConfiguration.UDPRouters,
 		UDPServices:    h.runtimeConfiguration.UDPServices,
 	}
@@ -0,0 +1,101 @@
+// Package balancer implements the server selection strategies of the TCP and UDP load-balancers,
+// other than the weighted round robin which each of them implements, as well as the selection of the targeted servers.
+package balancer
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// Strategy is the way a load-balancer selects the server of a connection.
+type Strategy string
+
+// Strategies.
+const (
+	// StrategyWRR is the weighted round robin.
+	StrategyWRR Strategy = "wrr"
+	// StrategyLeastConn selects the server with the fewest active connections, relative to its weight.
+	StrategyLeastConn Strategy = "leastconn"
+	// StrategyP2C selects the server with the fewest active connections, among two servers picked at random.
+	StrategyP2C Strategy = "p2c"
+	// StrategyHash selects the server by consistent hashing of a connection key, with bounded loads.
+	StrategyHash Strategy = "hash"
+)
+
+// ParseStrategy returns the strategy with the given name, the weighted round robin being the default one.
+func ParseStrategy(name string) (Strategy, error) {
+	switch strategy := Strategy(strings.ToLower(name)); strategy {
+	case "":
+		return StrategyWRR, nil
+	case StrategyWRR, StrategyLeastConn, StrategyP2C, StrategyHash:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown load-balancing strategy: %s", name)
+	}
+}
+
+// Servers is the view of the servers of a load-balancer the strategies select from, by index.
+type Servers interface {
+	Len() int
+	// Name identifies the server across the configurations, e.g. its address.
+	Name(i int) string
+	Weight(i int) int
+	// Available reports whether the server can get connections from the load-balancing.
+	Available(i int) bool
+	// Active returns the number of connections the server is currently handling.
+	Active(i int) int64
+}
+
+// LeastConn returns the index of the available server with the fewest active connections relative to its weight.
+// The servers are looked at from the given offset, for the ties to be broken in turn.
+// It returns false if no server is available.
+func LeastConn(servers Servers, offset int) (int, bool) {
+	best := -1
+	for n := 0; n < servers.Len(); n++ {
+		i := (offset + n) % servers.Len()
+		if !servers.Available(i) {
+			continue
+		}
+
+		if best == -1 || lessLoaded(servers, i, best) {
+			best = i
+		}
+	}
+
+	return best, best != -1
+}
+
+// P2C returns the index of the least loaded of two available servers picked at random.
+// It returns false if no server is available.
+func P2C(servers Servers, r *rand.Rand) (int, bool) {
+	var available []int
+	for i := 0; i < servers.Len(); i++ {
+		if servers.Available(i) {
+			available = append(available, i)
+		}
+	}
+
+	switch len(available) {
+	case 0:
+		return -1, false
+	case 1:
+		return available[0], true
+	}
+
+	first := r.Intn(len(available))
+	second := r.Intn(len(available) - 1)
+	if second >= first {
+		second++
+	}
+
+	if lessLoaded(servers, available[second], available[first]) {
+		return available[second], true
+	}
+	return available[first], true
+}
+
+// lessLoaded reports whether the server i has fewer active connections than the server j, relative to their weight.
+func lessLoaded(servers Servers, i, j int) bool {
+	return servers.Active(i)*int64(servers.Weight(j)) < servers.Active(j)*int64(servers.Weight(i))
+}
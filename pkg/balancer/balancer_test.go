@@ -0,0 +1,158 @@
+package balancer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testServer struct {
+	name   string
+	weight int
+	down   bool
+	active int64
+}
+
+type testServers []testServer
+
+func (s testServers) Len() int             { return len(s) }
+func (s testServers) Name(i int) string    { return s[i].name }
+func (s testServers) Weight(i int) int     { return s[i].weight }
+func (s testServers) Available(i int) bool { return !s[i].down && s[i].weight > 0 }
+func (s testServers) Active(i int) int64   { return s[i].active }
+
+func TestParseStrategy(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		name      string
+		expected  Strategy
+		expectErr bool
+	}{
+		{desc: "default", name: "", expected: StrategyWRR},
+		{desc: "wrr", name: "wrr", expected: StrategyWRR},
+		{desc: "case insensitive", name: "LeastConn", expected: StrategyLeastConn},
+		{desc: "p2c", name: "p2c", expected: StrategyP2C},
+		{desc: "hash", name: "hash", expected: StrategyHash},
+		{desc: "unknown", name: "random", expectErr: true},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			strategy, err := ParseStrategy(test.name)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, strategy)
+		})
+	}
+}
+
+func TestLeastConn(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		servers    testServers
+		offset     int
+		expected   int
+		expectedOK bool
+	}{
+		{
+			desc: "fewest active connections",
+			servers: testServers{
+				{name: "a", weight: 1, active: 3},
+				{name: "b", weight: 1, active: 1},
+				{name: "c", weight: 1, active: 2},
+			},
+			expected:   1,
+			expectedOK: true,
+		},
+		{
+			desc: "relative to the weight",
+			servers: testServers{
+				{name: "a", weight: 1, active: 2},
+				{name: "b", weight: 3, active: 3},
+			},
+			expected:   1,
+			expectedOK: true,
+		},
+		{
+			desc: "ties broken from the offset",
+			servers: testServers{
+				{name: "a", weight: 1},
+				{name: "b", weight: 1},
+				{name: "c", weight: 1},
+			},
+			offset:     2,
+			expected:   2,
+			expectedOK: true,
+		},
+		{
+			desc: "down and zero weight servers skipped",
+			servers: testServers{
+				{name: "a", weight: 1, down: true},
+				{name: "b", weight: 0},
+				{name: "c", weight: 1, active: 10},
+			},
+			expected:   2,
+			expectedOK: true,
+		},
+		{
+			desc: "no available server",
+			servers: testServers{
+				{name: "a", weight: 1, down: true},
+			},
+			expected: -1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			index, ok := LeastConn(test.servers, test.offset)
+			assert.Equal(t, test.expectedOK, ok)
+			assert.Equal(t, test.expected, index)
+		})
+	}
+}
+
+func TestP2C(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	// With two servers, both are always picked, so the least loaded one is selected.
+	servers := testServers{
+		{name: "a", weight: 1, active: 5},
+		{name: "b", weight: 1, active: 1},
+	}
+	for i := 0; i < 10; i++ {
+		index, ok := P2C(servers, r)
+		require.True(t, ok)
+		assert.Equal(t, 1, index)
+	}
+
+	// The most loaded server is never selected.
+	servers = testServers{
+		{name: "a", weight: 1, active: 1},
+		{name: "b", weight: 1, active: 9},
+		{name: "c", weight: 1, active: 2},
+	}
+	for i := 0; i < 100; i++ {
+		index, ok := P2C(servers, r)
+		require.True(t, ok)
+		assert.NotEqual(t, 1, index)
+	}
+
+	index, ok := P2C(testServers{{name: "a", weight: 1, down: true}, {name: "b", weight: 1}}, r)
+	require.True(t, ok)
+	assert.Equal(t, 1, index)
+
+	_, ok = P2C(testServers{{name: "a", weight: 1, down: true}}, r)
+	assert.False(t, ok)
+}
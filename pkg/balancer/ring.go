@@ -0,0 +1,95 @@
+package balancer
+
+import (
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+)
+
+const (
+	// pointsPerWeight is the number of points a server has on the ring, per unit of weight.
+	pointsPerWeight = 100
+	// loadFactor bounds the active connections of each server to 125% of its share of the total.
+	loadFactor = 1.25
+)
+
+type ringPoint struct {
+	hash   uint64
+	server int
+}
+
+// Ring is a consistent hash ring of servers, with bounded loads.
+// The points of the servers are computed from their name,
+// so that adding or removing a server only remaps the keys of that server.
+type Ring struct {
+	points []ringPoint
+}
+
+// NewRing creates the ring of the given servers, the servers without weight being left out.
+func NewRing(servers Servers) *Ring {
+	ring := &Ring{}
+	for i := 0; i < servers.Len(); i++ {
+		name := servers.Name(i)
+		for p := 0; p < servers.Weight(i)*pointsPerWeight; p++ {
+			ring.points = append(ring.points, ringPoint{hash: hash(name + "#" + strconv.Itoa(p)), server: i})
+		}
+	}
+
+	sort.Slice(ring.points, func(i, j int) bool { return ring.points[i].hash < ring.points[j].hash })
+
+	return ring
+}
+
+// Pick returns the index of the server of the given key, which is the first available server clockwise from the key
+// with fewer active connections than its bound, i.e. its weighted share of the total active connections times the load factor.
+// It returns false if no server is available.
+func (r *Ring) Pick(servers Servers, key string) (int, bool) {
+	var totalActive int64
+	var totalWeight int
+	for i := 0; i < servers.Len(); i++ {
+		if servers.Available(i) {
+			totalActive += servers.Active(i)
+			totalWeight += servers.Weight(i)
+		}
+	}
+	if len(r.points) == 0 || totalWeight == 0 {
+		return -1, false
+	}
+
+	keyHash := hash(key)
+	start := sort.Search(len(r.points), func(n int) bool { return r.points[n].hash >= keyHash })
+
+	fallback := -1
+	for n := 0; n < len(r.points); n++ {
+		server := r.points[(start+n)%len(r.points)].server
+		if !servers.Available(server) {
+			continue
+		}
+		if fallback == -1 {
+			fallback = server
+		}
+
+		bound := math.Ceil(loadFactor * float64(totalActive+1) * float64(servers.Weight(server)) / float64(totalWeight))
+		if float64(servers.Active(server)) < bound {
+			return server, true
+		}
+	}
+
+	return fallback, fallback != -1
+}
+
+// hash returns the FNV-1a hash of the given value, mixed for its bits to be evenly spread over the ring.
+func hash(value string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(value))
+
+	// Finalizer of SplitMix64.
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
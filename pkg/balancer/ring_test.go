@@ -0,0 +1,75 @@
+package balancer
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing_Pick_Stable(t *testing.T) {
+	servers := testServers{
+		{name: "10.0.0.1:80", weight: 1},
+		{name: "10.0.0.2:80", weight: 1},
+		{name: "10.0.0.3:80", weight: 1},
+	}
+	// The removed server is the second one, so the indexes of the others change.
+	reduced := testServers{servers[0], servers[2]}
+
+	ring := NewRing(servers)
+	reducedRing := NewRing(reduced)
+
+	picked := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		key := "192.168.0." + strconv.Itoa(i)
+
+		index, ok := ring.Pick(servers, key)
+		require.True(t, ok)
+		picked[servers[index].name]++
+
+		reducedIndex, ok := reducedRing.Pick(reduced, key)
+		require.True(t, ok)
+
+		// Only the keys of the removed server are remapped.
+		if servers[index].name != "10.0.0.2:80" {
+			assert.Equal(t, servers[index].name, reduced[reducedIndex].name, key)
+		}
+
+		// The same key always goes to the same server.
+		again, _ := ring.Pick(servers, key)
+		assert.Equal(t, index, again)
+	}
+
+	for _, srv := range servers {
+		assert.Greater(t, picked[srv.name], 200, srv.name)
+	}
+}
+
+func TestRing_Pick_BoundedLoad(t *testing.T) {
+	servers := testServers{
+		{name: "a", weight: 1},
+		{name: "b", weight: 1},
+	}
+	ring := NewRing(servers)
+
+	index, ok := ring.Pick(servers, "key")
+	require.True(t, ok)
+
+	// The server of the key is overloaded, so the key goes to the other one.
+	servers[index].active = 10
+	overloaded, ok := ring.Pick(servers, "key")
+	require.True(t, ok)
+	assert.NotEqual(t, index, overloaded)
+
+	// Down servers are skipped.
+	servers[index].active = 0
+	servers[index].down = true
+	down, ok := ring.Pick(servers, "key")
+	require.True(t, ok)
+	assert.NotEqual(t, index, down)
+
+	servers[1-index].down = true
+	_, ok = ring.Pick(servers, "key")
+	assert.False(t, ok)
+}
@@ -0,0 +1,55 @@
+package balancer
+
+import "github.com/rs/zerolog/log"
+
+// TargetServers is the view of the servers of a load-balancer a target selects from, by index.
+type TargetServers interface {
+	Len() int
+	// Address is the address of the server, when it forwards to a single server.
+	Address(i int) string
+	// Service is the name of the service, when the server is a child service of a weighted service.
+	Service(i int) string
+}
+
+// Targeted returns the index of the server selected by the target service or server address,
+// and false to fall back to the load-balancing.
+// As the load-balancers may be nested, serviceMatched is set once the service is honored,
+// and the target is only reported as invalid by the load-balancer of servers,
+// which is the last one the connection goes through.
+func Targeted(servers TargetServers, service, serverAddress string, serviceMatched *bool) (int, bool) {
+	if service != "" && !*serviceMatched {
+		for i := 0; i < servers.Len(); i++ {
+			if servers.Service(i) == service {
+				*serviceMatched = true
+				return i, true
+			}
+		}
+	}
+
+	hasAddresses := false
+	for i := 0; i < servers.Len(); i++ {
+		if servers.Address(i) != "" {
+			hasAddresses = true
+			break
+		}
+	}
+	if !hasAddresses {
+		return -1, false
+	}
+
+	if service != "" && !*serviceMatched {
+		log.Warn().Str("targetService", service).Msg("Unknown target service, ignoring it")
+	}
+
+	if serverAddress == "" {
+		return -1, false
+	}
+	for i := 0; i < servers.Len(); i++ {
+		if servers.Address(i) == serverAddress {
+			return i, true
+		}
+	}
+
+	log.Warn().Str("targetServerAddress", serverAddress).Msg("Unknown target server address, falling back to load balancing")
+	return -1, false
+}
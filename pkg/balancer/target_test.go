@@ -0,0 +1,92 @@
+package balancer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testTarget struct {
+	address string
+	service string
+}
+
+type testTargets []testTarget
+
+func (s testTargets) Len() int             { return len(s) }
+func (s testTargets) Address(i int) string { return s[i].address }
+func (s testTargets) Service(i int) string { return s[i].service }
+
+func TestTargeted(t *testing.T) {
+	services := testTargets{{service: "svc1"}, {service: "svc2"}}
+	addresses := testTargets{{address: "h1"}, {address: "h2"}}
+
+	testCases := []struct {
+		desc                   string
+		servers                testTargets
+		service                string
+		serverAddress          string
+		serviceMatched         bool
+		expected               int
+		expectedOk             bool
+		expectedServiceMatched bool
+	}{
+		{
+			desc:                   "service",
+			servers:                services,
+			service:                "svc2",
+			expected:               1,
+			expectedOk:             true,
+			expectedServiceMatched: true,
+		},
+		{
+			desc:     "unknown service",
+			servers:  services,
+			service:  "svc3",
+			expected: -1,
+		},
+		{
+			desc:                   "service already matched by a parent load-balancer",
+			servers:                addresses,
+			service:                "svc2",
+			serverAddress:          "h2",
+			serviceMatched:         true,
+			expected:               1,
+			expectedOk:             true,
+			expectedServiceMatched: true,
+		},
+		{
+			desc:          "server address",
+			servers:       addresses,
+			serverAddress: "h1",
+			expected:      0,
+			expectedOk:    true,
+		},
+		{
+			desc:          "server address among services",
+			servers:       services,
+			serverAddress: "h1",
+			expected:      -1,
+		},
+		{
+			desc:          "unknown server address",
+			servers:       addresses,
+			serverAddress: "h3",
+			expected:      -1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			serviceMatched := test.serviceMatched
+			i, ok := Targeted(test.servers, test.service, test.serverAddress, &serviceMatched)
+
+			assert.Equal(t, test.expected, i)
+			assert.Equal(t, test.expectedOk, ok)
+			assert.Equal(t, test.expectedServiceMatched, serviceMatched)
+		})
+	}
+}
@@ -9,18 +9,44 @@ type Needleware struct {
 // +k8s:deepcopy-gen=true
 
 type Needle struct {
-	Endpoint        string          `json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty" export:"true"`
-	Client          *NeedleClient   `json:"client,omitempty" toml:"client,omitempty" yaml:"client,omitempty" export:"true"`
-	Decision        *NeedleDecision `json:"decision,omitempty" toml:"decision,omitempty" yaml:"decision,omitempty" export:"true"`
-	NotifyConnClose []string        `json:"notifyConnClose,omitempty" toml:"notifyConnClose,omitempty" yaml:"notifyConnClose,omitempty" export:"true"`
+	Endpoint        string             `json:"endpoint,omitempty" toml:"endpoint,omitempty" yaml:"endpoint,omitempty" export:"true"`
+	Client          *NeedleClient      `json:"client,omitempty" toml:"client,omitempty" yaml:"client,omitempty" export:"true"`
+	Decision        *NeedleDecision    `json:"decision,omitempty" toml:"decision,omitempty" yaml:"decision,omitempty" export:"true"`
+	NotifyConnClose []string           `json:"notifyConnClose,omitempty" toml:"notifyConnClose,omitempty" yaml:"notifyConnClose,omitempty" export:"true"`
+	CloseEvents     *NeedleCloseEvents `json:"closeEvents,omitempty" toml:"closeEvents,omitempty" yaml:"closeEvents,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// NeedleCloseEvents configures the queue delivering the connection close events of a needle.
+type NeedleCloseEvents struct {
+	// QueueSize is the maximum number of events waiting for delivery.
+	QueueSize int `json:"queueSize,omitempty" toml:"queueSize,omitempty" yaml:"queueSize,omitempty" export:"true"`
+	// BatchSize is the maximum number of events delivered at once.
+	BatchSize int `json:"batchSize,omitempty" toml:"batchSize,omitempty" yaml:"batchSize,omitempty" export:"true"`
+	// FlushInterval is how long events wait for a batch to fill up before being delivered.
+	FlushInterval string `json:"flushInterval,omitempty" toml:"flushInterval,omitempty" yaml:"flushInterval,omitempty" export:"true"`
+	// OnOverflow is what happens to a new event when the queue is full: dropOldest, dropNewest or block,
+	// which waits for room at most for the connection timeout of the needle, and then drops the new event.
+	OnOverflow string `json:"onOverflow,omitempty" toml:"onOverflow,omitempty" yaml:"onOverflow,omitempty" export:"true"`
+	// SpoolDir is the directory where pending events are persisted, to be delivered after a restart.
+	SpoolDir string `json:"spoolDir,omitempty" toml:"spoolDir,omitempty" yaml:"spoolDir,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
 
 type NeedleDecision struct {
+	// Mode is how the decision is applied: enforce waits for it, shadow only records it and always accepts,
+	// optimistic accepts right away and closes the connection if the decision turns out to be a reject.
+	Mode      string `json:"mode,omitempty" toml:"mode,omitempty" yaml:"mode,omitempty" export:"true"`
 	OnTimeout string `json:"onTimeout,omitempty" toml:"onTimeout,omitempty" yaml:"onTimeout,omitempty" export:"true"`
 	OnError   string `json:"onError,omitempty" toml:"onError,omitempty" yaml:"onError,omitempty" export:"true"`
-	OnReject  string `json:"onReject,omitempty" toml:"onReject,omitempty" yaml:"onReject,omitempty" export:"true"`
+	// FallbackNeedle is the name of the needle deciding instead of this one on errors and timeouts,
+	// in which case OnError and OnTimeout are not used.
+	FallbackNeedle string `json:"fallbackNeedle,omitempty" toml:"fallbackNeedle,omitempty" yaml:"fallbackNeedle,omitempty" export:"true"`
+	// OnOverload is the decision when the concurrency limit of the client is reached: reject or accept.
+	OnOverload string `json:"onOverload,omitempty" toml:"onOverload,omitempty" yaml:"onOverload,omitempty" export:"true"`
+	OnReject   string `json:"onReject,omitempty" toml:"onReject,omitempty" yaml:"onReject,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -29,6 +55,34 @@ type NeedleClient struct {
 	Type    string      `json:"type,omitempty" toml:"type,omitempty" yaml:"type,omitempty" export:"true"`
 	Timeout string      `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
 	Auth    *NeedleAuth `json:"auth,omitempty" toml:"auth,omitempty" yaml:"auth,omitempty" export:"true"`
+	// Retry retries the decision requests which failed or timed out, within the timeout of the client.
+	Retry *NeedleRetry `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
+	// Hedging sends a second decision request when the first one is slow, the first answer winning.
+	Hedging *NeedleHedging `json:"hedging,omitempty" toml:"hedging,omitempty" yaml:"hedging,omitempty" export:"true"`
+	// MaxConcurrent limits the number of decisions asked for at the same time, zero meaning unlimited.
+	MaxConcurrent int `json:"maxConcurrent,omitempty" toml:"maxConcurrent,omitempty" yaml:"maxConcurrent,omitempty" export:"true"`
+	// MaxQueue limits the number of decisions waiting for MaxConcurrent, defaulting to 1024.
+	// They wait until the timeout of the client at most, the decision being then decision.onOverload.
+	MaxQueue int `json:"maxQueue,omitempty" toml:"maxQueue,omitempty" yaml:"maxQueue,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// NeedleRetry configures the retries of the decision requests. The close events are retried on their own.
+type NeedleRetry struct {
+	// Attempts is the maximum number of attempts, including the first one.
+	// They are spaced by a short jittered backoff, as long as the timeout of the client leaves room for another attempt.
+	Attempts int `json:"attempts,omitempty" toml:"attempts,omitempty" yaml:"attempts,omitempty" export:"true"`
+	// PerAttemptTimeout bounds each attempt, the timeout of the client bounding them all.
+	PerAttemptTimeout string `json:"perAttemptTimeout,omitempty" toml:"perAttemptTimeout,omitempty" yaml:"perAttemptTimeout,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// NeedleHedging configures the hedging of the decision requests.
+type NeedleHedging struct {
+	// Delay is how long the first request is waited for before sending a second one.
+	Delay string `json:"delay,omitempty" toml:"delay,omitempty" yaml:"delay,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -49,10 +103,26 @@ needleware:
 				auth:
 					method: tls
 					tlsCertFilePath: "/path/to/cert"
+				retry:
+					attempts: 3
+					perAttemptTimeout: 100ms
+				hedging:
+					delay: 20ms
+				maxConcurrent: 100
+				maxQueue: 1000
 			decision:
+				mode: enforce | shadow | optimistic
 				onTimeout: reject | accept
 				onError: reject | accept
+				onOverload: reject | accept
+				fallbackNeedle: some-local-needle
 			notifyConnClose:
 				- accept
 				- reject
+			closeEvents:
+				queueSize: 1024
+				batchSize: 100
+				flushInterval: 1s
+				onOverflow: dropOldest | dropNewest | block
+				spoolDir: "/var/lib/traefik/needles"
 */
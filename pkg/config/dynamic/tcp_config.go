@@ -9,6 +9,13 @@ import (
 	"github.com/traefik/traefik/v3/pkg/types"
 )
 
+const (
+	// DefaultPassiveHealthCheckMaxDialFailures is the default value for the TCPPassiveHealthCheck maxDialFailures.
+	DefaultPassiveHealthCheckMaxDialFailures = 3
+	// DefaultPassiveHealthCheckEjectionTime is the default value for the TCPPassiveHealthCheck ejectionTime.
+	DefaultPassiveHealthCheckEjectionTime = ptypes.Duration(30 * time.Second)
+)
+
 // +k8s:deepcopy-gen=true
 
 // TCPConfiguration contains all the TCP configuration parameters.
@@ -25,13 +32,45 @@ type TCPConfiguration struct {
 type TCPService struct {
 	LoadBalancer *TCPServersLoadBalancer `json:"loadBalancer,omitempty" toml:"loadBalancer,omitempty" yaml:"loadBalancer,omitempty" export:"true"`
 	Weighted     *TCPWeightedRoundRobin  `json:"weighted,omitempty" toml:"weighted,omitempty" yaml:"weighted,omitempty" label:"-" export:"true"`
+	Failover     *TCPFailover            `json:"failover,omitempty" toml:"failover,omitempty" yaml:"failover,omitempty" label:"-" export:"true"`
+	Mirroring    *TCPMirroring           `json:"mirroring,omitempty" toml:"mirroring,omitempty" yaml:"mirroring,omitempty" label:"-" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
 
 // TCPWeightedRoundRobin is a weighted round robin tcp load-balancer of services.
 type TCPWeightedRoundRobin struct {
-	Services []TCPWRRService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	Services    []TCPWRRService `json:"services,omitempty" toml:"services,omitempty" yaml:"services,omitempty" export:"true"`
+	HealthCheck *HealthCheck    `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPFailover holds the TCP failover service configuration,
+// which forwards the connections to the fallback service while the main service is down.
+type TCPFailover struct {
+	Service     string       `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Fallback    string       `json:"fallback,omitempty" toml:"fallback,omitempty" yaml:"fallback,omitempty" export:"true"`
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPMirroring holds the TCP mirroring service configuration,
+// which copies the bytes sent by the clients to the mirror services, discarding their responses.
+type TCPMirroring struct {
+	Service     string             `json:"service,omitempty" toml:"service,omitempty" yaml:"service,omitempty" export:"true"`
+	Mirrors     []TCPMirrorService `json:"mirrors,omitempty" toml:"mirrors,omitempty" yaml:"mirrors,omitempty" export:"true"`
+	HealthCheck *HealthCheck       `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPMirrorService is a reference to a tcp service the connections are mirrored to.
+type TCPMirrorService struct {
+	Name string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty" export:"true"`
+	// Percent is the percentage of the connections mirrored to the service.
+	Percent int `json:"percent,omitempty" toml:"percent,omitempty" yaml:"percent,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -77,6 +116,16 @@ type TCPServersLoadBalancer struct {
 	ProxyProtocol    *ProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
 	Servers          []TCPServer    `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
 	ServersTransport string         `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	// HealthCheck actively checks the servers, which do not receive connections while down.
+	HealthCheck *TCPServerHealthCheck `json:"healthCheck,omitempty" toml:"healthCheck,omitempty" yaml:"healthCheck,omitempty" export:"true"`
+	// PassiveHealthCheck ejects the servers which cannot be dialed for the connections.
+	PassiveHealthCheck *TCPPassiveHealthCheck `json:"passiveHealthCheck,omitempty" toml:"passiveHealthCheck,omitempty" yaml:"passiveHealthCheck,omitempty" export:"true"`
+	// Retry dials the next server when a server cannot be dialed, before forwarding any byte.
+	Retry *TCPRetry `json:"retry,omitempty" toml:"retry,omitempty" yaml:"retry,omitempty" export:"true"`
+	// Strategy is the way the servers are selected: wrr (default), leastconn, p2c or hash.
+	Strategy string `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty" export:"true"`
+	// HashKey is the key of the connections hashed by the hash strategy: clientIP (default) or sni.
+	HashKey string `json:"hashKey,omitempty" toml:"hashKey,omitempty" yaml:"hashKey,omitempty" export:"true"`
 }
 
 // Mergeable tells if the given service is mergeable.
@@ -98,11 +147,64 @@ func (l *TCPServersLoadBalancer) Mergeable(loadBalancer *TCPServersLoadBalancer)
 
 // +k8s:deepcopy-gen=true
 
+// TCPServerHealthCheck holds the active health check configuration of the TCP servers.
+// A server is up when it can be dialed, through the servers transport and with TLS if the server has it enabled,
+// and when it answers the Send payload with data starting with Expect, if set.
+type TCPServerHealthCheck struct {
+	// Port replaces the port of the servers for the health check.
+	Port     int             `json:"port,omitempty" toml:"port,omitempty,omitzero" yaml:"port,omitempty" export:"true"`
+	Send     string          `json:"send,omitempty" toml:"send,omitempty" yaml:"send,omitempty"`
+	Expect   string          `json:"expect,omitempty" toml:"expect,omitempty" yaml:"expect,omitempty"`
+	Interval ptypes.Duration `json:"interval,omitempty" toml:"interval,omitempty" yaml:"interval,omitempty" export:"true"`
+	Timeout  ptypes.Duration `json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a TCPServerHealthCheck.
+func (h *TCPServerHealthCheck) SetDefaults() {
+	h.Interval = DefaultHealthCheckInterval
+	h.Timeout = DefaultHealthCheckTimeout
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPPassiveHealthCheck holds the passive health check configuration of the TCP servers.
+type TCPPassiveHealthCheck struct {
+	// MaxDialFailures is the number of consecutive dial failures after which the server is ejected.
+	MaxDialFailures int `json:"maxDialFailures,omitempty" toml:"maxDialFailures,omitempty" yaml:"maxDialFailures,omitempty" export:"true"`
+	// EjectionTime is how long the server is ejected for, unless the active health check finds it up before.
+	EjectionTime ptypes.Duration `json:"ejectionTime,omitempty" toml:"ejectionTime,omitempty" yaml:"ejectionTime,omitempty" export:"true"`
+}
+
+// SetDefaults Default values for a TCPPassiveHealthCheck.
+func (h *TCPPassiveHealthCheck) SetDefaults() {
+	h.MaxDialFailures = DefaultPassiveHealthCheckMaxDialFailures
+	h.EjectionTime = DefaultPassiveHealthCheckEjectionTime
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPRetry holds the dial retry configuration of the TCP servers.
+type TCPRetry struct {
+	// Attempts is the maximum number of servers dialed for a connection, the first one included.
+	Attempts int `json:"attempts,omitempty" toml:"attempts,omitempty" yaml:"attempts,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // TCPServer holds a TCP Server configuration.
 type TCPServer struct {
 	Address string `json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty" label:"-"`
 	Port    string `json:"-" toml:"-" yaml:"-"`
 	TLS     bool   `json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty"`
+	// Weight is the weight of the server in the load-balancing, 1 by default.
+	Weight *int `json:"weight,omitempty" toml:"weight,omitempty" yaml:"weight,omitempty" export:"true"`
+	// ProxyProtocol overrides the PROXY protocol configuration of the load-balancer for the server,
+	// the version 0 disabling the PROXY protocol.
+	ProxyProtocol *ProxyProtocol `json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// ServersTransport overrides the servers transport of the load-balancer for the server.
+	ServersTransport string `json:"serversTransport,omitempty" toml:"serversTransport,omitempty" yaml:"serversTransport,omitempty" export:"true"`
+	// TLSServerName is the server name sent to the server, and against which its certificate is verified, when TLS is enabled.
+	TLSServerName string `json:"tlsServerName,omitempty" toml:"tlsServerName,omitempty" yaml:"tlsServerName,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -132,6 +234,11 @@ type TCPServersTransport struct {
 	// means an infinite deadline (i.e. the reading capability is never closed).
 	TerminationDelay ptypes.Duration  `description:"Defines the delay to wait before fully terminating the connection, after one connected peer has closed its writing capability." json:"terminationDelay,omitempty" toml:"terminationDelay,omitempty" yaml:"terminationDelay,omitempty" export:"true"`
 	TLS              *TLSClientConfig `description:"Defines the TLS configuration." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// IdleTimeout is the maximum duration a connection can stay without any data transferred in either direction,
+	// before being closed. If zero, no idle timeout exists.
+	IdleTimeout ptypes.Duration `description:"Defines the maximum duration a connection can stay without any data transferred in either direction, before being closed. If zero, no idle timeout exists." json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
+	// MaxLifetime is the maximum duration of a connection, after which it is closed. If zero, no maximum lifetime exists.
+	MaxLifetime ptypes.Duration `description:"Defines the maximum duration of a connection, after which it is closed. If zero, no maximum lifetime exists." json:"maxLifetime,omitempty" toml:"maxLifetime,omitempty" yaml:"maxLifetime,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -1,5 +1,17 @@
 package dynamic
 
+import (
+	"time"
+
+	ptypes "github.com/traefik/paerser/types"
+)
+
+// Behaviors of the TCP RateLimit middleware on the connections exceeding the rate.
+const (
+	TCPRateLimitOnExceedClose = "close"
+	TCPRateLimitOnExceedDelay = "delay"
+)
+
 // +k8s:deepcopy-gen=true
 
 // TCPMiddleware holds the TCPMiddleware configuration.
@@ -7,6 +19,7 @@ type TCPMiddleware struct {
 	InFlightConn *TCPInFlightConn `json:"inFlightConn,omitempty" toml:"inFlightConn,omitempty" yaml:"inFlightConn,omitempty" export:"true"`
 	IPAllowList  *TCPIPAllowList  `json:"ipAllowList,omitempty" toml:"ipAllowList,omitempty" yaml:"ipAllowList,omitempty" export:"true"`
 	Needle       *TCPNeedle       `json:"needle,omitempty" toml:"needle,omitempty" yaml:"needle,omitempty" export:"true"`
+	RateLimit    *TCPRateLimit    `json:"rateLimit,omitempty" toml:"rateLimit,omitempty" yaml:"rateLimit,omitempty" export:"true"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -23,6 +36,55 @@ type TCPInFlightConn struct {
 
 // +k8s:deepcopy-gen=true
 
+// TCPRateLimit holds the TCP RateLimit middleware configuration.
+// This middleware limits the rate of the new connections for one source,
+// e.g. to stop connection floods.
+type TCPRateLimit struct {
+	// Average is the maximum rate, by default in connections/s, allowed for the given source.
+	// It defaults to 0, which means no rate limiting.
+	// The rate is actually defined by dividing Average by Period. So for a rate below 1conn/s,
+	// one needs to define a Period larger than a second.
+	Average int64 `json:"average,omitempty" toml:"average,omitempty" yaml:"average,omitempty" export:"true"`
+	// Period, in combination with Average, defines the actual maximum rate, such as:
+	// r = Average / Period. It defaults to a second.
+	Period ptypes.Duration `json:"period,omitempty" toml:"period,omitempty" yaml:"period,omitempty" export:"true"`
+	// Burst is the maximum number of connections allowed to arrive in the same arbitrarily small period of time.
+	// It defaults to 1.
+	Burst int64 `json:"burst,omitempty" toml:"burst,omitempty" yaml:"burst,omitempty" export:"true"`
+	// SourceCriterion defines what criterion is used to group connections as originating from a common source.
+	// If not set, the default is to use the client IP.
+	SourceCriterion *TCPSourceCriterion `json:"sourceCriterion,omitempty" toml:"sourceCriterion,omitempty" yaml:"sourceCriterion,omitempty" export:"true"`
+	// OnExceed defines what happens to the connections exceeding the rate:
+	// close (default) closes them, and delay holds them until they are within the rate.
+	OnExceed string `json:"onExceed,omitempty" toml:"onExceed,omitempty" yaml:"onExceed,omitempty" export:"true"`
+	// MaxDelay is the maximum duration a connection is held with the delay behavior,
+	// the connections which would be held longer being closed. It defaults to a second.
+	MaxDelay ptypes.Duration `json:"maxDelay,omitempty" toml:"maxDelay,omitempty" yaml:"maxDelay,omitempty" export:"true"`
+}
+
+// SetDefaults sets the default values on a TCPRateLimit.
+func (r *TCPRateLimit) SetDefaults() {
+	r.Burst = 1
+	r.Period = ptypes.Duration(time.Second)
+	r.OnExceed = TCPRateLimitOnExceedClose
+	r.MaxDelay = ptypes.Duration(time.Second)
+}
+
+// +k8s:deepcopy-gen=true
+
+// TCPSourceCriterion defines the criterion used to group TCP connections as originating from a common source,
+// which is the client IP, possibly aggregated by network.
+type TCPSourceCriterion struct {
+	// IPv4Mask is the prefix length of the networks the IPv4 client addresses are aggregated by, e.g. 24.
+	// It defaults to 0, which means no aggregation.
+	IPv4Mask int `json:"ipv4Mask,omitempty" toml:"ipv4Mask,omitempty" yaml:"ipv4Mask,omitempty" export:"true"`
+	// IPv6Mask is the prefix length of the networks the IPv6 client addresses are aggregated by, e.g. 64.
+	// It defaults to 0, which means no aggregation.
+	IPv6Mask int `json:"ipv6Mask,omitempty" toml:"ipv6Mask,omitempty" yaml:"ipv6Mask,omitempty" export:"true"`
+}
+
+// +k8s:deepcopy-gen=true
+
 // TCPIPAllowList holds the TCP IPAllowList middleware configuration.
 // This middleware accepts/refuses connections based on the client IP.
 type TCPIPAllowList struct {
@@ -33,6 +95,16 @@ type TCPIPAllowList struct {
 // +k8s:deepcopy-gen=true
 
 type TCPNeedle struct {
-	Id       string            `json:"id,omitempty" toml:"id,omitempty" yaml:"id,omitempty"`
+	Id string `json:"id,omitempty" toml:"id,omitempty" yaml:"id,omitempty"`
+	// Metadata is sent to the decision service along with the connections.
+	// The values are Go templates, with the sprig functions but env, expandenv and getHostByName, rendered for each connection with its
+	// Protocol, ClientIP, ClientPort, LocalIP, LocalPort, SNI, EntryPoint, Router and Service,
+	// and the TLV function returning the value of a PROXY protocol TLV, e.g. {{ .TLV 0xE0 }}.
 	Metadata map[string]string `json:"metadata,omitempty" toml:"metadata,omitempty" yaml:"metadata,omitempty"`
+	// PeekBytes defines the maximum number of bytes read from the client before asking for a decision.
+	// The peeked bytes are sent to the decision service, then replayed to the backend.
+	PeekBytes int `json:"peekBytes,omitempty" toml:"peekBytes,omitempty" yaml:"peekBytes,omitempty" export:"true"`
+	// PeekTimeout defines how long to wait for the client to send its first bytes.
+	// The decision is asked as soon as some bytes are received, or without any once elapsed.
+	PeekTimeout ptypes.Duration `json:"peekTimeout,omitempty" toml:"peekTimeout,omitempty" yaml:"peekTimeout,omitempty" export:"true"`
 }
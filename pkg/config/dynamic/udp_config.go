@@ -55,6 +55,8 @@ type UDPRouter struct {
 // UDPServersLoadBalancer defines the configuration for a load-balancer of UDP servers.
 type UDPServersLoadBalancer struct {
 	Servers []UDPServer `json:"servers,omitempty" toml:"servers,omitempty" yaml:"servers,omitempty" label-slice-as-struct:"server" export:"true"`
+	// Strategy is the way the servers are selected: wrr (default), leastconn, p2c or hash.
+	Strategy string `json:"strategy,omitempty" toml:"strategy,omitempty" yaml:"strategy,omitempty" export:"true"`
 }
 
 // Mergeable reports whether the given load-balancer can be merged with the receiver.
@@ -85,6 +87,10 @@ type UDPServer struct {
 // +k8s:deepcopy-gen=true
 
 type UDPNeedle struct {
-	Id       string            `json:"id,omitempty" toml:"id,omitempty" yaml:"id,omitempty"`
+	Id string `json:"id,omitempty" toml:"id,omitempty" yaml:"id,omitempty"`
+	// Metadata is sent to the decision service along with the connections.
+	// The values are Go templates, with the sprig functions but env, expandenv and getHostByName, rendered for each connection with its
+	// Protocol, ClientIP, ClientPort, LocalIP, LocalPort, SNI, EntryPoint, Router and Service,
+	// and the TLV function returning the value of a PROXY protocol TLV, e.g. {{ .TLV 0xE0 }}.
 	Metadata map[string]string `json:"metadata,omitempty" toml:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
@@ -876,6 +876,11 @@ func (in *Needle) DeepCopyInto(out *Needle) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CloseEvents != nil {
+		in, out := &in.CloseEvents, &out.CloseEvents
+		*out = new(NeedleCloseEvents)
+		**out = **in
+	}
 	return
 }
 
@@ -913,6 +918,16 @@ func (in *NeedleClient) DeepCopyInto(out *NeedleClient) {
 		*out = new(NeedleAuth)
 		**out = **in
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(NeedleRetry)
+		**out = **in
+	}
+	if in.Hedging != nil {
+		in, out := &in.Hedging, &out.Hedging
+		*out = new(NeedleHedging)
+		**out = **in
+	}
 	return
 }
 
@@ -926,6 +941,22 @@ func (in *NeedleClient) DeepCopy() *NeedleClient {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NeedleCloseEvents) DeepCopyInto(out *NeedleCloseEvents) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NeedleCloseEvents.
+func (in *NeedleCloseEvents) DeepCopy() *NeedleCloseEvents {
+	if in == nil {
+		return nil
+	}
+	out := new(NeedleCloseEvents)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NeedleDecision) DeepCopyInto(out *NeedleDecision) {
 	*out = *in
@@ -942,6 +973,38 @@ func (in *NeedleDecision) DeepCopy() *NeedleDecision {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NeedleHedging) DeepCopyInto(out *NeedleHedging) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NeedleHedging.
+func (in *NeedleHedging) DeepCopy() *NeedleHedging {
+	if in == nil {
+		return nil
+	}
+	out := new(NeedleHedging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NeedleRetry) DeepCopyInto(out *NeedleRetry) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NeedleRetry.
+func (in *NeedleRetry) DeepCopy() *NeedleRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(NeedleRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Needleware) DeepCopyInto(out *Needleware) {
 	*out = *in
@@ -1542,6 +1605,27 @@ func (in *TCPConfiguration) DeepCopy() *TCPConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPFailover) DeepCopyInto(out *TCPFailover) {
+	*out = *in
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPFailover.
+func (in *TCPFailover) DeepCopy() *TCPFailover {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPFailover)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPIPAllowList) DeepCopyInto(out *TCPIPAllowList) {
 	*out = *in
@@ -1597,6 +1681,11 @@ func (in *TCPMiddleware) DeepCopyInto(out *TCPMiddleware) {
 		*out = new(TCPNeedle)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(TCPRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1610,6 +1699,48 @@ func (in *TCPMiddleware) DeepCopy() *TCPMiddleware {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPMirrorService) DeepCopyInto(out *TCPMirrorService) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPMirrorService.
+func (in *TCPMirrorService) DeepCopy() *TCPMirrorService {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPMirrorService)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPMirroring) DeepCopyInto(out *TCPMirroring) {
+	*out = *in
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]TCPMirrorService, len(*in))
+		copy(*out, *in)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPMirroring.
+func (in *TCPMirroring) DeepCopy() *TCPMirroring {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPMirroring)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPNeedle) DeepCopyInto(out *TCPNeedle) {
 	*out = *in
@@ -1633,6 +1764,59 @@ func (in *TCPNeedle) DeepCopy() *TCPNeedle {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPPassiveHealthCheck) DeepCopyInto(out *TCPPassiveHealthCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPPassiveHealthCheck.
+func (in *TCPPassiveHealthCheck) DeepCopy() *TCPPassiveHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPPassiveHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPRateLimit) DeepCopyInto(out *TCPRateLimit) {
+	*out = *in
+	if in.SourceCriterion != nil {
+		in, out := &in.SourceCriterion, &out.SourceCriterion
+		*out = new(TCPSourceCriterion)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPRateLimit.
+func (in *TCPRateLimit) DeepCopy() *TCPRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPRetry) DeepCopyInto(out *TCPRetry) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPRetry.
+func (in *TCPRetry) DeepCopy() *TCPRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPRouter) DeepCopyInto(out *TCPRouter) {
 	*out = *in
@@ -1667,6 +1851,16 @@ func (in *TCPRouter) DeepCopy() *TCPRouter {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPServer) DeepCopyInto(out *TCPServer) {
 	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
+	}
+	if in.ProxyProtocol != nil {
+		in, out := &in.ProxyProtocol, &out.ProxyProtocol
+		*out = new(ProxyProtocol)
+		**out = **in
+	}
 	return
 }
 
@@ -1680,6 +1874,22 @@ func (in *TCPServer) DeepCopy() *TCPServer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPServerHealthCheck) DeepCopyInto(out *TCPServerHealthCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPServerHealthCheck.
+func (in *TCPServerHealthCheck) DeepCopy() *TCPServerHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPServerHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPServersLoadBalancer) DeepCopyInto(out *TCPServersLoadBalancer) {
 	*out = *in
@@ -1691,7 +1901,24 @@ func (in *TCPServersLoadBalancer) DeepCopyInto(out *TCPServersLoadBalancer) {
 	if in.Servers != nil {
 		in, out := &in.Servers, &out.Servers
 		*out = make([]TCPServer, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(TCPServerHealthCheck)
+		**out = **in
+	}
+	if in.PassiveHealthCheck != nil {
+		in, out := &in.PassiveHealthCheck, &out.PassiveHealthCheck
+		*out = new(TCPPassiveHealthCheck)
+		**out = **in
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(TCPRetry)
+		**out = **in
 	}
 	return
 }
@@ -1740,6 +1967,16 @@ func (in *TCPService) DeepCopyInto(out *TCPService) {
 		*out = new(TCPWeightedRoundRobin)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Failover != nil {
+		in, out := &in.Failover, &out.Failover
+		*out = new(TCPFailover)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mirroring != nil {
+		in, out := &in.Mirroring, &out.Mirroring
+		*out = new(TCPMirroring)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1753,6 +1990,22 @@ func (in *TCPService) DeepCopy() *TCPService {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TCPSourceCriterion) DeepCopyInto(out *TCPSourceCriterion) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TCPSourceCriterion.
+func (in *TCPSourceCriterion) DeepCopy() *TCPSourceCriterion {
+	if in == nil {
+		return nil
+	}
+	out := new(TCPSourceCriterion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPWRRService) DeepCopyInto(out *TCPWRRService) {
 	*out = *in
@@ -1784,6 +2037,11 @@ func (in *TCPWeightedRoundRobin) DeepCopyInto(out *TCPWeightedRoundRobin) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		**out = **in
+	}
 	return
 }
 
@@ -193,6 +193,8 @@ func TestDecodeConfiguration(t *testing.T) {
 		"traefik.tcp.routers.Router1.tls.options":                          "foo",
 		"traefik.tcp.routers.Router1.tls.passthrough":                      "false",
 		"traefik.tcp.services.Service0.loadbalancer.server.Port":           "42",
+		"traefik.tcp.services.Service0.loadbalancer.server.Weight":         "42",
+		"traefik.tcp.services.Service0.loadbalancer.server.TLSServerName":  "foo",
 		"traefik.tcp.services.Service0.loadbalancer.proxyProtocol.version": "42",
 		"traefik.tcp.services.Service0.loadbalancer.serversTransport":      "foo",
 		"traefik.tcp.services.Service1.loadbalancer.server.Port":           "42",
@@ -257,7 +259,9 @@ func TestDecodeConfiguration(t *testing.T) {
 					LoadBalancer: &dynamic.TCPServersLoadBalancer{
 						Servers: []dynamic.TCPServer{
 							{
-								Port: "42",
+								Port:          "42",
+								Weight:        func(i int) *int { return &i }(42),
+								TLSServerName: "foo",
 							},
 						},
 						ProxyProtocol:    &dynamic.ProxyProtocol{Version: 42},
@@ -752,7 +756,9 @@ func TestEncodeConfiguration(t *testing.T) {
 					LoadBalancer: &dynamic.TCPServersLoadBalancer{
 						Servers: []dynamic.TCPServer{
 							{
-								Port: "42",
+								Port:          "42",
+								Weight:        func(i int) *int { return &i }(42),
+								TLSServerName: "foo",
 							},
 						},
 						ServersTransport: "foo",
@@ -1350,26 +1356,28 @@ func TestEncodeConfiguration(t *testing.T) {
 		"traefik.HTTP.Services.Service1.LoadBalancer.server.Scheme":                    "foobar",
 		"traefik.HTTP.Services.Service1.LoadBalancer.ServersTransport":                 "foobar",
 
-		"traefik.TCP.Middlewares.Middleware0.IPAllowList.SourceRange": "foobar, fiibar",
-		"traefik.TCP.Middlewares.Middleware2.InFlightConn.Amount":     "42",
-		"traefik.TCP.Routers.Router0.Rule":                            "foobar",
-		"traefik.TCP.Routers.Router0.Priority":                        "42",
-		"traefik.TCP.Routers.Router0.EntryPoints":                     "foobar, fiibar",
-		"traefik.TCP.Routers.Router0.Service":                         "foobar",
-		"traefik.TCP.Routers.Router0.TLS.Passthrough":                 "false",
-		"traefik.TCP.Routers.Router0.TLS.Options":                     "foo",
-		"traefik.TCP.Routers.Router1.Rule":                            "foobar",
-		"traefik.TCP.Routers.Router1.Priority":                        "42",
-		"traefik.TCP.Routers.Router1.EntryPoints":                     "foobar, fiibar",
-		"traefik.TCP.Routers.Router1.Service":                         "foobar",
-		"traefik.TCP.Routers.Router1.TLS.Passthrough":                 "false",
-		"traefik.TCP.Routers.Router1.TLS.Options":                     "foo",
-		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":      "42",
-		"traefik.TCP.Services.Service0.LoadBalancer.server.TLS":       "false",
-		"traefik.TCP.Services.Service0.LoadBalancer.ServersTransport": "foo",
-		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":      "42",
-		"traefik.TCP.Services.Service1.LoadBalancer.server.TLS":       "false",
-		"traefik.TCP.Services.Service1.LoadBalancer.ServersTransport": "foo",
+		"traefik.TCP.Middlewares.Middleware0.IPAllowList.SourceRange":     "foobar, fiibar",
+		"traefik.TCP.Middlewares.Middleware2.InFlightConn.Amount":         "42",
+		"traefik.TCP.Routers.Router0.Rule":                                "foobar",
+		"traefik.TCP.Routers.Router0.Priority":                            "42",
+		"traefik.TCP.Routers.Router0.EntryPoints":                         "foobar, fiibar",
+		"traefik.TCP.Routers.Router0.Service":                             "foobar",
+		"traefik.TCP.Routers.Router0.TLS.Passthrough":                     "false",
+		"traefik.TCP.Routers.Router0.TLS.Options":                         "foo",
+		"traefik.TCP.Routers.Router1.Rule":                                "foobar",
+		"traefik.TCP.Routers.Router1.Priority":                            "42",
+		"traefik.TCP.Routers.Router1.EntryPoints":                         "foobar, fiibar",
+		"traefik.TCP.Routers.Router1.Service":                             "foobar",
+		"traefik.TCP.Routers.Router1.TLS.Passthrough":                     "false",
+		"traefik.TCP.Routers.Router1.TLS.Options":                         "foo",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.Port":          "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.TLS":           "false",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.TLSServerName": "foo",
+		"traefik.TCP.Services.Service0.LoadBalancer.server.Weight":        "42",
+		"traefik.TCP.Services.Service0.LoadBalancer.ServersTransport":     "foo",
+		"traefik.TCP.Services.Service1.LoadBalancer.server.Port":          "42",
+		"traefik.TCP.Services.Service1.LoadBalancer.server.TLS":           "false",
+		"traefik.TCP.Services.Service1.LoadBalancer.ServersTransport":     "foo",
 
 		"traefik.UDP.Routers.Router0.EntryPoints":                "foobar, fiibar",
 		"traefik.UDP.Routers.Router0.Service":                    "foobar",
@@ -3,6 +3,8 @@ package static
 import (
 	"fmt"
 	"math"
+	"net"
+	"strconv"
 	"strings"
 
 	ptypes "github.com/traefik/paerser/types"
@@ -11,7 +13,7 @@ import (
 
 // EntryPoint holds the entry point configuration.
 type EntryPoint struct {
-	Address          string                `description:"Entry point address." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
+	Address          string                `description:"Entry point address, or comma-separated list of addresses, whose port can be a range (e.g. :10000-20000/udp)." json:"address,omitempty" toml:"address,omitempty" yaml:"address,omitempty"`
 	AsDefault        bool                  `description:"Adds this EntryPoint to the list of default EntryPoints to be used on routers that don't have any Entrypoint defined." json:"asDefault,omitempty" toml:"asDefault,omitempty" yaml:"asDefault,omitempty"`
 	Transport        *EntryPointsTransport `description:"Configures communication between clients and Traefik." json:"transport,omitempty" toml:"transport,omitempty" yaml:"transport,omitempty" export:"true"`
 	ProxyProtocol    *ProxyProtocol        `description:"Proxy-Protocol configuration." json:"proxyProtocol,omitempty" toml:"proxyProtocol,omitempty" yaml:"proxyProtocol,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
@@ -20,6 +22,8 @@ type EntryPoint struct {
 	HTTP2            *HTTP2Config          `description:"HTTP/2 configuration." json:"http2,omitempty" toml:"http2,omitempty" yaml:"http2,omitempty" export:"true"`
 	HTTP3            *HTTP3Config          `description:"HTTP/3 configuration." json:"http3,omitempty" toml:"http3,omitempty" yaml:"http3,omitempty" label:"allowEmpty" file:"allowEmpty" export:"true"`
 	UDP              *UDPConfig            `description:"UDP configuration." json:"udp,omitempty" toml:"udp,omitempty" yaml:"udp,omitempty"`
+	Needle           *EntryPointNeedle     `description:"Needle deciding on every connection accepted by the entry point, before TLS and routing." json:"needle,omitempty" toml:"needle,omitempty" yaml:"needle,omitempty" export:"true"`
+	StartTLS         []string              `description:"Protocols whose STARTTLS negotiation is handled by the entry point, to route their connections on the TLS ClientHello (postgres, smtp, imap, pop3, xmpp, mysql). Defaults to postgres." json:"startTLS,omitempty" toml:"startTLS,omitempty" yaml:"startTLS,omitempty" export:"true"`
 }
 
 // GetAddress strips any potential protocol part of the address field of the
@@ -29,6 +33,57 @@ func (ep EntryPoint) GetAddress() string {
 	return splitN[0]
 }
 
+// GetAddresses returns the addresses the entry point listens on,
+// from the comma-separated list of addresses of its address field, whose port ranges are expanded.
+func (ep EntryPoint) GetAddresses() ([]string, error) {
+	var addresses []string
+	for _, address := range strings.Split(ep.GetAddress(), ",") {
+		address = strings.TrimSpace(address)
+
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", address, err)
+		}
+
+		first, last, isRange := strings.Cut(port, "-")
+		if !isRange {
+			addresses = append(addresses, address)
+			continue
+		}
+
+		firstPort, err := strconv.ParseUint(first, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port range %q: %w", port, err)
+		}
+
+		lastPort, err := strconv.ParseUint(last, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port range %q: %w", port, err)
+		}
+
+		if firstPort == 0 || firstPort > lastPort {
+			return nil, fmt.Errorf("invalid port range %q", port)
+		}
+
+		for p := firstPort; p <= lastPort; p++ {
+			addresses = append(addresses, net.JoinHostPort(host, strconv.FormatUint(p, 10)))
+		}
+	}
+
+	return addresses, nil
+}
+
+// GetFirstAddress returns the first of the addresses the entry point listens on,
+// for the usages which need a single address, such as the port of a redirection.
+func (ep EntryPoint) GetFirstAddress() (string, error) {
+	addresses, err := ep.GetAddresses()
+	if err != nil {
+		return "", err
+	}
+
+	return addresses[0], nil
+}
+
 // GetProtocol returns the protocol part of the address field of the entry point.
 // If none is specified, it defaults to "tcp".
 func (ep EntryPoint) GetProtocol() (string, error) {
@@ -135,6 +190,12 @@ func (t *EntryPointsTransport) SetDefaults() {
 	t.RespondingTimeouts.SetDefaults()
 }
 
+// EntryPointNeedle references a needle of the dynamic configuration.
+type EntryPointNeedle struct {
+	ID       string            `description:"Qualified name of the needle (e.g. foo@file)." json:"id,omitempty" toml:"id,omitempty" yaml:"id,omitempty" export:"true"`
+	Metadata map[string]string `description:"Metadata sent to the decision service along with the connection, the values being Go templates rendered for each connection." json:"metadata,omitempty" toml:"metadata,omitempty" yaml:"metadata,omitempty" export:"true"`
+}
+
 // UDPConfig is the UDP configuration of an entry point.
 type UDPConfig struct {
 	Timeout ptypes.Duration `description:"Timeout defines how long to wait on an idle session before releasing the related resources." json:"timeout,omitempty" toml:"timeout,omitempty" yaml:"timeout,omitempty"`
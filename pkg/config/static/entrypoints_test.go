@@ -65,3 +65,106 @@ func TestEntryPointProtocol(t *testing.T) {
 		})
 	}
 }
+
+func TestEntryPointAddresses(t *testing.T) {
+	tests := []struct {
+		name              string
+		address           string
+		expectedAddresses []string
+		expectedError     bool
+	}{
+		{
+			name:              "Single address",
+			address:           "127.0.0.1:8080",
+			expectedAddresses: []string{"127.0.0.1:8080"},
+		},
+		{
+			name:              "Port range with protocol",
+			address:           ":10000-10002/udp",
+			expectedAddresses: []string{":10000", ":10001", ":10002"},
+		},
+		{
+			name:              "List of addresses",
+			address:           "127.0.0.1:8080, [::1]:9000-9001",
+			expectedAddresses: []string{"127.0.0.1:8080", "[::1]:9000", "[::1]:9001"},
+		},
+		{
+			name:          "Missing port",
+			address:       "127.0.0.1",
+			expectedError: true,
+		},
+		{
+			name:          "Invalid port range",
+			address:       ":10002-10000",
+			expectedError: true,
+		},
+		{
+			name:          "Port range out of bounds",
+			address:       ":65000-65536",
+			expectedError: true,
+		},
+		{
+			name:          "Port range from zero",
+			address:       ":0-10",
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := EntryPoint{
+				Address: tt.address,
+			}
+			addresses, err := ep.GetAddresses()
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedAddresses, addresses)
+		})
+	}
+}
+
+func TestEntryPointFirstAddress(t *testing.T) {
+	tests := []struct {
+		name            string
+		address         string
+		expectedAddress string
+		expectedError   bool
+	}{
+		{
+			name:            "Single address",
+			address:         "127.0.0.1:8080/tcp",
+			expectedAddress: "127.0.0.1:8080",
+		},
+		{
+			name:            "Port range",
+			address:         ":10000-10002/udp",
+			expectedAddress: ":10000",
+		},
+		{
+			name:            "List of addresses",
+			address:         "[::1]:9000-9001, 127.0.0.1:8080",
+			expectedAddress: "[::1]:9000",
+		},
+		{
+			name:          "Invalid address",
+			address:       "127.0.0.1:8080,127.0.0.1",
+			expectedError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ep := EntryPoint{
+				Address: tt.address,
+			}
+			address, err := ep.GetFirstAddress()
+			if tt.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedAddress, address)
+		})
+	}
+}
@@ -125,6 +125,11 @@ type TCPServersTransport struct {
 	// means an infinite deadline (i.e. the reading capability is never closed).
 	TerminationDelay ptypes.Duration  `description:"Defines the delay to wait before fully terminating the connection, after one connected peer has closed its writing capability." json:"terminationDelay,omitempty" toml:"terminationDelay,omitempty" yaml:"terminationDelay,omitempty" export:"true"`
 	TLS              *TLSClientConfig `description:"Defines the TLS configuration." json:"tls,omitempty" toml:"tls,omitempty" yaml:"tls,omitempty" label:"allowEmpty" file:"allowEmpty" kv:"allowEmpty" export:"true"`
+	// IdleTimeout is the maximum duration a connection can stay without any data transferred in either direction,
+	// before being closed. If zero, no idle timeout exists.
+	IdleTimeout ptypes.Duration `description:"Defines the maximum duration a connection can stay without any data transferred in either direction, before being closed. If zero, no idle timeout exists." json:"idleTimeout,omitempty" toml:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" export:"true"`
+	// MaxLifetime is the maximum duration of a connection, after which it is closed. If zero, no maximum lifetime exists.
+	MaxLifetime ptypes.Duration `description:"Defines the maximum duration of a connection, after which it is closed. If zero, no maximum lifetime exists." json:"maxLifetime,omitempty" toml:"maxLifetime,omitempty" yaml:"maxLifetime,omitempty" export:"true"`
 }
 
 // TLSClientConfig options to configure TLS communication between Traefik and the servers.
@@ -280,7 +285,13 @@ func (c *Configuration) SetEffectiveConfiguration() {
 		log.Debug().Msg("Experimental Kubernetes Gateway provider has been activated")
 		entryPoints := make(map[string]gateway.Entrypoint)
 		for epName, entryPoint := range c.EntryPoints {
-			entryPoints[epName] = gateway.Entrypoint{Address: entryPoint.GetAddress(), HasHTTPTLSConf: entryPoint.HTTP.TLS != nil}
+			address, err := entryPoint.GetFirstAddress()
+			if err != nil {
+				log.Error().Err(err).Str(logs.EntryPointName, epName).Msg("Cannot get the entry point address for the Kubernetes Gateway provider")
+				continue
+			}
+
+			entryPoints[epName] = gateway.Entrypoint{Address: address, HasHTTPTLSConf: entryPoint.HTTP.TLS != nil}
 		}
 
 		c.Providers.KubernetesGateway.EntryPoints = entryPoints
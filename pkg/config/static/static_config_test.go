@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/traefik/traefik/v3/pkg/provider/kubernetes/gateway"
 )
 
 func TestHasEntrypoint(t *testing.T) {
@@ -38,3 +39,29 @@ func TestHasEntrypoint(t *testing.T) {
 		})
 	}
 }
+
+func TestSetEffectiveConfiguration_gatewayEntryPoints(t *testing.T) {
+	cfg := &Configuration{
+		EntryPoints: map[string]*EntryPoint{
+			"web":     {Address: ":80"},
+			"range":   {Address: ":8000-8002/tcp"},
+			"list":    {Address: "127.0.0.1:9000,[::1]:9001"},
+			"invalid": {Address: "127.0.0.1"},
+		},
+		Providers: &Providers{
+			KubernetesGateway: &gateway.Provider{},
+		},
+		Experimental: &Experimental{
+			KubernetesGateway: true,
+		},
+	}
+
+	cfg.SetEffectiveConfiguration()
+
+	expected := map[string]gateway.Entrypoint{
+		"web":   {Address: ":80"},
+		"range": {Address: ":8000"},
+		"list":  {Address: "127.0.0.1:9000"},
+	}
+	assert.Equal(t, expected, cfg.Providers.KubernetesGateway.EntryPoints)
+}
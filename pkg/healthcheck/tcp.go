@@ -0,0 +1,284 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+	"golang.org/x/net/proxy"
+)
+
+// tcpServerStatus reports the status of the servers of a TCP service,
+// to the load-balancer, the runtime configuration and the metrics.
+type tcpServerStatus struct {
+	balancer    StatusSetter
+	info        *runtime.TCPServiceInfo
+	metrics     metricsHealthCheck
+	serviceName string
+}
+
+func (s tcpServerStatus) set(ctx context.Context, address string, up bool) {
+	s.balancer.SetStatus(ctx, address, up)
+
+	statusStr := runtime.StatusDown
+	serverUpMetricValue := float64(0)
+	if up {
+		statusStr = runtime.StatusUp
+		serverUpMetricValue = 1
+	}
+
+	s.info.UpdateServerStatus(address, statusStr)
+
+	s.metrics.ServiceServerUpGauge().
+		With("service", s.serviceName, "url", address).
+		Set(serverUpMetricValue)
+}
+
+// TCPTarget identifies a server checked by the TCP health checker.
+// As the servers sharing an address can be dialed differently, they are told apart by how they are dialed.
+type TCPTarget struct {
+	Address          string
+	ServersTransport string
+	TLS              bool
+	TLSServerName    string
+}
+
+// ServiceTCPHealthChecker actively checks the servers of a TCP service.
+type ServiceTCPHealthChecker struct {
+	status tcpServerStatus
+
+	config   *dynamic.TCPServerHealthCheck
+	interval time.Duration
+	timeout  time.Duration
+
+	// targets are the dialers of the servers.
+	targets map[TCPTarget]tcp.Dialer
+}
+
+func NewServiceTCPHealthChecker(ctx context.Context, metrics metricsHealthCheck, config *dynamic.TCPServerHealthCheck, service StatusSetter, info *runtime.TCPServiceInfo, serviceName string, targets map[TCPTarget]tcp.Dialer) *ServiceTCPHealthChecker {
+	logger := log.Ctx(ctx)
+
+	interval := time.Duration(config.Interval)
+	if interval <= 0 {
+		logger.Error().Msg("Health check interval smaller than zero")
+		interval = time.Duration(dynamic.DefaultHealthCheckInterval)
+	}
+
+	timeout := time.Duration(config.Timeout)
+	if timeout <= 0 {
+		logger.Error().Msg("Health check timeout smaller than zero")
+		timeout = time.Duration(dynamic.DefaultHealthCheckTimeout)
+	}
+
+	if timeout >= interval {
+		interval = timeout + time.Second
+		logger.Warn().Msgf("TCP health check timeout (%s) should be lower than the interval, the interval is set to %s", timeout, interval)
+	}
+
+	return &ServiceTCPHealthChecker{
+		status: tcpServerStatus{
+			balancer:    service,
+			info:        info,
+			metrics:     metrics,
+			serviceName: serviceName,
+		},
+		config:   config,
+		interval: interval,
+		timeout:  timeout,
+		targets:  targets,
+	}
+}
+
+func (shc *ServiceTCPHealthChecker) Launch(ctx context.Context) {
+	ticker := time.NewTicker(shc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			// The status being shared by the servers with the same address,
+			// an address is up only if it passes the health check with all the ways it is dialed.
+			statuses := make(map[string]bool)
+			for target, dialer := range shc.targets {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+
+				up := true
+				if err := shc.executeHealthCheck(ctx, target.Address, dialer); err != nil {
+					// The context is canceled when the dynamic configuration is refreshed.
+					if errors.Is(err, context.Canceled) {
+						return
+					}
+
+					log.Ctx(ctx).Warn().
+						Str("serverAddress", target.Address).
+						Err(err).
+						Msg("Health check failed.")
+
+					up = false
+				}
+
+				if previous, ok := statuses[target.Address]; ok {
+					up = up && previous
+				}
+				statuses[target.Address] = up
+			}
+
+			for address, up := range statuses {
+				shc.status.set(ctx, address, up)
+			}
+		}
+	}
+}
+
+// executeHealthCheck returns an error with a meaningful description if the health check failed.
+func (shc *ServiceTCPHealthChecker) executeHealthCheck(ctx context.Context, address string, dialer tcp.Dialer) error {
+	ctx, cancel := context.WithTimeout(ctx, shc.timeout)
+	defer cancel()
+
+	if shc.config.Port != 0 {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return fmt.Errorf("parse server address: %w", err)
+		}
+		address = net.JoinHostPort(host, strconv.Itoa(shc.config.Port))
+	}
+
+	// The TLS dialers complete the handshake before returning the connection.
+	conn, err := dialContext(ctx, dialer, address)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", address, err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("set deadline: %w", err)
+		}
+	}
+
+	if shc.config.Send != "" {
+		if _, err := io.WriteString(conn, shc.config.Send); err != nil {
+			return fmt.Errorf("send payload: %w", err)
+		}
+	}
+
+	if shc.config.Expect != "" {
+		received := make([]byte, len(shc.config.Expect))
+		if _, err := io.ReadFull(conn, received); err != nil {
+			return fmt.Errorf("read expected payload: %w", err)
+		}
+		if !bytes.Equal(received, []byte(shc.config.Expect)) {
+			return fmt.Errorf("received %q, expected %q", received, shc.config.Expect)
+		}
+	}
+
+	return nil
+}
+
+func dialContext(ctx context.Context, dialer tcp.Dialer, address string) (net.Conn, error) {
+	if contextDialer, ok := dialer.(proxy.ContextDialer); ok {
+		return contextDialer.DialContext(ctx, "tcp", address)
+	}
+	return dialer.Dial("tcp", address)
+}
+
+// TCPPassiveHealthChecker ejects the servers of a TCP service after consecutive dial failures.
+type TCPPassiveHealthChecker struct {
+	// ctx is canceled when the dynamic configuration is refreshed, after which no server is brought back.
+	ctx    context.Context
+	status tcpServerStatus
+
+	maxDialFailures int
+	ejectionTime    time.Duration
+
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+func NewTCPPassiveHealthChecker(ctx context.Context, metrics metricsHealthCheck, config *dynamic.TCPPassiveHealthCheck, service StatusSetter, info *runtime.TCPServiceInfo, serviceName string) *TCPPassiveHealthChecker {
+	logger := log.Ctx(ctx)
+
+	maxDialFailures := config.MaxDialFailures
+	if maxDialFailures <= 0 {
+		logger.Error().Msg("Passive health check maxDialFailures smaller than one")
+		maxDialFailures = dynamic.DefaultPassiveHealthCheckMaxDialFailures
+	}
+
+	ejectionTime := time.Duration(config.EjectionTime)
+	if ejectionTime <= 0 {
+		logger.Error().Msg("Passive health check ejectionTime smaller than zero")
+		ejectionTime = time.Duration(dynamic.DefaultPassiveHealthCheckEjectionTime)
+	}
+
+	return &TCPPassiveHealthChecker{
+		ctx: ctx,
+		status: tcpServerStatus{
+			balancer:    service,
+			info:        info,
+			metrics:     metrics,
+			serviceName: serviceName,
+		},
+		maxDialFailures: maxDialFailures,
+		ejectionTime:    ejectionTime,
+		failures:        map[string]int{},
+	}
+}
+
+// DialObserver returns the function to notify of the result of the dials to the server with the given address.
+func (c *TCPPassiveHealthChecker) DialObserver(address string) func(err error) {
+	return func(err error) {
+		if !c.observe(address, err) {
+			return
+		}
+
+		log.Ctx(c.ctx).Warn().
+			Str("serverAddress", address).
+			Err(err).
+			Msgf("Ejecting server after %d consecutive dial failures for %s", c.maxDialFailures, c.ejectionTime)
+
+		c.status.set(c.ctx, address, false)
+
+		time.AfterFunc(c.ejectionTime, func() {
+			if c.ctx.Err() != nil {
+				return
+			}
+			c.status.set(c.ctx, address, true)
+		})
+	}
+}
+
+// observe records the result of a dial, and reports whether the server has to be ejected.
+func (c *TCPPassiveHealthChecker) observe(address string, err error) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil {
+		delete(c.failures, address)
+		return false
+	}
+
+	c.failures[address]++
+	if c.failures[address] < c.maxDialFailures {
+		return false
+	}
+
+	delete(c.failures, address)
+	return true
+}
@@ -0,0 +1,249 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestServiceTCPHealthChecker_executeHealthCheck(t *testing.T) {
+	echoAddress := startTCPServer(t, func(conn net.Conn) {
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte("+OK " + string(buf)))
+	})
+
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(tlsServer.Close)
+	tlsAddress := tlsServer.Listener.Addr().String()
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedAddress := closedListener.Addr().String()
+	require.NoError(t, closedListener.Close())
+
+	dialerManager := tcp.NewDialerManager(nil)
+	dialerManager.Update(map[string]*dynamic.TCPServersTransport{
+		"default@internal": {
+			DialTimeout: ptypes.Duration(time.Second),
+			TLS:         &dynamic.TLSClientConfig{InsecureSkipVerify: true},
+		},
+	})
+
+	testCases := []struct {
+		desc      string
+		config    dynamic.TCPServerHealthCheck
+		address   string
+		tls       bool
+		expectErr bool
+	}{
+		{
+			desc:    "connect",
+			address: echoAddress,
+		},
+		{
+			desc:    "send and expect",
+			config:  dynamic.TCPServerHealthCheck{Send: "PING", Expect: "+OK PING"},
+			address: echoAddress,
+		},
+		{
+			desc:      "unexpected answer",
+			config:    dynamic.TCPServerHealthCheck{Send: "PING", Expect: "+OK PONG"},
+			address:   echoAddress,
+			expectErr: true,
+		},
+		{
+			desc:      "no answer",
+			config:    dynamic.TCPServerHealthCheck{Expect: "+OK"},
+			address:   echoAddress,
+			expectErr: true,
+		},
+		{
+			desc:      "connection refused",
+			address:   closedAddress,
+			expectErr: true,
+		},
+		{
+			desc:    "TLS handshake",
+			address: tlsAddress,
+			tls:     true,
+		},
+		{
+			desc:      "TLS handshake with a server without TLS",
+			address:   echoAddress,
+			tls:       true,
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dialer, err := dialerManager.Get("", test.tls)
+			require.NoError(t, err)
+
+			config := test.config
+			config.Interval = ptypes.Duration(time.Second)
+			config.Timeout = ptypes.Duration(200 * time.Millisecond)
+
+			hc := NewServiceTCPHealthChecker(context.Background(), &MetricsMock{generic.NewGauge("test")}, &config, nil, nil, "foo", nil)
+
+			err = hc.executeHealthCheck(context.Background(), test.address, dialer)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestServiceTCPHealthChecker_Launch(t *testing.T) {
+	upAddress := startTCPServer(t, func(net.Conn) {})
+	sharedAddress := startTCPServer(t, func(net.Conn) {})
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	downAddress := closedListener.Addr().String()
+	require.NoError(t, closedListener.Close())
+
+	dialerManager := tcp.NewDialerManager(nil)
+	dialerManager.Update(map[string]*dynamic.TCPServersTransport{
+		"default@internal": {
+			TLS: &dynamic.TLSClientConfig{InsecureSkipVerify: true},
+		},
+	})
+	dialer, err := dialerManager.Get("", false)
+	require.NoError(t, err)
+	tlsDialer, err := dialerManager.Get("", true)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	balancer := &statusRecorder{}
+	info := &runtime.TCPServiceInfo{}
+	gauge := generic.NewGauge("test")
+	config := &dynamic.TCPServerHealthCheck{
+		Interval: ptypes.Duration(100 * time.Millisecond),
+		Timeout:  ptypes.Duration(50 * time.Millisecond),
+	}
+
+	hc := NewServiceTCPHealthChecker(ctx, &MetricsMock{gauge}, config, balancer, info, "foo", map[TCPTarget]tcp.Dialer{
+		{Address: upAddress}:   dialer,
+		{Address: downAddress}: dialer,
+		// The server does not complete the TLS handshake.
+		{Address: sharedAddress}:            dialer,
+		{Address: sharedAddress, TLS: true}: tlsDialer,
+	})
+	go hc.Launch(ctx)
+
+	require.Eventually(t, func() bool { return len(info.GetAllStatus()) == 3 }, time.Second, 10*time.Millisecond)
+	cancel()
+
+	expected := map[string]string{
+		upAddress:     runtime.StatusUp,
+		downAddress:   runtime.StatusDown,
+		sharedAddress: runtime.StatusDown,
+	}
+	assert.Equal(t, expected, info.GetAllStatus())
+	assert.True(t, balancer.get(upAddress))
+	assert.False(t, balancer.get(downAddress))
+	assert.False(t, balancer.get(sharedAddress))
+}
+
+func TestTCPPassiveHealthChecker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	balancer := &statusRecorder{}
+	info := &runtime.TCPServiceInfo{}
+	gauge := generic.NewGauge("test")
+	config := &dynamic.TCPPassiveHealthCheck{
+		MaxDialFailures: 2,
+		EjectionTime:    ptypes.Duration(100 * time.Millisecond),
+	}
+
+	hc := NewTCPPassiveHealthChecker(ctx, &MetricsMock{gauge}, config, balancer, info, "foo")
+	observe := hc.DialObserver("127.0.0.1:8080")
+
+	errDial := errors.New("connection refused")
+
+	// A successful dial resets the consecutive failures.
+	observe(errDial)
+	observe(nil)
+	observe(errDial)
+	assert.Empty(t, info.GetAllStatus())
+
+	observe(errDial)
+	assert.Equal(t, map[string]string{"127.0.0.1:8080": runtime.StatusDown}, info.GetAllStatus())
+	assert.False(t, balancer.get("127.0.0.1:8080"))
+
+	// The server is brought back after the ejection time.
+	require.Eventually(t, func() bool { return balancer.get("127.0.0.1:8080") }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[string]string{"127.0.0.1:8080": runtime.StatusUp}, info.GetAllStatus())
+}
+
+func startTCPServer(t *testing.T, handle func(conn net.Conn)) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// statusRecorder records the last status of each server.
+type statusRecorder struct {
+	mu     sync.Mutex
+	status map[string]bool
+}
+
+func (r *statusRecorder) SetStatus(_ context.Context, childName string, up bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.status == nil {
+		r.status = map[string]bool{}
+	}
+	r.status[childName] = up
+}
+
+func (r *statusRecorder) get(childName string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.status[childName]
+}
@@ -16,4 +16,8 @@ const (
 	TLSStoreName         = "tlsStoreName"
 	ServersTransportName = "serversTransport"
 	NeedleName           = "needleName"
+	ConnID               = "connId"
+	NeedleMode           = "needleMode"
+	NeedleDecision       = "needleDecision"
+	NeedleDecisionStatus = "needleDecisionStatus"
 )
@@ -54,6 +54,15 @@ type Registry interface {
 	ServiceServerUpGauge() metrics.Gauge
 	ServiceReqsBytesCounter() metrics.Counter
 	ServiceRespsBytesCounter() metrics.Counter
+
+	// needle metrics
+
+	NeedleCloseEventsQueueGauge() metrics.Gauge
+	NeedleCloseEventsDeliveredCounter() metrics.Counter
+	NeedleCloseEventsDroppedCounter() metrics.Counter
+	NeedleCloseEventsRetriesCounter() metrics.Counter
+	NeedleDecisionsCounter() metrics.Counter
+	NeedleDecisionQueueGauge() metrics.Gauge
 }
 
 // NewVoidRegistry is a noop implementation of metrics.Registry.
@@ -87,6 +96,12 @@ func NewMultiRegistry(registries []Registry) Registry {
 	var serviceServerUpGauge []metrics.Gauge
 	var serviceReqsBytesCounter []metrics.Counter
 	var serviceRespsBytesCounter []metrics.Counter
+	var needleCloseEventsQueueGauge []metrics.Gauge
+	var needleCloseEventsDeliveredCounter []metrics.Counter
+	var needleCloseEventsDroppedCounter []metrics.Counter
+	var needleCloseEventsRetriesCounter []metrics.Counter
+	var needleDecisionsCounter []metrics.Counter
+	var needleDecisionQueueGauge []metrics.Gauge
 
 	for _, r := range registries {
 		if r.ConfigReloadsCounter() != nil {
@@ -152,61 +167,91 @@ func NewMultiRegistry(registries []Registry) Registry {
 		if r.ServiceRespsBytesCounter() != nil {
 			serviceRespsBytesCounter = append(serviceRespsBytesCounter, r.ServiceRespsBytesCounter())
 		}
+		if r.NeedleCloseEventsQueueGauge() != nil {
+			needleCloseEventsQueueGauge = append(needleCloseEventsQueueGauge, r.NeedleCloseEventsQueueGauge())
+		}
+		if r.NeedleCloseEventsDeliveredCounter() != nil {
+			needleCloseEventsDeliveredCounter = append(needleCloseEventsDeliveredCounter, r.NeedleCloseEventsDeliveredCounter())
+		}
+		if r.NeedleCloseEventsDroppedCounter() != nil {
+			needleCloseEventsDroppedCounter = append(needleCloseEventsDroppedCounter, r.NeedleCloseEventsDroppedCounter())
+		}
+		if r.NeedleCloseEventsRetriesCounter() != nil {
+			needleCloseEventsRetriesCounter = append(needleCloseEventsRetriesCounter, r.NeedleCloseEventsRetriesCounter())
+		}
+		if r.NeedleDecisionsCounter() != nil {
+			needleDecisionsCounter = append(needleDecisionsCounter, r.NeedleDecisionsCounter())
+		}
+		if r.NeedleDecisionQueueGauge() != nil {
+			needleDecisionQueueGauge = append(needleDecisionQueueGauge, r.NeedleDecisionQueueGauge())
+		}
 	}
 
 	return &standardRegistry{
-		epEnabled:                      len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0,
-		svcEnabled:                     len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
-		routerEnabled:                  len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0,
-		configReloadsCounter:           multi.NewCounter(configReloadsCounter...),
-		lastConfigReloadSuccessGauge:   multi.NewGauge(lastConfigReloadSuccessGauge...),
-		openConnectionsGauge:           multi.NewGauge(openConnectionsGauge...),
-		tlsCertsNotAfterTimestampGauge: multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
-		entryPointReqsCounter:          NewMultiCounterWithHeaders(entryPointReqsCounter...),
-		entryPointReqsTLSCounter:       multi.NewCounter(entryPointReqsTLSCounter...),
-		entryPointReqDurationHistogram: MultiHistogram(entryPointReqDurationHistogram),
-		entryPointReqsBytesCounter:     multi.NewCounter(entryPointReqsBytesCounter...),
-		entryPointRespsBytesCounter:    multi.NewCounter(entryPointRespsBytesCounter...),
-		routerReqsCounter:              NewMultiCounterWithHeaders(routerReqsCounter...),
-		routerReqsTLSCounter:           multi.NewCounter(routerReqsTLSCounter...),
-		routerReqDurationHistogram:     MultiHistogram(routerReqDurationHistogram),
-		routerReqsBytesCounter:         multi.NewCounter(routerReqsBytesCounter...),
-		routerRespsBytesCounter:        multi.NewCounter(routerRespsBytesCounter...),
-		serviceReqsCounter:             NewMultiCounterWithHeaders(serviceReqsCounter...),
-		serviceReqsTLSCounter:          multi.NewCounter(serviceReqsTLSCounter...),
-		serviceReqDurationHistogram:    MultiHistogram(serviceReqDurationHistogram),
-		serviceRetriesCounter:          multi.NewCounter(serviceRetriesCounter...),
-		serviceServerUpGauge:           multi.NewGauge(serviceServerUpGauge...),
-		serviceReqsBytesCounter:        multi.NewCounter(serviceReqsBytesCounter...),
-		serviceRespsBytesCounter:       multi.NewCounter(serviceRespsBytesCounter...),
+		epEnabled:                         len(entryPointReqsCounter) > 0 || len(entryPointReqDurationHistogram) > 0,
+		svcEnabled:                        len(serviceReqsCounter) > 0 || len(serviceReqDurationHistogram) > 0 || len(serviceRetriesCounter) > 0 || len(serviceServerUpGauge) > 0,
+		routerEnabled:                     len(routerReqsCounter) > 0 || len(routerReqDurationHistogram) > 0,
+		configReloadsCounter:              multi.NewCounter(configReloadsCounter...),
+		lastConfigReloadSuccessGauge:      multi.NewGauge(lastConfigReloadSuccessGauge...),
+		openConnectionsGauge:              multi.NewGauge(openConnectionsGauge...),
+		tlsCertsNotAfterTimestampGauge:    multi.NewGauge(tlsCertsNotAfterTimestampGauge...),
+		entryPointReqsCounter:             NewMultiCounterWithHeaders(entryPointReqsCounter...),
+		entryPointReqsTLSCounter:          multi.NewCounter(entryPointReqsTLSCounter...),
+		entryPointReqDurationHistogram:    MultiHistogram(entryPointReqDurationHistogram),
+		entryPointReqsBytesCounter:        multi.NewCounter(entryPointReqsBytesCounter...),
+		entryPointRespsBytesCounter:       multi.NewCounter(entryPointRespsBytesCounter...),
+		routerReqsCounter:                 NewMultiCounterWithHeaders(routerReqsCounter...),
+		routerReqsTLSCounter:              multi.NewCounter(routerReqsTLSCounter...),
+		routerReqDurationHistogram:        MultiHistogram(routerReqDurationHistogram),
+		routerReqsBytesCounter:            multi.NewCounter(routerReqsBytesCounter...),
+		routerRespsBytesCounter:           multi.NewCounter(routerRespsBytesCounter...),
+		serviceReqsCounter:                NewMultiCounterWithHeaders(serviceReqsCounter...),
+		serviceReqsTLSCounter:             multi.NewCounter(serviceReqsTLSCounter...),
+		serviceReqDurationHistogram:       MultiHistogram(serviceReqDurationHistogram),
+		serviceRetriesCounter:             multi.NewCounter(serviceRetriesCounter...),
+		serviceServerUpGauge:              multi.NewGauge(serviceServerUpGauge...),
+		serviceReqsBytesCounter:           multi.NewCounter(serviceReqsBytesCounter...),
+		serviceRespsBytesCounter:          multi.NewCounter(serviceRespsBytesCounter...),
+		needleCloseEventsQueueGauge:       multi.NewGauge(needleCloseEventsQueueGauge...),
+		needleCloseEventsDeliveredCounter: multi.NewCounter(needleCloseEventsDeliveredCounter...),
+		needleCloseEventsDroppedCounter:   multi.NewCounter(needleCloseEventsDroppedCounter...),
+		needleCloseEventsRetriesCounter:   multi.NewCounter(needleCloseEventsRetriesCounter...),
+		needleDecisionsCounter:            multi.NewCounter(needleDecisionsCounter...),
+		needleDecisionQueueGauge:          multi.NewGauge(needleDecisionQueueGauge...),
 	}
 }
 
 type standardRegistry struct {
-	epEnabled                      bool
-	routerEnabled                  bool
-	svcEnabled                     bool
-	configReloadsCounter           metrics.Counter
-	lastConfigReloadSuccessGauge   metrics.Gauge
-	openConnectionsGauge           metrics.Gauge
-	tlsCertsNotAfterTimestampGauge metrics.Gauge
-	entryPointReqsCounter          CounterWithHeaders
-	entryPointReqsTLSCounter       metrics.Counter
-	entryPointReqDurationHistogram ScalableHistogram
-	entryPointReqsBytesCounter     metrics.Counter
-	entryPointRespsBytesCounter    metrics.Counter
-	routerReqsCounter              CounterWithHeaders
-	routerReqsTLSCounter           metrics.Counter
-	routerReqDurationHistogram     ScalableHistogram
-	routerReqsBytesCounter         metrics.Counter
-	routerRespsBytesCounter        metrics.Counter
-	serviceReqsCounter             CounterWithHeaders
-	serviceReqsTLSCounter          metrics.Counter
-	serviceReqDurationHistogram    ScalableHistogram
-	serviceRetriesCounter          metrics.Counter
-	serviceServerUpGauge           metrics.Gauge
-	serviceReqsBytesCounter        metrics.Counter
-	serviceRespsBytesCounter       metrics.Counter
+	epEnabled                         bool
+	routerEnabled                     bool
+	svcEnabled                        bool
+	configReloadsCounter              metrics.Counter
+	lastConfigReloadSuccessGauge      metrics.Gauge
+	openConnectionsGauge              metrics.Gauge
+	tlsCertsNotAfterTimestampGauge    metrics.Gauge
+	entryPointReqsCounter             CounterWithHeaders
+	entryPointReqsTLSCounter          metrics.Counter
+	entryPointReqDurationHistogram    ScalableHistogram
+	entryPointReqsBytesCounter        metrics.Counter
+	entryPointRespsBytesCounter       metrics.Counter
+	routerReqsCounter                 CounterWithHeaders
+	routerReqsTLSCounter              metrics.Counter
+	routerReqDurationHistogram        ScalableHistogram
+	routerReqsBytesCounter            metrics.Counter
+	routerRespsBytesCounter           metrics.Counter
+	serviceReqsCounter                CounterWithHeaders
+	serviceReqsTLSCounter             metrics.Counter
+	serviceReqDurationHistogram       ScalableHistogram
+	serviceRetriesCounter             metrics.Counter
+	serviceServerUpGauge              metrics.Gauge
+	serviceReqsBytesCounter           metrics.Counter
+	serviceRespsBytesCounter          metrics.Counter
+	needleCloseEventsQueueGauge       metrics.Gauge
+	needleCloseEventsDeliveredCounter metrics.Counter
+	needleCloseEventsDroppedCounter   metrics.Counter
+	needleCloseEventsRetriesCounter   metrics.Counter
+	needleDecisionsCounter            metrics.Counter
+	needleDecisionQueueGauge          metrics.Gauge
 }
 
 func (r *standardRegistry) IsEpEnabled() bool {
@@ -305,6 +350,30 @@ func (r *standardRegistry) ServiceRespsBytesCounter() metrics.Counter {
 	return r.serviceRespsBytesCounter
 }
 
+func (r *standardRegistry) NeedleCloseEventsQueueGauge() metrics.Gauge {
+	return r.needleCloseEventsQueueGauge
+}
+
+func (r *standardRegistry) NeedleCloseEventsDeliveredCounter() metrics.Counter {
+	return r.needleCloseEventsDeliveredCounter
+}
+
+func (r *standardRegistry) NeedleCloseEventsDroppedCounter() metrics.Counter {
+	return r.needleCloseEventsDroppedCounter
+}
+
+func (r *standardRegistry) NeedleCloseEventsRetriesCounter() metrics.Counter {
+	return r.needleCloseEventsRetriesCounter
+}
+
+func (r *standardRegistry) NeedleDecisionsCounter() metrics.Counter {
+	return r.needleDecisionsCounter
+}
+
+func (r *standardRegistry) NeedleDecisionQueueGauge() metrics.Gauge {
+	return r.needleDecisionQueueGauge
+}
+
 // ScalableHistogram is a Histogram with a predefined time unit,
 // used when producing observations without explicitly setting the observed value.
 type ScalableHistogram interface {
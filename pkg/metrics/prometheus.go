@@ -55,6 +55,15 @@ const (
 	serviceServerUpName        = metricServicePrefix + "server_up"
 	serviceReqsBytesTotalName  = metricServicePrefix + "requests_bytes_total"
 	serviceRespsBytesTotalName = metricServicePrefix + "responses_bytes_total"
+
+	// needle level.
+	metricNeedlePrefix                = MetricNamePrefix + "needle_"
+	needleCloseEventsQueueName        = metricNeedlePrefix + "close_events_queued"
+	needleCloseEventsDeliveredName    = metricNeedlePrefix + "close_events_delivered_total"
+	needleCloseEventsDroppedName      = metricNeedlePrefix + "close_events_dropped_total"
+	needleCloseEventsRetriesTotalName = metricNeedlePrefix + "close_events_retries_total"
+	needleDecisionsTotalName          = metricNeedlePrefix + "decisions_total"
+	needleDecisionQueueName           = metricNeedlePrefix + "decisions_queued"
 )
 
 // promState holds all metric state internally and acts as the only Collector we register for Prometheus.
@@ -127,21 +136,58 @@ func initStandardRegistry(config *types.Prometheus) Registry {
 		Help: "How many open connections exist, by entryPoint and protocol",
 	}, []string{"entrypoint", "protocol"})
 
+	needleCloseEventsQueue := newGaugeFrom(stdprometheus.GaugeOpts{
+		Name: needleCloseEventsQueueName,
+		Help: "How many connection close events are waiting for delivery, by needle.",
+	}, []string{"needle"})
+	needleCloseEventsDelivered := newCounterFrom(stdprometheus.CounterOpts{
+		Name: needleCloseEventsDeliveredName,
+		Help: "How many connection close events have been delivered, by needle.",
+	}, []string{"needle"})
+	needleCloseEventsDropped := newCounterFrom(stdprometheus.CounterOpts{
+		Name: needleCloseEventsDroppedName,
+		Help: "How many connection close events have been dropped because of a full queue, by needle and overflow policy.",
+	}, []string{"needle", "policy"})
+	needleCloseEventsRetries := newCounterFrom(stdprometheus.CounterOpts{
+		Name: needleCloseEventsRetriesTotalName,
+		Help: "How many deliveries of connection close events have been retried, by needle.",
+	}, []string{"needle"})
+	needleDecisions := newCounterFrom(stdprometheus.CounterOpts{
+		Name: needleDecisionsTotalName,
+		Help: "How many decisions have been made, by needle, decision mode, decision and decision status.",
+	}, []string{"needle", "mode", "decision", "status"})
+	needleDecisionQueue := newGaugeFrom(stdprometheus.GaugeOpts{
+		Name: needleDecisionQueueName,
+		Help: "How many decisions are waiting for the concurrency limit of their needle, by needle.",
+	}, []string{"needle"})
+
 	promState.vectors = []vector{
 		configReloads.cv,
 		lastConfigReloadSuccess.gv,
 		tlsCertsNotAfterTimestamp.gv,
 		openConnections.gv,
+		needleCloseEventsQueue.gv,
+		needleCloseEventsDelivered.cv,
+		needleCloseEventsDropped.cv,
+		needleCloseEventsRetries.cv,
+		needleDecisions.cv,
+		needleDecisionQueue.gv,
 	}
 
 	reg := &standardRegistry{
-		epEnabled:                      config.AddEntryPointsLabels,
-		routerEnabled:                  config.AddRoutersLabels,
-		svcEnabled:                     config.AddServicesLabels,
-		configReloadsCounter:           configReloads,
-		lastConfigReloadSuccessGauge:   lastConfigReloadSuccess,
-		tlsCertsNotAfterTimestampGauge: tlsCertsNotAfterTimestamp,
-		openConnectionsGauge:           openConnections,
+		epEnabled:                         config.AddEntryPointsLabels,
+		routerEnabled:                     config.AddRoutersLabels,
+		svcEnabled:                        config.AddServicesLabels,
+		configReloadsCounter:              configReloads,
+		lastConfigReloadSuccessGauge:      lastConfigReloadSuccess,
+		tlsCertsNotAfterTimestampGauge:    tlsCertsNotAfterTimestamp,
+		openConnectionsGauge:              openConnections,
+		needleCloseEventsQueueGauge:       needleCloseEventsQueue,
+		needleCloseEventsDeliveredCounter: needleCloseEventsDelivered,
+		needleCloseEventsDroppedCounter:   needleCloseEventsDropped,
+		needleCloseEventsRetriesCounter:   needleCloseEventsRetries,
+		needleDecisionsCounter:            needleDecisions,
+		needleDecisionQueueGauge:          needleDecisionQueue,
 	}
 
 	if config.AddEntryPointsLabels {
@@ -0,0 +1,208 @@
+// Package ratelimiter implements a TCP middleware limiting the rate of the new connections with a set of token buckets.
+package ratelimiter
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/mailgun/ttlmap"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/middlewares"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+	"golang.org/x/time/rate"
+)
+
+const (
+	typeName   = "RateLimiterTCP"
+	maxSources = 65536
+)
+
+// rateLimiter limits the rate of the new connections with a set of token buckets;
+// one for each source. The same parameters are applied to all the buckets.
+type rateLimiter struct {
+	name  string
+	rate  rate.Limit // conns/s
+	burst int64
+	// delay holds the connections exceeding the rate, for at most maxDelay, instead of closing them.
+	delay    bool
+	maxDelay time.Duration
+	// each rate limiter for a given source is stored in the buckets ttlmap.
+	// To keep this ttlmap constrained in size,
+	// each ratelimiter is "garbage collected" when it is considered expired.
+	// It is considered expired after it hasn't been used for ttl seconds.
+	ttl int
+	// ipv4Mask and ipv6Mask aggregate the client IPs by network, if set.
+	ipv4Mask net.IPMask
+	ipv6Mask net.IPMask
+	next     tcp.Handler
+
+	buckets *ttlmap.TtlMap // actual buckets, keyed by source.
+}
+
+// New returns a rate limiter middleware.
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPRateLimit, name string) (tcp.Handler, error) {
+	logger := middlewares.GetLogger(ctx, name, typeName)
+	logger.Debug().Msg("Creating middleware")
+
+	var delay bool
+	switch config.OnExceed {
+	case "", dynamic.TCPRateLimitOnExceedClose:
+	case dynamic.TCPRateLimitOnExceedDelay:
+		delay = true
+	default:
+		return nil, fmt.Errorf("unknown onExceed behavior: %s", config.OnExceed)
+	}
+
+	maxDelay := time.Duration(config.MaxDelay)
+	if maxDelay < 0 {
+		return nil, fmt.Errorf("negative value not valid for maxDelay: %v", maxDelay)
+	}
+	if maxDelay == 0 {
+		maxDelay = time.Second
+	}
+
+	var ipv4Mask, ipv6Mask net.IPMask
+	if config.SourceCriterion != nil {
+		var err error
+		if ipv4Mask, err = sourceMask(config.SourceCriterion.IPv4Mask, 32); err != nil {
+			return nil, fmt.Errorf("invalid ipv4Mask: %w", err)
+		}
+		if ipv6Mask, err = sourceMask(config.SourceCriterion.IPv6Mask, 128); err != nil {
+			return nil, fmt.Errorf("invalid ipv6Mask: %w", err)
+		}
+	}
+
+	buckets, err := ttlmap.NewConcurrent(maxSources)
+	if err != nil {
+		return nil, err
+	}
+
+	burst := config.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	period := time.Duration(config.Period)
+	if period < 0 {
+		return nil, fmt.Errorf("negative value not valid for period: %v", period)
+	}
+	if period == 0 {
+		period = time.Second
+	}
+
+	// Initialized at rate.Inf to enforce no rate limiting when config.Average == 0
+	rtl := float64(rate.Inf)
+	if config.Average > 0 {
+		rtl = float64(config.Average*int64(time.Second)) / float64(period)
+	}
+
+	// Make the ttl inversely proportional to how often a rate limiter is supposed to see any activity (when maxed out),
+	// for low rate limiters.
+	// Otherwise just make it a second for all the high rate limiters.
+	// Add an extra second in both cases for continuity between the two cases.
+	ttl := 1
+	if rtl >= 1 {
+		ttl++
+	} else if rtl > 0 {
+		ttl += int(1 / rtl)
+	}
+
+	return &rateLimiter{
+		name:     name,
+		rate:     rate.Limit(rtl),
+		burst:    burst,
+		delay:    delay,
+		maxDelay: maxDelay,
+		ttl:      ttl,
+		ipv4Mask: ipv4Mask,
+		ipv6Mask: ipv6Mask,
+		next:     next,
+		buckets:  buckets,
+	}, nil
+}
+
+// sourceMask returns the mask of the given prefix length, or nil for no aggregation.
+func sourceMask(ones, bits int) (net.IPMask, error) {
+	if ones < 0 || ones > bits {
+		return nil, fmt.Errorf("prefix length %d not between 0 and %d", ones, bits)
+	}
+	if ones == 0 {
+		return nil, nil
+	}
+	return net.CIDRMask(ones, bits), nil
+}
+
+// ServeTCP serves the given TCP connection.
+func (rl *rateLimiter) ServeTCP(conn tcp.WriteCloser) {
+	logger := middlewares.GetLogger(context.Background(), rl.name, typeName)
+
+	source, err := rl.source(conn.RemoteAddr())
+	if err != nil {
+		logger.Error().Err(err).Msg("Could not extract source of connection")
+		conn.Close()
+		return
+	}
+
+	var bucket *rate.Limiter
+	if rlSource, exists := rl.buckets.Get(source); exists {
+		bucket = rlSource.(*rate.Limiter)
+	} else {
+		bucket = rate.NewLimiter(rl.rate, int(rl.burst))
+	}
+
+	// We Set even in the case where the source already exists,
+	// because we want to update the expiryTime everytime we get the source,
+	// as the expiryTime is supposed to reflect the activity (or lack thereof) on that source.
+	if err := rl.buckets.Set(source, bucket, rl.ttl); err != nil {
+		logger.Error().Err(err).Msg("Could not insert/update bucket")
+		conn.Close()
+		return
+	}
+
+	res := bucket.Reserve()
+	if !res.OK() {
+		logger.Debug().Str("source", source).Msg("Connection rejected: no bursty traffic allowed")
+		conn.Close()
+		return
+	}
+
+	if delay := res.Delay(); delay > 0 {
+		if !rl.delay || delay > rl.maxDelay {
+			res.Cancel()
+			logger.Debug().Str("source", source).Msg("Connection rejected: rate exceeded")
+			conn.Close()
+			return
+		}
+
+		time.Sleep(delay)
+	}
+
+	rl.next.ServeTCP(conn)
+}
+
+// source returns the client IP of the connection, aggregated by network if configured.
+func (rl *rateLimiter) source(remoteAddr net.Addr) (string, error) {
+	host, _, err := net.SplitHostPort(remoteAddr.String())
+	if err != nil {
+		return "", err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", fmt.Errorf("invalid client IP: %s", host)
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		if rl.ipv4Mask == nil {
+			return ip4.String(), nil
+		}
+		return (&net.IPNet{IP: ip4.Mask(rl.ipv4Mask), Mask: rl.ipv4Mask}).String(), nil
+	}
+
+	if rl.ipv6Mask == nil {
+		return ip.String(), nil
+	}
+	return (&net.IPNet{IP: ip.Mask(rl.ipv6Mask), Mask: rl.ipv6Mask}).String(), nil
+}
@@ -0,0 +1,176 @@
+package ratelimiter
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		config        dynamic.TCPRateLimit
+		expectedError string
+	}{
+		{
+			desc:   "default",
+			config: dynamic.TCPRateLimit{Average: 10},
+		},
+		{
+			desc:   "delay",
+			config: dynamic.TCPRateLimit{Average: 10, OnExceed: dynamic.TCPRateLimitOnExceedDelay},
+		},
+		{
+			desc:          "unknown onExceed",
+			config:        dynamic.TCPRateLimit{Average: 10, OnExceed: "drop"},
+			expectedError: "unknown onExceed behavior: drop",
+		},
+		{
+			desc:          "negative period",
+			config:        dynamic.TCPRateLimit{Average: 10, Period: ptypes.Duration(-time.Second)},
+			expectedError: "negative value not valid for period: -1s",
+		},
+		{
+			desc:          "invalid IPv4 mask",
+			config:        dynamic.TCPRateLimit{Average: 10, SourceCriterion: &dynamic.TCPSourceCriterion{IPv4Mask: 33}},
+			expectedError: "invalid ipv4Mask: prefix length 33 not between 0 and 32",
+		},
+		{
+			desc:          "invalid IPv6 mask",
+			config:        dynamic.TCPRateLimit{Average: 10, SourceCriterion: &dynamic.TCPSourceCriterion{IPv6Mask: -1}},
+			expectedError: "invalid ipv6Mask: prefix length -1 not between 0 and 128",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(context.Background(), tcp.HandlerFunc(func(tcp.WriteCloser) {}), test.config, "foo")
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRateLimiter_ServeTCP(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		config           dynamic.TCPRateLimit
+		addrs            []string
+		expectedServed   int
+		expectedMinDelay time.Duration
+	}{
+		{
+			desc:           "no rate limiting",
+			config:         dynamic.TCPRateLimit{},
+			addrs:          []string{"10.0.0.1:1000", "10.0.0.1:1001", "10.0.0.1:1002"},
+			expectedServed: 3,
+		},
+		{
+			desc:           "burst then closed",
+			config:         dynamic.TCPRateLimit{Average: 1, Burst: 2},
+			addrs:          []string{"10.0.0.1:1000", "10.0.0.1:1001", "10.0.0.1:1002"},
+			expectedServed: 2,
+		},
+		{
+			desc:           "one bucket per client IP",
+			config:         dynamic.TCPRateLimit{Average: 1},
+			addrs:          []string{"10.0.0.1:1000", "10.0.0.2:1000", "[2001:db8::1]:1000", "[2001:db8::2]:1000"},
+			expectedServed: 4,
+		},
+		{
+			desc: "client IPs aggregated by network",
+			config: dynamic.TCPRateLimit{
+				Average:         1,
+				SourceCriterion: &dynamic.TCPSourceCriterion{IPv4Mask: 24, IPv6Mask: 64},
+			},
+			addrs:          []string{"10.0.0.1:1000", "10.0.0.2:1000", "10.0.1.1:1000", "[2001:db8::1]:1000", "[2001:db8::2]:1000"},
+			expectedServed: 3,
+		},
+		{
+			desc: "delayed",
+			config: dynamic.TCPRateLimit{
+				Average:  10,
+				OnExceed: dynamic.TCPRateLimitOnExceedDelay,
+				MaxDelay: ptypes.Duration(time.Second),
+			},
+			addrs:            []string{"10.0.0.1:1000", "10.0.0.1:1001", "10.0.0.1:1002"},
+			expectedServed:   3,
+			expectedMinDelay: 150 * time.Millisecond,
+		},
+		{
+			desc: "delay exceeding the max delay",
+			config: dynamic.TCPRateLimit{
+				Average:  1,
+				OnExceed: dynamic.TCPRateLimitOnExceedDelay,
+				MaxDelay: ptypes.Duration(100 * time.Millisecond),
+			},
+			addrs:          []string{"10.0.0.1:1000", "10.0.0.1:1001"},
+			expectedServed: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var served int
+			next := tcp.HandlerFunc(func(tcp.WriteCloser) {
+				served++
+			})
+
+			middleware, err := New(context.Background(), next, test.config, "foo")
+			require.NoError(t, err)
+
+			start := time.Now()
+
+			var closed int
+			for _, addr := range test.addrs {
+				conn := &fakeConn{addr: addr}
+				middleware.ServeTCP(conn)
+				closed += conn.closeCall
+			}
+
+			assert.Equal(t, test.expectedServed, served)
+			assert.Equal(t, len(test.addrs)-test.expectedServed, closed)
+			assert.GreaterOrEqual(t, time.Since(start), test.expectedMinDelay)
+		})
+	}
+}
+
+type fakeConn struct {
+	net.Conn
+
+	addr      string
+	closeCall int
+}
+
+func (c *fakeConn) RemoteAddr() net.Addr {
+	addr, err := net.ResolveTCPAddr("tcp", c.addr)
+	if err != nil {
+		panic(err)
+	}
+	return addr
+}
+
+func (c *fakeConn) Close() error {
+	c.closeCall++
+	return nil
+}
+
+func (c *fakeConn) CloseWrite() error {
+	return nil
+}
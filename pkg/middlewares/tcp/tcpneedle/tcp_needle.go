@@ -2,31 +2,71 @@ package tcpneedle
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
 	"github.com/rs/zerolog"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/middlewares"
 	"github.com/traefik/traefik/v3/pkg/needleware"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 )
 
-const typeName = "NeedleTCP"
+const (
+	typeName = "NeedleTCP"
+
+	maxPeekBytes       = 64 * 1024
+	defaultPeekTimeout = time.Second
+)
 
 type needleTCP struct {
-	name   string
-	next   tcp.Handler
-	needle needleware.Needle
-	logger *zerolog.Logger
+	name        string
+	next        tcp.Handler
+	needle      needleware.Needle
+	route       *client.Route
+	peekBytes   int
+	peekTimeout time.Duration
+	logger      *zerolog.Logger
 }
 
 // New creates Needle middleware.
-func New(ctx context.Context, next tcp.Handler, needle needleware.Needle, name string) (tcp.Handler, error) {
+func New(ctx context.Context, next tcp.Handler, config dynamic.TCPNeedle, needle needleware.Needle, name string) (tcp.Handler, error) {
 	logger := middlewares.GetLogger(ctx, name, typeName)
 	logger.Debug().Msg("Creating middleware")
 
+	if config.PeekBytes < 0 || config.PeekBytes > maxPeekBytes {
+		return nil, fmt.Errorf("peekBytes must be between 0 and %d, got %d", maxPeekBytes, config.PeekBytes)
+	}
+
+	peekTimeout := time.Duration(config.PeekTimeout)
+	if peekTimeout < 0 {
+		return nil, fmt.Errorf("peekTimeout must be positive, got %s", peekTimeout)
+	}
+	if peekTimeout == 0 {
+		peekTimeout = defaultPeekTimeout
+	}
+
+	var route *client.Route
+	if r, ok := needleware.GetRoute(ctx); ok {
+		route = &r
+	}
+
 	return &needleTCP{
-		name:   name,
-		next:   next,
-		needle: needle,
-		logger: logger,
+		name:        name,
+		next:        next,
+		needle:      needle,
+		route:       route,
+		peekBytes:   config.PeekBytes,
+		peekTimeout: peekTimeout,
+		logger:      logger,
 	}, nil
 }
 
@@ -35,18 +75,217 @@ func (i *needleTCP) ServeTCP(conn tcp.WriteCloser) {
 	remoteAddr := conn.RemoteAddr().String()
 	localAddr := conn.LocalAddr().String()
 
-	criteria, err := i.needle.NewTCPCriteria(remoteAddr, localAddr)
+	// The handshake is done early so that the TLS state can be part of the decision criteria.
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		if err := tlsConn.Handshake(); err != nil {
+			i.logger.Debug().Err(err).Msgf("TLS handshake failed for connection from %s to %s", remoteAddr, localAddr)
+			conn.Close()
+			return
+		}
+	}
+
+	criteria, err := i.needle.NewTCPCriteria(conn, i.route)
+	if err == nil && i.peekBytes > 0 {
+		var payload []byte
+		payload, err = i.peek(conn)
+		if err != nil {
+			i.logger.Debug().Err(err).Msgf("Failed to peek first bytes of TCP connection from %s to %s", remoteAddr, localAddr)
+			conn.Close()
+			return
+		}
+		criteria.Payload = payload
+		conn = &peekedConn{Peeked: payload, WriteCloser: conn}
+	}
+
 	if err == nil {
 		// wait until the decision is made
-		decision, _ := i.needle.Decide(criteria)
-		defer i.needle.OnConnClose(decision)
+		decision, err := i.needle.Decide(criteria)
+		if err != nil {
+			i.logger.Error().Err(err).Str(logs.ConnID, criteria.ConnUID).Msgf("Failed to decide on TCP connection from %s to %s", remoteAddr, localAddr)
+			conn.Close()
+			return
+		}
 		if decision.ConnRejected() {
 			conn.Close()
+			i.needle.OnConnClose(decision)
 			return
 		}
+		lateConn := conn
+		decision.OnLateReject(func() { lateConn.Close() })
+
+		// The connection may outlive ServeTCP, as the HTTP entry points serve it asynchronously,
+		// the needle is therefore notified once the connection is closed.
+		limits := decision.NewShapingLimits()
+		conn = &countingConn{
+			WriteCloser: conn,
+			traffic:     &decision.Traffic,
+			onClose: func() {
+				if limits != nil {
+					decision.SetShapingCloseReason(limits.CloseReason())
+				}
+				i.needle.OnConnClose(decision)
+			},
+		}
+		if target := i.newTarget(decision); target != nil {
+			conn = tcp.WithTarget(conn, target)
+		}
+		if limits != nil {
+			conn = tcp.WithLimits(conn, limits)
+		}
 	} else {
 		i.logger.Error().Err(err).Msgf("Failed to create criteria when serving TCP connection from %s to %s", remoteAddr, localAddr)
 	}
 
 	i.next.ServeTCP(conn)
 }
+
+// newTarget converts the routing target of a decision into a target for the services.
+// An invalid PROXY protocol override is ignored, whereas invalid server addresses
+// and service names are reported by the load-balancers.
+func (i *needleTCP) newTarget(decision *needleware.DecisionWrapper) *tcp.Target {
+	routing := decision.Target
+	if routing == nil {
+		return nil
+	}
+
+	target := &tcp.Target{
+		ServerAddress: routing.ServerAddress,
+		Service:       routing.Service,
+	}
+
+	if pp := routing.ProxyProtocol; pp != nil {
+		proxyProtocol, err := newTargetProxyProtocol(pp)
+		if err != nil {
+			i.logger.Warn().Err(err).Str(logs.ConnID, decision.Criteria.ConnUID).Msg("Invalid PROXY protocol override in decision, ignoring it")
+		} else {
+			target.ProxyProtocol = proxyProtocol
+		}
+	}
+
+	if target.ServerAddress == "" && target.Service == "" && target.ProxyProtocol == nil {
+		return nil
+	}
+	return target
+}
+
+func newTargetProxyProtocol(pp *client.ProxyProtocolOverride) (*tcp.TargetProxyProtocol, error) {
+	if pp.Version > 2 {
+		return nil, fmt.Errorf("unknown PROXY protocol version: %d", pp.Version)
+	}
+
+	proxyProtocol := &tcp.TargetProxyProtocol{Version: int(pp.Version)}
+
+	if pp.SourceAddress != "" {
+		addr, err := netip.ParseAddrPort(pp.SourceAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid source address: %w", err)
+		}
+		proxyProtocol.SourceAddr = net.TCPAddrFromAddrPort(addr)
+	}
+
+	if pp.DestinationAddress != "" {
+		addr, err := netip.ParseAddrPort(pp.DestinationAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination address: %w", err)
+		}
+		proxyProtocol.DestinationAddr = net.TCPAddrFromAddrPort(addr)
+	}
+
+	return proxyProtocol, nil
+}
+
+// peek reads the first bytes sent by the client, up to peekBytes, waiting at most peekTimeout for them.
+// It returns as soon as bytes are received, and receiving none is not an error,
+// as the client may be waiting for the server to speak.
+// The read deadline of the connection is restored afterwards, if it is known.
+func (i *needleTCP) peek(conn tcp.WriteCloser) ([]byte, error) {
+	previousDeadline, _ := tcp.GetReadDeadline(conn)
+
+	deadline := time.Now().Add(i.peekTimeout)
+	if !previousDeadline.IsZero() && previousDeadline.Before(deadline) {
+		deadline = previousDeadline
+	}
+
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, i.peekBytes)
+
+	var n int
+	var err error
+	for n == 0 && err == nil {
+		n, err = conn.Read(buf)
+	}
+
+	var netErr net.Error
+	if err != nil && !errors.Is(err, io.EOF) && !(errors.As(err, &netErr) && netErr.Timeout()) {
+		return nil, err
+	}
+
+	if err := conn.SetReadDeadline(previousDeadline); err != nil {
+		return nil, err
+	}
+
+	return buf[:n], nil
+}
+
+// peekedConn is a connection replaying the peeked bytes before reading from the underlying connection.
+type peekedConn struct {
+	Peeked []byte
+	tcp.WriteCloser
+}
+
+// Read reads bytes from the connection (using the peeked bytes prior to actually reading).
+func (c *peekedConn) Read(p []byte) (n int, err error) {
+	if len(c.Peeked) > 0 {
+		n = copy(p, c.Peeked)
+		c.Peeked = c.Peeked[n:]
+		if len(c.Peeked) == 0 {
+			c.Peeked = nil
+		}
+		return n, nil
+	}
+	return c.WriteCloser.Read(p)
+}
+
+// NetConn returns the underlying connection.
+func (c *peekedConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// countingConn counts the bytes exchanged with the client, for the snapshots of the open connections,
+// and calls onClose once the connection is closed.
+type countingConn struct {
+	tcp.WriteCloser
+	traffic *needleware.Traffic
+
+	onClose   func()
+	closeOnce sync.Once
+}
+
+// Close closes the connection, and calls onClose the first time.
+func (c *countingConn) Close() error {
+	err := c.WriteCloser.Close()
+	c.closeOnce.Do(c.onClose)
+	return err
+}
+
+// Read reads bytes from the client.
+func (c *countingConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	c.traffic.AddUploaded(n)
+	return n, err
+}
+
+// Write writes bytes to the client.
+func (c *countingConn) Write(p []byte) (int, error) {
+	n, err := c.WriteCloser.Write(p)
+	c.traffic.AddDownloaded(n)
+	return n, err
+}
+
+// NetConn returns the underlying connection.
+func (c *countingConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
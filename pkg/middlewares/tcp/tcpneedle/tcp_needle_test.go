@@ -0,0 +1,331 @@
+package tcpneedle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ptypes "github.com/traefik/paerser/types"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/needleware"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+func TestNeedleTCP_ServeTCP_Peek(t *testing.T) {
+	testCases := []struct {
+		desc            string
+		config          dynamic.TCPNeedle
+		clientData      string
+		expectedPayload string
+		expectedData    string
+	}{
+		{
+			desc:            "peek disabled",
+			config:          dynamic.TCPNeedle{},
+			clientData:      "hello world",
+			expectedPayload: "",
+			expectedData:    "hello world",
+		},
+		{
+			desc:            "peek fewer bytes than sent",
+			config:          dynamic.TCPNeedle{PeekBytes: 5},
+			clientData:      "hello world",
+			expectedPayload: "hello",
+			expectedData:    "hello world",
+		},
+		{
+			desc:            "peek more bytes than sent",
+			config:          dynamic.TCPNeedle{PeekBytes: 64, PeekTimeout: ptypes.Duration(100 * time.Millisecond)},
+			clientData:      "hello",
+			expectedPayload: "hello",
+			expectedData:    "hello",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			needle := &fakeNeedle{}
+
+			dataCh := make(chan []byte, 1)
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				data, _ := io.ReadAll(conn)
+				_ = conn.Close()
+				dataCh <- data
+			})
+
+			middleware, err := New(context.Background(), next, test.config, needle, "foo")
+			require.NoError(t, err)
+
+			serverConn := pipeTCP(t, test.clientData, test.config.PeekBytes > len(test.clientData))
+			middleware.ServeTCP(serverConn)
+
+			select {
+			case data := <-dataCh:
+				assert.Equal(t, test.expectedData, string(data))
+			case <-time.After(time.Second):
+				t.Fatal("Timeout waiting for data")
+			}
+
+			require.NotNil(t, needle.criteria)
+			assert.Equal(t, test.expectedPayload, string(needle.criteria.Payload))
+			assert.Equal(t, 1, needle.closes)
+		})
+	}
+}
+
+func TestNeedleTCP_peek(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() {
+		_ = serverConn.Close()
+		_ = clientConn.Close()
+	})
+
+	go func() {
+		_, _ = clientConn.Write([]byte("hello"))
+	}()
+
+	previousDeadline := time.Now().Add(time.Hour)
+	conn := &deadlineConn{Conn: serverConn}
+	require.NoError(t, conn.SetReadDeadline(previousDeadline))
+
+	i := &needleTCP{peekBytes: 64, peekTimeout: 10 * time.Second}
+
+	// The first bytes are returned without waiting for the peek timeout.
+	start := time.Now()
+	payload, err := i.peek(conn)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(payload))
+	assert.Less(t, time.Since(start), time.Second)
+
+	// The previous read deadline is restored.
+	assert.Equal(t, previousDeadline, conn.ReadDeadline())
+}
+
+// deadlineConn is a connection reporting its read deadline, like the connections of the entry points.
+type deadlineConn struct {
+	net.Conn
+	readDeadline time.Time
+}
+
+func (c *deadlineConn) SetReadDeadline(deadline time.Time) error {
+	c.readDeadline = deadline
+	return c.Conn.SetReadDeadline(deadline)
+}
+
+func (c *deadlineConn) ReadDeadline() time.Time {
+	return c.readDeadline
+}
+
+func (c *deadlineConn) CloseWrite() error {
+	return c.Conn.Close()
+}
+
+func TestNeedleTCP_ServeTCP_Reject(t *testing.T) {
+	needle := &fakeNeedle{reject: []byte("DROP")}
+
+	nextCalled := false
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		nextCalled = true
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPNeedle{PeekBytes: 4}, needle, "foo")
+	require.NoError(t, err)
+
+	middleware.ServeTCP(pipeTCP(t, "DROP TABLE", false))
+
+	assert.False(t, nextCalled)
+	assert.Equal(t, 1, needle.closes)
+}
+
+func TestNeedleTCP_ServeTCP_AsyncClose(t *testing.T) {
+	needle := &fakeNeedle{}
+
+	// The HTTP entry points hand the connection over to the HTTP server, and return before it is closed.
+	var served tcp.WriteCloser
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		served = conn
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPNeedle{}, needle, "foo")
+	require.NoError(t, err)
+
+	middleware.ServeTCP(pipeTCP(t, "hello", false))
+
+	require.NotNil(t, served)
+	assert.Zero(t, needle.closes)
+
+	require.NoError(t, served.Close())
+	_ = served.Close()
+	assert.Equal(t, 1, needle.closes)
+}
+
+func TestNeedleTCP_ServeTCP_DecisionError(t *testing.T) {
+	needle := &fakeNeedle{err: errors.New("unknown decision code")}
+
+	nextCalled := false
+	next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		nextCalled = true
+	})
+
+	middleware, err := New(context.Background(), next, dynamic.TCPNeedle{}, needle, "foo")
+	require.NoError(t, err)
+
+	conn := pipeTCP(t, "hello", false)
+	middleware.ServeTCP(conn)
+
+	assert.False(t, nextCalled)
+	assert.Zero(t, needle.closes)
+
+	// The connection has been closed.
+	_, err = conn.Read(make([]byte, 1))
+	assert.Error(t, err)
+}
+
+func TestNeedleTCP_ServeTCP_Target(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		routing  *client.RoutingTarget
+		expected *tcp.Target
+	}{
+		{
+			desc: "no routing target",
+		},
+		{
+			desc:     "server address",
+			routing:  &client.RoutingTarget{ServerAddress: "10.0.0.1:5432"},
+			expected: &tcp.Target{ServerAddress: "10.0.0.1:5432"},
+		},
+		{
+			desc: "PROXY protocol override",
+			routing: &client.RoutingTarget{
+				Service:       "db@file",
+				ProxyProtocol: &client.ProxyProtocolOverride{Version: 2, SourceAddress: "192.168.0.1:1234"},
+			},
+			expected: &tcp.Target{
+				Service: "db@file",
+				ProxyProtocol: &tcp.TargetProxyProtocol{
+					Version:    2,
+					SourceAddr: &net.TCPAddr{IP: net.ParseIP("192.168.0.1").To4(), Port: 1234},
+				},
+			},
+		},
+		{
+			desc: "invalid PROXY protocol override is ignored",
+			routing: &client.RoutingTarget{
+				ServerAddress: "10.0.0.1:5432",
+				ProxyProtocol: &client.ProxyProtocolOverride{Version: 3},
+			},
+			expected: &tcp.Target{ServerAddress: "10.0.0.1:5432"},
+		},
+		{
+			desc:    "only invalid PROXY protocol override",
+			routing: &client.RoutingTarget{ProxyProtocol: &client.ProxyProtocolOverride{Version: 1, SourceAddress: "foo"}},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			targetCh := make(chan *tcp.Target, 1)
+			next := tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				targetCh <- tcp.GetTarget(conn)
+			})
+
+			middleware, err := New(context.Background(), next, dynamic.TCPNeedle{}, &fakeNeedle{target: test.routing}, "foo")
+			require.NoError(t, err)
+
+			middleware.ServeTCP(pipeTCP(t, "", false))
+
+			assert.Equal(t, test.expected, <-targetCh)
+		})
+	}
+}
+
+func TestNew_InvalidPeekBytes(t *testing.T) {
+	_, err := New(context.Background(), nil, dynamic.TCPNeedle{PeekBytes: -1}, &fakeNeedle{}, "foo")
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), nil, dynamic.TCPNeedle{PeekBytes: maxPeekBytes + 1}, &fakeNeedle{}, "foo")
+	assert.Error(t, err)
+}
+
+// pipeTCP returns the server side of a TCP connection on which the client sent data.
+// If keepOpen is true, the client keeps its side open for a while after sending.
+func pipeTCP(t *testing.T, data string, keepOpen bool) tcp.WriteCloser {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			return
+		}
+
+		_, _ = conn.Write([]byte(data))
+		if keepOpen {
+			time.Sleep(300 * time.Millisecond)
+		}
+		_ = conn.Close()
+	}()
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn.(*net.TCPConn)
+}
+
+type fakeNeedle struct {
+	err      error
+	reject   []byte
+	target   *client.RoutingTarget
+	criteria *client.DecisionCriteria
+	closes   int
+}
+
+func (n *fakeNeedle) NewTCPCriteria(conn tcp.WriteCloser, route *client.Route) (*client.DecisionCriteria, error) {
+	return &client.DecisionCriteria{Protocol: client.ProtocolTCP, Route: route}, nil
+}
+
+func (n *fakeNeedle) NewUDPCriteria(remoteAddr string, localAddr string) (*client.DecisionCriteria, error) {
+	return &client.DecisionCriteria{Protocol: client.ProtocolUDP}, nil
+}
+
+func (n *fakeNeedle) Decide(criteria *client.DecisionCriteria) (*needleware.DecisionWrapper, error) {
+	n.criteria = criteria
+
+	if n.err != nil {
+		return nil, n.err
+	}
+
+	code := client.DecisionConnAccepted
+	if n.reject != nil && bytes.HasPrefix(criteria.Payload, n.reject) {
+		code = client.DecisionConnRejected
+	}
+
+	return &needleware.DecisionWrapper{
+		Status:       client.StatusDecisionLoaded,
+		DecisionCode: code,
+		Criteria:     criteria,
+		Target:       n.target,
+	}, nil
+}
+
+func (n *fakeNeedle) OnConnClose(decision *needleware.DecisionWrapper) {
+	n.closes++
+}
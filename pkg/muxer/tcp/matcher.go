@@ -1,27 +1,40 @@
 package tcp
 
 import (
+	"bytes"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
 	"github.com/go-acme/lego/v4/challenge/tlsalpn01"
+	"github.com/pires/go-proxyproto"
+	"github.com/pires/go-proxyproto/tlvparse"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/ip"
+	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
 )
 
 var tcpFuncs = map[string]func(*matchersTree, ...string) error{
-	"ALPN":          expect1Parameter(alpn),
-	"ClientIP":      expect1Parameter(clientIP),
-	"HostSNI":       expect1Parameter(hostSNI),
-	"HostSNIRegexp": expect1Parameter(hostSNIRegexp),
+	"ALPN":              expect1Parameter(alpn),
+	"ClientCertPresent": expect1Parameter(clientCertPresent),
+	"ClientIP":          expect1Parameter(clientIP),
+	"HostSNI":           expect1Parameter(hostSNI),
+	"HostSNIRegexp":     expect1Parameter(hostSNIRegexp),
+	"LocalPort":         expect1Parameter(localPort),
+	"ProxyProtocolTLV":  expectParameters(2, proxyProtocolTLV),
+	"TLSVersion":        expect1Parameter(tlsVersion),
 }
 
 func expect1Parameter(fn func(*matchersTree, ...string) error) func(*matchersTree, ...string) error {
+	return expectParameters(1, fn)
+}
+
+func expectParameters(n int, fn func(*matchersTree, ...string) error) func(*matchersTree, ...string) error {
 	return func(route *matchersTree, s ...string) error {
-		if len(s) != 1 {
-			return fmt.Errorf("unexpected number of parameters; got %d, expected 1", len(s))
+		if len(s) != n {
+			return fmt.Errorf("unexpected number of parameters; got %d, expected %d", len(s), n)
 		}
 
 		return fn(route, s...)
@@ -67,6 +80,79 @@ func clientIP(tree *matchersTree, clientIP ...string) error {
 	return nil
 }
 
+// clientCertPresent checks whether the client presented a certificate during the TLS handshake.
+func clientCertPresent(tree *matchersTree, values ...string) error {
+	present, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return fmt.Errorf("invalid value for ClientCertPresent matcher, %q is not a boolean", values[0])
+	}
+
+	tree.matcher = func(meta ConnData) bool {
+		return meta.clientCertPresent == present
+	}
+
+	return nil
+}
+
+// localPort checks if the port on which the connection was accepted matches the matcher port.
+func localPort(tree *matchersTree, ports ...string) error {
+	port, err := strconv.ParseUint(ports[0], 10, 16)
+	if err != nil || port == 0 {
+		return fmt.Errorf("invalid value for LocalPort matcher, %q is not a valid port", ports[0])
+	}
+
+	tree.matcher = func(meta ConnData) bool {
+		return meta.localPort == int(port)
+	}
+
+	return nil
+}
+
+// proxyProtocolTLV checks if any of the TLVs of the PROXY protocol header has the matcher type and value.
+func proxyProtocolTLV(tree *matchersTree, values ...string) error {
+	typ, err := strconv.ParseUint(values[0], 0, 8)
+	if err != nil {
+		return fmt.Errorf("invalid type for ProxyProtocolTLV matcher, %q is not a byte", values[0])
+	}
+
+	value := []byte(values[1])
+
+	tree.matcher = func(meta ConnData) bool {
+		for _, tlv := range meta.proxyProtocolTLVs {
+			if tlv.Type != proxyproto.PP2Type(typ) {
+				continue
+			}
+
+			// The AWS VPC endpoint ID is matched without its subtype.
+			if vpce, err := tlvparse.AWSVPCEndpointID(tlv); err == nil && vpce == values[1] {
+				return true
+			}
+
+			if bytes.Equal(tlv.Value, value) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return nil
+}
+
+// tlsVersion checks if the highest TLS version supported by the client matches the matcher version.
+func tlsVersion(tree *matchersTree, versions ...string) error {
+	version, ok := traefiktls.MaxVersion[versions[0]]
+	if !ok {
+		return fmt.Errorf("invalid value for TLSVersion matcher, %q is not a valid TLS version", versions[0])
+	}
+
+	tree.matcher = func(meta ConnData) bool {
+		return meta.tlsVersion == version
+	}
+
+	return nil
+}
+
 var hostOrIP = regexp.MustCompile(`^[[:alnum:]\.\-\:]+$`)
 
 // hostSNI checks if the SNI Host of the connection match the matcher host.
@@ -1,8 +1,10 @@
 package tcp
 
 import (
+	"crypto/tls"
 	"testing"
 
+	"github.com/pires/go-proxyproto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/tcp"
@@ -381,3 +383,264 @@ func Test_ALPN(t *testing.T) {
 		})
 	}
 }
+
+func Test_LocalPort(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		expected map[int]bool
+		buildErr bool
+	}{
+		{
+			desc:     "Invalid LocalPort matcher (not a number)",
+			rule:     "LocalPort(`foo`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid LocalPort matcher (out of range)",
+			rule:     "LocalPort(`65536`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid LocalPort matcher (zero)",
+			rule:     "LocalPort(`0`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid LocalPort matcher (too many parameters)",
+			rule:     "LocalPort(`8000`, `8001`)",
+			buildErr: true,
+		},
+		{
+			desc: "Valid LocalPort matcher",
+			rule: "LocalPort(`8000`)",
+			expected: map[int]bool{
+				8000: true,
+				8001: false,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute(test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for port, match := range test.expected {
+				meta := ConnData{
+					localPort: port,
+				}
+
+				handler, _ := muxer.Match(meta)
+				assert.Equal(t, match, handler != nil, port)
+			}
+		})
+	}
+}
+
+func Test_ProxyProtocolTLV(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		tlvs     []proxyproto.TLV
+		expected bool
+		buildErr bool
+	}{
+		{
+			desc:     "Invalid ProxyProtocolTLV matcher (not a byte)",
+			rule:     "ProxyProtocolTLV(`0x100`, `foo`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Invalid ProxyProtocolTLV matcher (missing value)",
+			rule:     "ProxyProtocolTLV(`0xE0`)",
+			buildErr: true,
+		},
+		{
+			desc:     "Valid ProxyProtocolTLV matcher without TLVs",
+			rule:     "ProxyProtocolTLV(`0xE0`, `foo`)",
+			expected: false,
+		},
+		{
+			desc:     "Valid ProxyProtocolTLV matcher matching",
+			rule:     "ProxyProtocolTLV(`0xE0`, `foo`)",
+			tlvs:     []proxyproto.TLV{{Type: 0xE1, Value: []byte("foo")}, {Type: 0xE0, Value: []byte("foo")}},
+			expected: true,
+		},
+		{
+			desc:     "Valid ProxyProtocolTLV matcher with decimal type matching",
+			rule:     "ProxyProtocolTLV(`224`, `foo`)",
+			tlvs:     []proxyproto.TLV{{Type: 0xE0, Value: []byte("foo")}},
+			expected: true,
+		},
+		{
+			desc:     "Valid ProxyProtocolTLV matcher not matching the value",
+			rule:     "ProxyProtocolTLV(`0xE0`, `foo`)",
+			tlvs:     []proxyproto.TLV{{Type: 0xE0, Value: []byte("bar")}},
+			expected: false,
+		},
+		{
+			desc:     "Valid ProxyProtocolTLV matcher not matching the type",
+			rule:     "ProxyProtocolTLV(`0xE0`, `foo`)",
+			tlvs:     []proxyproto.TLV{{Type: 0xE1, Value: []byte("foo")}},
+			expected: false,
+		},
+		{
+			desc:     "Valid ProxyProtocolTLV matcher matching the AWS VPC endpoint ID",
+			rule:     "ProxyProtocolTLV(`0xEA`, `vpce-08d2bf15fac5001c9`)",
+			tlvs:     []proxyproto.TLV{{Type: 0xEA, Value: []byte("\x01vpce-08d2bf15fac5001c9")}},
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute(test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			handler, _ := muxer.Match(ConnData{proxyProtocolTLVs: test.tlvs})
+			assert.Equal(t, test.expected, handler != nil)
+		})
+	}
+}
+
+func Test_TLSVersion(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		expected map[uint16]bool
+		buildErr bool
+	}{
+		{
+			desc:     "Invalid TLSVersion matcher",
+			rule:     "TLSVersion(`1.3`)",
+			buildErr: true,
+		},
+		{
+			desc: "Valid TLSVersion matcher",
+			rule: "TLSVersion(`VersionTLS12`)",
+			expected: map[uint16]bool{
+				0:                false,
+				tls.VersionTLS12: true,
+				tls.VersionTLS13: false,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute(test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for version, match := range test.expected {
+				meta := ConnData{
+					tlsVersion: version,
+				}
+
+				handler, _ := muxer.Match(meta)
+				assert.Equal(t, match, handler != nil, version)
+			}
+		})
+	}
+}
+
+func Test_ClientCertPresent(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     string
+		present  bool
+		unknown  bool
+		expected bool
+		buildErr bool
+	}{
+		{
+			desc:     "Invalid ClientCertPresent matcher",
+			rule:     "ClientCertPresent(`foo`)",
+			buildErr: true,
+		},
+		{
+			desc:     "ClientCertPresent matcher matching",
+			rule:     "ClientCertPresent(`true`)",
+			present:  true,
+			expected: true,
+		},
+		{
+			desc:     "ClientCertPresent matcher not matching",
+			rule:     "ClientCertPresent(`true`)",
+			expected: false,
+		},
+		{
+			desc:     "Negative ClientCertPresent matcher matching",
+			rule:     "ClientCertPresent(`false`)",
+			expected: true,
+		},
+		{
+			desc:     "ClientCertPresent matcher matching an unknown client certificate",
+			rule:     "ClientCertPresent(`true`)",
+			unknown:  true,
+			expected: true,
+		},
+		{
+			desc:     "Contradictory ClientCertPresent matchers not matching an unknown client certificate",
+			rule:     "ClientCertPresent(`true`) && !ClientCertPresent(`true`)",
+			unknown:  true,
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			muxer, err := NewMuxer()
+			require.NoError(t, err)
+
+			err = muxer.AddRoute(test.rule, 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {}))
+			if test.buildErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, muxer.HasClientCertRoutes())
+
+			meta := ConnData{
+				clientCertPresent: test.present,
+				clientCertUnknown: test.unknown,
+			}
+
+			handler, _ := muxer.Match(meta)
+			assert.Equal(t, test.expected, handler != nil)
+		})
+	}
+}
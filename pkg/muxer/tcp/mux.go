@@ -1,11 +1,13 @@
 package tcp
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"sort"
 	"strings"
 
+	"github.com/pires/go-proxyproto"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/rules"
 	"github.com/traefik/traefik/v3/pkg/tcp"
@@ -15,9 +17,17 @@ import (
 
 // ConnData contains TCP connection metadata.
 type ConnData struct {
-	serverName string
-	remoteIP   string
-	alpnProtos []string
+	serverName        string
+	remoteIP          string
+	localPort         int
+	alpnProtos        []string
+	proxyProtocolTLVs []proxyproto.TLV
+	// tlsVersion is the highest TLS version supported by the client.
+	tlsVersion uint16
+	// clientCertPresent is whether the client presented a certificate,
+	// which is unknown until the end of the TLS handshake if clientCertUnknown is set.
+	clientCertPresent bool
+	clientCertUnknown bool
 }
 
 // NewConnData builds a connData struct from the given parameters.
@@ -32,13 +42,67 @@ func NewConnData(serverName string, conn tcp.WriteCloser, alpnProtos []string) (
 	// so there is no need to trim a potential trailing dot
 	serverName = types.CanonicalDomain(serverName)
 
+	var localPort int
+	if addr, ok := conn.LocalAddr().(*net.TCPAddr); ok {
+		localPort = addr.Port
+	}
+
 	return ConnData{
-		serverName: types.CanonicalDomain(serverName),
-		remoteIP:   remoteIP,
-		alpnProtos: alpnProtos,
+		serverName:        types.CanonicalDomain(serverName),
+		remoteIP:          remoteIP,
+		localPort:         localPort,
+		alpnProtos:        alpnProtos,
+		proxyProtocolTLVs: proxyProtocolTLVs(conn),
 	}, nil
 }
 
+// SetTLSVersions sets the TLS versions supported by the client, as sent in its ClientHello.
+// As the connection is a TLS one, whether the client presents a certificate is unknown until SetClientCert is called.
+func (c *ConnData) SetTLSVersions(versions []uint16) {
+	c.tlsVersion = 0
+	for _, version := range versions {
+		// Ignores the GREASE values, and the unsupported versions.
+		if version >= tls.VersionTLS10 && version <= tls.VersionTLS13 && version > c.tlsVersion {
+			c.tlsVersion = version
+		}
+	}
+
+	c.clientCertPresent = false
+	c.clientCertUnknown = true
+}
+
+// SetClientCert sets whether the client presented a certificate during the TLS handshake.
+func (c *ConnData) SetClientCert(present bool) {
+	c.clientCertPresent = present
+	c.clientCertUnknown = false
+}
+
+// proxyProtocolTLVs returns the TLVs of the PROXY protocol header read by conn or by one of the connections it wraps, if any.
+func proxyProtocolTLVs(conn net.Conn) []proxyproto.TLV {
+	for conn != nil {
+		if proxyConn, ok := conn.(*proxyproto.Conn); ok {
+			header := proxyConn.ProxyHeader()
+			if header == nil {
+				return nil
+			}
+
+			tlvs, err := header.TLVs()
+			if err != nil {
+				log.Debug().Err(err).Msg("Error while reading the PROXY protocol TLVs")
+			}
+			return tlvs
+		}
+
+		unwrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			return nil
+		}
+		conn = unwrapper.NetConn()
+	}
+
+	return nil
+}
+
 // Muxer defines a muxer that handles TCP routing with rules.
 type Muxer struct {
 	routes routes
@@ -62,9 +126,11 @@ func NewMuxer() (*Muxer, error) {
 
 // Match returns the handler of the first route matching the connection metadata,
 // and whether the match is exactly from the rule HostSNI(*).
+// While whether the client presents a certificate is unknown,
+// a route depending on it matches if it matches whether or not the client presents one.
 func (m Muxer) Match(meta ConnData) (tcp.Handler, bool) {
 	for _, route := range m.routes {
-		if route.matchers.match(meta) {
+		if route.match(meta) {
 			return route.handler, route.catchAll
 		}
 	}
@@ -72,6 +138,17 @@ func (m Muxer) Match(meta ConnData) (tcp.Handler, bool) {
 	return nil, false
 }
 
+// HasClientCertRoutes returns whether any route of the muxer depends on the client certificate.
+func (m *Muxer) HasClientCertRoutes() bool {
+	for _, route := range m.routes {
+		if route.clientCert {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetRulePriority computes the priority for a given rule.
 // The priority is calculated using the length of rule.
 // There is a special case where the HostSNI(`*`) has a priority of -1.
@@ -131,10 +208,11 @@ func (m *Muxer) AddRoute(rule string, priority int, handler tcp.Handler) error {
 	}
 
 	newRoute := &route{
-		handler:  handler,
-		matchers: matchers,
-		catchAll: catchAll,
-		priority: priority,
+		handler:    handler,
+		matchers:   matchers,
+		catchAll:   catchAll,
+		clientCert: len(ruleTree.ParseMatchers([]string{"ClientCertPresent"})) > 0,
+		priority:   priority,
 	}
 	m.routes = append(m.routes, newRoute)
 
@@ -195,12 +273,26 @@ type route struct {
 	handler tcp.Handler
 	// catchAll indicates whether the route rule has exactly the catchAll value (HostSNI(`*`)).
 	catchAll bool
+	// clientCert indicates whether the route rule depends on the client certificate (ClientCertPresent).
+	clientCert bool
 	// priority is used to disambiguate between two (or more) rules that would
 	// all match for a given request.
 	// Computed from the matching rule length, if not user-set.
 	priority int
 }
 
+func (r *route) match(meta ConnData) bool {
+	if !r.clientCert || !meta.clientCertUnknown {
+		return r.matchers.match(meta)
+	}
+
+	withCert, withoutCert := meta, meta
+	withCert.SetClientCert(true)
+	withoutCert.SetClientCert(false)
+
+	return r.matchers.match(withCert) || r.matchers.match(withoutCert)
+}
+
 // matchersTree represents the matchers tree structure.
 type matchersTree struct {
 	// matcher is a matcher func used to match connection properties.
@@ -1,10 +1,12 @@
 package tcp
 
 import (
+	"crypto/tls"
 	"net"
 	"testing"
 	"time"
 
+	"github.com/pires/go-proxyproto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/tcp"
@@ -16,6 +18,7 @@ func Test_addTCPRoute(t *testing.T) {
 		rule       string
 		serverName string
 		remoteAddr string
+		localPort  int
 		protos     []string
 		routeErr   bool
 		matchErr   bool
@@ -260,6 +263,19 @@ func Test_addTCPRoute(t *testing.T) {
 			rule:       "HostSNI(`10::10`)",
 			serverName: "10::10",
 		},
+		{
+			desc:       "Valid HostSNI and LocalPort rule matching",
+			rule:       "HostSNI(`example.org`) && LocalPort(`8000`)",
+			serverName: "example.org",
+			localPort:  8000,
+		},
+		{
+			desc:       "Valid HostSNI and LocalPort rule not matching",
+			rule:       "HostSNI(`example.org`) && LocalPort(`8000`)",
+			serverName: "example.org",
+			localPort:  8001,
+			matchErr:   true,
+		},
 	}
 
 	for _, test := range testCases {
@@ -293,6 +309,7 @@ func Test_addTCPRoute(t *testing.T) {
 			conn := &fakeConn{
 				call:       map[string]int{},
 				remoteAddr: fakeAddr{addr: addr},
+				localAddr:  &net.TCPAddr{IP: net.IPv4zero, Port: test.localPort},
 			}
 
 			connData, err := NewConnData(test.serverName, conn, test.protos)
@@ -315,6 +332,73 @@ func Test_addTCPRoute(t *testing.T) {
 	}
 }
 
+func TestNewConnData_proxyProtocol(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	t.Cleanup(func() {
+		_ = clientConn.Close()
+		_ = serverConn.Close()
+	})
+
+	header := proxyproto.HeaderProxyFromAddrs(2,
+		&net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 12345},
+		&net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 8000},
+	)
+	tlvs := []proxyproto.TLV{{Type: 0xEA, Value: []byte("\x01vpce-08d2bf15fac5001c9")}}
+	require.NoError(t, header.SetTLVs(tlvs))
+
+	go func() {
+		_, _ = header.WriteTo(clientConn)
+	}()
+
+	conn := &netConnWrapper{fakeConn: &fakeConn{}, conn: proxyproto.NewConn(serverConn)}
+
+	connData, err := NewConnData("", conn, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "10.0.0.1", connData.remoteIP)
+	assert.Equal(t, 8000, connData.localPort)
+	assert.Equal(t, tlvs, connData.proxyProtocolTLVs)
+}
+
+func TestConnData_SetTLSVersions(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		versions []uint16
+		expected uint16
+	}{
+		{
+			desc: "no versions",
+		},
+		{
+			desc:     "highest version",
+			versions: []uint16{tls.VersionTLS13, tls.VersionTLS12},
+			expected: tls.VersionTLS13,
+		},
+		{
+			desc:     "GREASE and unknown versions ignored",
+			versions: []uint16{0x0a0a, tls.VersionTLS12, 0x0305},
+			expected: tls.VersionTLS12,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var connData ConnData
+			connData.SetTLSVersions(test.versions)
+
+			assert.Equal(t, test.expected, connData.tlsVersion)
+			assert.True(t, connData.clientCertUnknown)
+
+			connData.SetClientCert(true)
+			assert.True(t, connData.clientCertPresent)
+			assert.False(t, connData.clientCertUnknown)
+		})
+	}
+}
+
 func TestParseHostSNI(t *testing.T) {
 	testCases := []struct {
 		desc          string
@@ -500,6 +584,7 @@ func TestGetRulePriority(t *testing.T) {
 type fakeConn struct {
 	call       map[string]int
 	remoteAddr net.Addr
+	localAddr  net.Addr
 }
 
 func (f *fakeConn) Read(b []byte) (n int, err error) {
@@ -516,7 +601,7 @@ func (f *fakeConn) Close() error {
 }
 
 func (f *fakeConn) LocalAddr() net.Addr {
-	panic("implement me")
+	return f.localAddr
 }
 
 func (f *fakeConn) RemoteAddr() net.Addr {
@@ -539,6 +624,25 @@ func (f *fakeConn) CloseWrite() error {
 	panic("implement me")
 }
 
+// netConnWrapper is a fakeConn wrapping a connection, from which it takes its addresses.
+type netConnWrapper struct {
+	*fakeConn
+	conn net.Conn
+}
+
+func (w *netConnWrapper) LocalAddr() net.Addr {
+	return w.conn.LocalAddr()
+}
+
+func (w *netConnWrapper) RemoteAddr() net.Addr {
+	return w.conn.RemoteAddr()
+}
+
+// NetConn returns the wrapped connection.
+func (w *netConnWrapper) NetConn() net.Conn {
+	return w.conn
+}
+
 type fakeAddr struct {
 	addr string
 }
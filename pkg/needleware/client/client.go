@@ -1,6 +1,9 @@
 package client
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Protocol int
 
@@ -15,6 +18,10 @@ const (
 	StatusDecisionLoaded DecisionStatus = iota
 	StatusDecisionError
 	StatusDecisionTimeout
+	// StatusDecisionCanceled tells that the decision has been canceled, because the instance is shutting down.
+	StatusDecisionCanceled
+	// StatusDecisionOverloaded tells that the decision has not been asked for, because too many were pending.
+	StatusDecisionOverloaded
 )
 
 type DecisionCode int
@@ -24,23 +31,138 @@ const (
 	DecisionConnRejected
 )
 
+type CloseReason int
+
+const (
+	CloseReasonClosed CloseReason = iota
+	CloseReasonMaxTotalBytesExceeded
+	CloseReasonMaxDurationExceeded
+	// CloseReasonRejected tells that an optimistically accepted connection has been closed by a late reject.
+	CloseReasonRejected
+)
+
 type Client interface {
 	OnConnOpened(criteria *DecisionCriteria, ctx context.Context) *DecisionResponse
-	OnConnClosed(connId int32, ctx context.Context) error
+	OnConnClosed(event *CloseEvent, ctx context.Context) error
+	OnConnClosedBatch(events []*CloseEvent, ctx context.Context) error
+	// OnInstanceShutdown tells that the instance is shutting down, and that no more close events are going to be sent.
+	OnInstanceShutdown(instance string, ctx context.Context) error
+	// SyncOpenConnections sends a snapshot of the connections of the instance which are still open.
+	SyncOpenConnections(instance string, conns []*OpenConnection, ctx context.Context) error
+}
+
+// OpenConnection describes a connection still open, as sent to resynchronize a decision service.
+type OpenConnection struct {
+	Criteria        *DecisionCriteria
+	StartedAt       time.Time
+	BytesUploaded   uint64
+	BytesDownloaded uint64
+}
+
+// CloseEvent is sent once an observed connection has been closed.
+type CloseEvent struct {
+	ConnId  int32
+	ConnUID string
+	Reason  CloseReason
+	// Needle is the name of the needle which decided on the connection.
+	Needle string
 }
 
 type DecisionCriteria struct {
-	Protocol   Protocol
-	ConnId     int32
-	RemoteHost string
-	RemotePort int32
-	LocalHost  string
-	LocalPort  int32
-	Metadata   map[string]string
+	Protocol Protocol
+	// ConnId is the legacy identifier of the connection, it is not unique across instances.
+	ConnId int32
+	// ConnUID is the globally unique identifier of the connection: the instance name and a ULID, separated by the last dash.
+	ConnUID       string
+	RemoteHost    string
+	RemotePort    int32
+	LocalHost     string
+	LocalPort     int32
+	TLS           *TLSInfo
+	ProxyProtocol *ProxyProtocolInfo
+	Route         *Route
+	// Payload holds the first bytes sent by the client, when the needle is configured to peek them.
+	Payload  []byte
+	Metadata map[string]string
+}
+
+// TLSInfo holds the negotiated state of a TLS connection terminated by Traefik.
+type TLSInfo struct {
+	ServerName         string
+	NegotiatedProtocol string
+	Version            uint16
+	ClientCertificate  *ClientCertificate
+}
+
+// ClientCertificate holds the details of the leaf certificate presented by the client.
+type ClientCertificate struct {
+	Subject           string
+	Issuer            string
+	SerialNumber      string
+	DNSNames          []string
+	IPAddresses       []string
+	URIs              []string
+	EmailAddresses    []string
+	FingerprintSHA256 string
+}
+
+// ProxyProtocolInfo holds the PROXY protocol header received on the connection.
+// PeerHost and PeerPort are the address of the socket peer which sent the header,
+// whereas the remote address of the criteria is the source declared in the header.
+type ProxyProtocolInfo struct {
+	Version         byte
+	SourceHost      string
+	SourcePort      int32
+	DestinationHost string
+	DestinationPort int32
+	PeerHost        string
+	PeerPort        int32
+	TLVs            map[uint32][]byte
+}
+
+// Route holds the names of the Traefik elements the connection went through.
+type Route struct {
+	EntryPoint string
+	Router     string
+	Service    string
 }
 
 type Decision struct {
 	Code DecisionCode
+	// Target optionally overrides where an accepted connection is forwarded to.
+	Target *RoutingTarget
+	// Limits optionally restricts the bandwidth and lifetime of an accepted connection.
+	Limits *ConnLimits
+	// SyncRequested tells that the service asks for a snapshot of the open connections.
+	SyncRequested bool
+}
+
+// ConnLimits restricts an accepted connection, zero values meaning unlimited.
+// Upload is the direction from the client to the server, download the opposite one.
+type ConnLimits struct {
+	UploadBytesPerSecond   uint64
+	DownloadBytesPerSecond uint64
+	MaxTotalBytes          uint64
+	MaxDuration            time.Duration
+}
+
+// RoutingTarget tells the load-balancers which server or service an accepted connection should be forwarded to.
+// Empty fields leave the corresponding choice to the load-balancers.
+type RoutingTarget struct {
+	// ServerAddress selects the server with this address among the servers of the service.
+	ServerAddress string
+	// Service selects the child with this qualified name (e.g. foo@file) among the services of a weighted service.
+	Service string
+	// ProxyProtocol overrides the PROXY protocol header sent to the server.
+	ProxyProtocol *ProxyProtocolOverride
+}
+
+// ProxyProtocolOverride describes the PROXY protocol header to send to the server.
+// A zero Version disables the header, empty addresses (host:port) keep the ones of the connection.
+type ProxyProtocolOverride struct {
+	Version            byte
+	SourceAddress      string
+	DestinationAddress string
 }
 
 type DecisionResponse struct {
@@ -3,12 +3,24 @@ package client
 import (
 	"context"
 	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
 	"github.com/traefik/traefik/v3/pkg/needleware/client/pb"
+	"google.golang.org/grpc/codes"
+	grpcmetadata "google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+// ConnIDMetadataKey is the gRPC metadata key holding the unique identifier of the connection a decision is asked for.
+const ConnIDMetadataKey = "x-needle-conn-id"
+
 type GRPCClient struct {
 	client pb.NeedlewareClient
+	// batchUnsupported is set once the server answered that it does not implement batches.
+	batchUnsupported atomic.Bool
 }
 
 func NewGRPCClient(client pb.NeedlewareClient) *GRPCClient {
@@ -18,41 +30,20 @@ func NewGRPCClient(client pb.NeedlewareClient) *GRPCClient {
 }
 
 func (c *GRPCClient) OnConnOpened(criteria *DecisionCriteria, ctx context.Context) *DecisionResponse {
-	var protocol pb.Protocol
-	switch criteria.Protocol {
-	case ProtocolUDP:
-		protocol = pb.Protocol_UDP
-	case ProtocolTCP:
-		protocol = pb.Protocol_TCP
-	default:
+	conn, err := toPbConnection(criteria)
+	if err != nil {
 		return &DecisionResponse{
 			Status: StatusDecisionError,
-			Err:    fmt.Errorf("unknown protocol %d", criteria.Protocol),
+			Err:    err,
 		}
 	}
 
-	var metadata *pb.Metadata = nil
-	if criteria.Metadata != nil {
-		metadata = &pb.Metadata{
-			Data: criteria.Metadata,
-		}
+	// The identifier is also sent as metadata, so that interceptors can correlate the call with the connection.
+	if criteria.ConnUID != "" {
+		ctx = grpcmetadata.AppendToOutgoingContext(ctx, ConnIDMetadataKey, criteria.ConnUID)
 	}
 
-	response, err := c.client.OnConnOpened(ctx, &pb.Connection{
-		Id: &pb.ConnectionId{
-			Value: criteria.ConnId,
-		},
-		Protocol: protocol,
-		RemoteAddress: &pb.Address{
-			Host: criteria.RemoteHost,
-			Port: criteria.RemotePort,
-		},
-		LocalAddress: &pb.Address{
-			Host: criteria.LocalHost,
-			Port: criteria.LocalPort,
-		},
-		Metadata: metadata,
-	})
+	response, err := c.client.OnConnOpened(ctx, conn)
 
 	if err != nil {
 		return &DecisionResponse{
@@ -70,14 +61,18 @@ func (c *GRPCClient) OnConnOpened(criteria *DecisionCriteria, ctx context.Contex
 		return &DecisionResponse{
 			Status: StatusDecisionLoaded,
 			Decision: &Decision{
-				Code: DecisionConnAccepted,
+				Code:          DecisionConnAccepted,
+				Target:        fromPbRoutingTarget(response.GetTarget()),
+				Limits:        fromPbLimits(response.GetLimits()),
+				SyncRequested: response.GetSyncRequested(),
 			},
 		}
 	case pb.DecisionCode_REJECT:
 		return &DecisionResponse{
 			Status: StatusDecisionLoaded,
 			Decision: &Decision{
-				Code: DecisionConnRejected,
+				Code:          DecisionConnRejected,
+				SyncRequested: response.GetSyncRequested(),
 			},
 		}
 	}
@@ -88,9 +83,227 @@ func (c *GRPCClient) OnConnOpened(criteria *DecisionCriteria, ctx context.Contex
 	}
 }
 
-func (c *GRPCClient) OnConnClosed(connId int32, ctx context.Context) error {
-	_, err := c.client.OnConnClosed(ctx, &pb.ConnectionId{
-		Value: connId,
-	})
+func (c *GRPCClient) OnConnClosed(event *CloseEvent, ctx context.Context) error {
+	_, err := c.client.OnConnClosed(ctx, toPbConnectionClosed(event))
 	return err
 }
+
+// OnConnClosedBatch delivers the events at once,
+// falling back to one call per event with servers not implementing batches.
+func (c *GRPCClient) OnConnClosedBatch(events []*CloseEvent, ctx context.Context) error {
+	if !c.batchUnsupported.Load() {
+		batch := &pb.ConnectionClosedBatch{}
+		for _, event := range events {
+			batch.Events = append(batch.Events, toPbConnectionClosed(event))
+		}
+
+		_, err := c.client.OnConnClosedBatch(ctx, batch)
+		if status.Code(err) != codes.Unimplemented {
+			return err
+		}
+		c.batchUnsupported.Store(true)
+	}
+
+	for _, event := range events {
+		if err := c.OnConnClosed(event, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OnInstanceShutdown tells the server that the instance is shutting down.
+// Servers not implementing it are not notified.
+func (c *GRPCClient) OnInstanceShutdown(instance string, ctx context.Context) error {
+	_, err := c.client.OnInstanceShutdown(ctx, &pb.Instance{Id: instance})
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	return err
+}
+
+// SyncOpenConnections sends the snapshot of the open connections.
+// Servers not implementing it are not synchronized.
+func (c *GRPCClient) SyncOpenConnections(instance string, conns []*OpenConnection, ctx context.Context) error {
+	snapshot := &pb.OpenConnections{Instance: &pb.Instance{Id: instance}}
+	for _, conn := range conns {
+		pbConn, err := toPbConnection(conn.Criteria)
+		if err != nil {
+			return err
+		}
+
+		snapshot.Connections = append(snapshot.Connections, &pb.OpenConnection{
+			Connection:      pbConn,
+			StartedAtMillis: conn.StartedAt.UnixMilli(),
+			BytesUploaded:   conn.BytesUploaded,
+			BytesDownloaded: conn.BytesDownloaded,
+		})
+	}
+
+	_, err := c.client.SyncOpenConnections(ctx, snapshot)
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	return err
+}
+
+func toPbConnection(criteria *DecisionCriteria) (*pb.Connection, error) {
+	var protocol pb.Protocol
+	switch criteria.Protocol {
+	case ProtocolUDP:
+		protocol = pb.Protocol_UDP
+	case ProtocolTCP:
+		protocol = pb.Protocol_TCP
+	default:
+		return nil, fmt.Errorf("unknown protocol %d", criteria.Protocol)
+	}
+
+	var metadata *pb.Metadata = nil
+	if criteria.Metadata != nil {
+		metadata = &pb.Metadata{
+			Data: criteria.Metadata,
+		}
+	}
+
+	return &pb.Connection{
+		Id: &pb.ConnectionId{
+			Value: criteria.ConnId,
+			Uid:   criteria.ConnUID,
+		},
+		Protocol: protocol,
+		RemoteAddress: &pb.Address{
+			Host: criteria.RemoteHost,
+			Port: criteria.RemotePort,
+		},
+		LocalAddress: &pb.Address{
+			Host: criteria.LocalHost,
+			Port: criteria.LocalPort,
+		},
+		Tls:           toPbTLS(criteria.TLS),
+		ProxyProtocol: toPbProxyProtocol(criteria.ProxyProtocol),
+		Route:         toPbRoute(criteria.Route),
+		Payload:       criteria.Payload,
+		Metadata:      metadata,
+	}, nil
+}
+
+func toPbConnectionClosed(event *CloseEvent) *pb.ConnectionClosed {
+	var reason pb.CloseReason
+	switch event.Reason {
+	case CloseReasonMaxTotalBytesExceeded:
+		reason = pb.CloseReason_MAX_TOTAL_BYTES_EXCEEDED
+	case CloseReasonMaxDurationExceeded:
+		reason = pb.CloseReason_MAX_DURATION_EXCEEDED
+	case CloseReasonRejected:
+		reason = pb.CloseReason_REJECTED
+	default:
+		reason = pb.CloseReason_CLOSED
+	}
+
+	return &pb.ConnectionClosed{
+		Value:  event.ConnId,
+		Reason: reason,
+		Uid:    event.ConnUID,
+		Needle: event.Needle,
+	}
+}
+
+func toPbTLS(info *TLSInfo) *pb.TLS {
+	if info == nil {
+		return nil
+	}
+
+	var cert *pb.ClientCertificate
+	if c := info.ClientCertificate; c != nil {
+		cert = &pb.ClientCertificate{
+			Subject:           c.Subject,
+			Issuer:            c.Issuer,
+			SerialNumber:      c.SerialNumber,
+			DnsNames:          c.DNSNames,
+			IpAddresses:       c.IPAddresses,
+			Uris:              c.URIs,
+			EmailAddresses:    c.EmailAddresses,
+			FingerprintSha256: c.FingerprintSHA256,
+		}
+	}
+
+	return &pb.TLS{
+		ServerName:         info.ServerName,
+		NegotiatedProtocol: info.NegotiatedProtocol,
+		Version:            uint32(info.Version),
+		ClientCertificate:  cert,
+	}
+}
+
+func toPbProxyProtocol(info *ProxyProtocolInfo) *pb.ProxyProtocol {
+	if info == nil {
+		return nil
+	}
+	return &pb.ProxyProtocol{
+		Version: uint32(info.Version),
+		SourceAddress: &pb.Address{
+			Host: info.SourceHost,
+			Port: info.SourcePort,
+		},
+		DestinationAddress: &pb.Address{
+			Host: info.DestinationHost,
+			Port: info.DestinationPort,
+		},
+		PeerAddress: &pb.Address{
+			Host: info.PeerHost,
+			Port: info.PeerPort,
+		},
+		Tlvs: info.TLVs,
+	}
+}
+
+func toPbRoute(route *Route) *pb.Route {
+	if route == nil {
+		return nil
+	}
+	return &pb.Route{
+		EntryPoint: route.EntryPoint,
+		Router:     route.Router,
+		Service:    route.Service,
+	}
+}
+
+func fromPbRoutingTarget(target *pb.RoutingTarget) *RoutingTarget {
+	if target == nil {
+		return nil
+	}
+
+	var proxyProtocol *ProxyProtocolOverride
+	if pp := target.GetProxyProtocol(); pp != nil {
+		proxyProtocol = &ProxyProtocolOverride{
+			Version:            byte(pp.GetVersion()),
+			SourceAddress:      fromPbAddress(pp.GetSourceAddress()),
+			DestinationAddress: fromPbAddress(pp.GetDestinationAddress()),
+		}
+	}
+
+	return &RoutingTarget{
+		ServerAddress: target.GetServerAddress(),
+		Service:       target.GetService(),
+		ProxyProtocol: proxyProtocol,
+	}
+}
+
+func fromPbLimits(limits *pb.Limits) *ConnLimits {
+	if limits == nil {
+		return nil
+	}
+	return &ConnLimits{
+		UploadBytesPerSecond:   limits.GetUploadBytesPerSecond(),
+		DownloadBytesPerSecond: limits.GetDownloadBytesPerSecond(),
+		MaxTotalBytes:          limits.GetMaxTotalBytes(),
+		MaxDuration:            time.Duration(limits.GetMaxDurationMillis()) * time.Millisecond,
+	}
+}
+
+func fromPbAddress(address *pb.Address) string {
+	if address == nil {
+		return ""
+	}
+	return net.JoinHostPort(address.GetHost(), strconv.Itoa(int(address.GetPort())))
+}
@@ -0,0 +1,136 @@
+// Package clienttest provides an in-memory needle client for tests.
+package clienttest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+)
+
+// Client is an in-memory client.Client, recording the criteria and close events it receives.
+// It is safe for concurrent use.
+type Client struct {
+	// Decide returns the response to a decision request.
+	// All the connections are accepted if it is nil.
+	Decide func(ctx context.Context, criteria *client.DecisionCriteria) *client.DecisionResponse
+	// OnClose is called with each batch of close events, before recording it.
+	// If it returns an error, the batch is not recorded and the error is returned to the caller.
+	OnClose func(events []*client.CloseEvent) error
+
+	mu        sync.Mutex
+	opened    []*client.DecisionCriteria
+	batches   [][]*client.CloseEvent
+	shutdowns []string
+	syncs     [][]*client.OpenConnection
+}
+
+// OnConnOpened implements the client.Client interface.
+func (c *Client) OnConnOpened(criteria *client.DecisionCriteria, ctx context.Context) *client.DecisionResponse {
+	c.mu.Lock()
+	c.opened = append(c.opened, criteria)
+	c.mu.Unlock()
+
+	if c.Decide == nil {
+		return Accept()
+	}
+	return c.Decide(ctx, criteria)
+}
+
+// OnConnClosed implements the client.Client interface.
+func (c *Client) OnConnClosed(event *client.CloseEvent, ctx context.Context) error {
+	return c.OnConnClosedBatch([]*client.CloseEvent{event}, ctx)
+}
+
+// OnConnClosedBatch implements the client.Client interface.
+func (c *Client) OnConnClosedBatch(events []*client.CloseEvent, ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.OnClose != nil {
+		if err := c.OnClose(events); err != nil {
+			return err
+		}
+	}
+
+	c.batches = append(c.batches, events)
+	return nil
+}
+
+// OnInstanceShutdown implements the client.Client interface.
+func (c *Client) OnInstanceShutdown(instance string, ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.shutdowns = append(c.shutdowns, instance)
+	return nil
+}
+
+// Shutdowns returns the instances which notified their shutdown so far.
+func (c *Client) Shutdowns() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.shutdowns...)
+}
+
+// SyncOpenConnections implements the client.Client interface.
+func (c *Client) SyncOpenConnections(instance string, conns []*client.OpenConnection, ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.syncs = append(c.syncs, conns)
+	return nil
+}
+
+// Syncs returns the snapshots of open connections received so far.
+func (c *Client) Syncs() [][]*client.OpenConnection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([][]*client.OpenConnection(nil), c.syncs...)
+}
+
+// Opened returns the criteria of the decisions asked so far.
+func (c *Client) Opened() []*client.DecisionCriteria {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]*client.DecisionCriteria(nil), c.opened...)
+}
+
+// Batches returns the batches of close events received so far.
+func (c *Client) Batches() [][]*client.CloseEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([][]*client.CloseEvent(nil), c.batches...)
+}
+
+// Closed returns the close events received so far.
+func (c *Client) Closed() []*client.CloseEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var events []*client.CloseEvent
+	for _, batch := range c.batches {
+		events = append(events, batch...)
+	}
+	return events
+}
+
+// Accept returns a response accepting the connection.
+func Accept() *client.DecisionResponse {
+	return &client.DecisionResponse{
+		Status:   client.StatusDecisionLoaded,
+		Decision: &client.Decision{Code: client.DecisionConnAccepted},
+	}
+}
+
+// Reject returns a response rejecting the connection.
+func Reject() *client.DecisionResponse {
+	return &client.DecisionResponse{
+		Status:   client.StatusDecisionLoaded,
+		Decision: &client.Decision{Code: client.DecisionConnRejected},
+	}
+}
@@ -113,12 +113,65 @@ func (DecisionCode) EnumDescriptor() ([]byte, []int) {
 	return file_proto_needleware_proto_rawDescGZIP(), []int{1}
 }
 
+type CloseReason int32
+
+const (
+	CloseReason_CLOSED                   CloseReason = 0
+	CloseReason_MAX_TOTAL_BYTES_EXCEEDED CloseReason = 1
+	CloseReason_MAX_DURATION_EXCEEDED    CloseReason = 2
+	CloseReason_REJECTED                 CloseReason = 3
+)
+
+// Enum value maps for CloseReason.
+var (
+	CloseReason_name = map[int32]string{
+		0: "CLOSED",
+		1: "MAX_TOTAL_BYTES_EXCEEDED",
+		2: "MAX_DURATION_EXCEEDED",
+		3: "REJECTED",
+	}
+	CloseReason_value = map[string]int32{
+		"CLOSED":                   0,
+		"MAX_TOTAL_BYTES_EXCEEDED": 1,
+		"MAX_DURATION_EXCEEDED":    2,
+		"REJECTED":                 3,
+	}
+)
+
+func (x CloseReason) Enum() *CloseReason {
+	p := new(CloseReason)
+	*p = x
+	return p
+}
+
+func (x CloseReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CloseReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_needleware_proto_enumTypes[2].Descriptor()
+}
+
+func (CloseReason) Type() protoreflect.EnumType {
+	return &file_proto_needleware_proto_enumTypes[2]
+}
+
+func (x CloseReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CloseReason.Descriptor instead.
+func (CloseReason) EnumDescriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{2}
+}
+
 type ConnectionId struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Value int32 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Value int32  `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Uid   string `protobuf:"bytes,2,opt,name=uid,proto3" json:"uid,omitempty"`
 }
 
 func (x *ConnectionId) Reset() {
@@ -160,6 +213,131 @@ func (x *ConnectionId) GetValue() int32 {
 	return 0
 }
 
+func (x *ConnectionId) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+type ConnectionClosed struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Value  int32       `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Reason CloseReason `protobuf:"varint,2,opt,name=reason,proto3,enum=me.igops.needleware.CloseReason" json:"reason,omitempty"`
+	Uid    string      `protobuf:"bytes,3,opt,name=uid,proto3" json:"uid,omitempty"`
+	Needle string      `protobuf:"bytes,4,opt,name=needle,proto3" json:"needle,omitempty"`
+}
+
+func (x *ConnectionClosed) Reset() {
+	*x = ConnectionClosed{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_needleware_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConnectionClosed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectionClosed) ProtoMessage() {}
+
+func (x *ConnectionClosed) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectionClosed.ProtoReflect.Descriptor instead.
+func (*ConnectionClosed) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConnectionClosed) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *ConnectionClosed) GetReason() CloseReason {
+	if x != nil {
+		return x.Reason
+	}
+	return CloseReason_CLOSED
+}
+
+func (x *ConnectionClosed) GetUid() string {
+	if x != nil {
+		return x.Uid
+	}
+	return ""
+}
+
+func (x *ConnectionClosed) GetNeedle() string {
+	if x != nil {
+		return x.Needle
+	}
+	return ""
+}
+
+type ConnectionClosedBatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events []*ConnectionClosed `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *ConnectionClosedBatch) Reset() {
+	*x = ConnectionClosedBatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_needleware_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConnectionClosedBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConnectionClosedBatch) ProtoMessage() {}
+
+func (x *ConnectionClosedBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConnectionClosedBatch.ProtoReflect.Descriptor instead.
+func (*ConnectionClosedBatch) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ConnectionClosedBatch) GetEvents() []*ConnectionClosed {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
 type Address struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -172,7 +350,7 @@ type Address struct {
 func (x *Address) Reset() {
 	*x = Address{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_needleware_proto_msgTypes[1]
+		mi := &file_proto_needleware_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -185,7 +363,7 @@ func (x *Address) String() string {
 func (*Address) ProtoMessage() {}
 
 func (x *Address) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_needleware_proto_msgTypes[1]
+	mi := &file_proto_needleware_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -198,7 +376,7 @@ func (x *Address) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Address.ProtoReflect.Descriptor instead.
 func (*Address) Descriptor() ([]byte, []int) {
-	return file_proto_needleware_proto_rawDescGZIP(), []int{1}
+	return file_proto_needleware_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Address) GetHost() string {
@@ -226,7 +404,7 @@ type Metadata struct {
 func (x *Metadata) Reset() {
 	*x = Metadata{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_needleware_proto_msgTypes[2]
+		mi := &file_proto_needleware_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -239,7 +417,7 @@ func (x *Metadata) String() string {
 func (*Metadata) ProtoMessage() {}
 
 func (x *Metadata) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_needleware_proto_msgTypes[2]
+	mi := &file_proto_needleware_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -252,7 +430,7 @@ func (x *Metadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
 func (*Metadata) Descriptor() ([]byte, []int) {
-	return file_proto_needleware_proto_rawDescGZIP(), []int{2}
+	return file_proto_needleware_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Metadata) GetData() map[string]string {
@@ -262,35 +440,38 @@ func (x *Metadata) GetData() map[string]string {
 	return nil
 }
 
-type Connection struct {
+type ClientCertificate struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id            *ConnectionId `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Protocol      Protocol      `protobuf:"varint,2,opt,name=protocol,proto3,enum=me.igops.needleware.Protocol" json:"protocol,omitempty"`
-	RemoteAddress *Address      `protobuf:"bytes,3,opt,name=remoteAddress,proto3" json:"remoteAddress,omitempty"`
-	LocalAddress  *Address      `protobuf:"bytes,4,opt,name=localAddress,proto3" json:"localAddress,omitempty"`
-	Metadata      *Metadata     `protobuf:"bytes,101,opt,name=metadata,proto3,oneof" json:"metadata,omitempty"`
+	Subject           string   `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Issuer            string   `protobuf:"bytes,2,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	SerialNumber      string   `protobuf:"bytes,3,opt,name=serialNumber,proto3" json:"serialNumber,omitempty"`
+	DnsNames          []string `protobuf:"bytes,4,rep,name=dnsNames,proto3" json:"dnsNames,omitempty"`
+	IpAddresses       []string `protobuf:"bytes,5,rep,name=ipAddresses,proto3" json:"ipAddresses,omitempty"`
+	Uris              []string `protobuf:"bytes,6,rep,name=uris,proto3" json:"uris,omitempty"`
+	EmailAddresses    []string `protobuf:"bytes,7,rep,name=emailAddresses,proto3" json:"emailAddresses,omitempty"`
+	FingerprintSha256 string   `protobuf:"bytes,8,opt,name=fingerprintSha256,proto3" json:"fingerprintSha256,omitempty"`
 }
 
-func (x *Connection) Reset() {
-	*x = Connection{}
+func (x *ClientCertificate) Reset() {
+	*x = ClientCertificate{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_needleware_proto_msgTypes[3]
+		mi := &file_proto_needleware_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Connection) String() string {
+func (x *ClientCertificate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Connection) ProtoMessage() {}
+func (*ClientCertificate) ProtoMessage() {}
 
-func (x *Connection) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_needleware_proto_msgTypes[3]
+func (x *ClientCertificate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -301,71 +482,95 @@ func (x *Connection) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Connection.ProtoReflect.Descriptor instead.
-func (*Connection) Descriptor() ([]byte, []int) {
-	return file_proto_needleware_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ClientCertificate.ProtoReflect.Descriptor instead.
+func (*ClientCertificate) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *Connection) GetId() *ConnectionId {
+func (x *ClientCertificate) GetSubject() string {
 	if x != nil {
-		return x.Id
+		return x.Subject
 	}
-	return nil
+	return ""
 }
 
-func (x *Connection) GetProtocol() Protocol {
+func (x *ClientCertificate) GetIssuer() string {
 	if x != nil {
-		return x.Protocol
+		return x.Issuer
 	}
-	return Protocol_UDP
+	return ""
 }
 
-func (x *Connection) GetRemoteAddress() *Address {
+func (x *ClientCertificate) GetSerialNumber() string {
 	if x != nil {
-		return x.RemoteAddress
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *ClientCertificate) GetDnsNames() []string {
+	if x != nil {
+		return x.DnsNames
 	}
 	return nil
 }
 
-func (x *Connection) GetLocalAddress() *Address {
+func (x *ClientCertificate) GetIpAddresses() []string {
 	if x != nil {
-		return x.LocalAddress
+		return x.IpAddresses
 	}
 	return nil
 }
 
-func (x *Connection) GetMetadata() *Metadata {
+func (x *ClientCertificate) GetUris() []string {
 	if x != nil {
-		return x.Metadata
+		return x.Uris
 	}
 	return nil
 }
 
-type Decision struct {
+func (x *ClientCertificate) GetEmailAddresses() []string {
+	if x != nil {
+		return x.EmailAddresses
+	}
+	return nil
+}
+
+func (x *ClientCertificate) GetFingerprintSha256() string {
+	if x != nil {
+		return x.FingerprintSha256
+	}
+	return ""
+}
+
+type TLS struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Code DecisionCode `protobuf:"varint,1,opt,name=code,proto3,enum=me.igops.needleware.DecisionCode" json:"code,omitempty"`
+	ServerName         string             `protobuf:"bytes,1,opt,name=serverName,proto3" json:"serverName,omitempty"`
+	NegotiatedProtocol string             `protobuf:"bytes,2,opt,name=negotiatedProtocol,proto3" json:"negotiatedProtocol,omitempty"`
+	Version            uint32             `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	ClientCertificate  *ClientCertificate `protobuf:"bytes,4,opt,name=clientCertificate,proto3,oneof" json:"clientCertificate,omitempty"`
 }
 
-func (x *Decision) Reset() {
-	*x = Decision{}
+func (x *TLS) Reset() {
+	*x = TLS{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_needleware_proto_msgTypes[4]
+		mi := &file_proto_needleware_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Decision) String() string {
+func (x *TLS) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Decision) ProtoMessage() {}
+func (*TLS) ProtoMessage() {}
 
-func (x *Decision) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_needleware_proto_msgTypes[4]
+func (x *TLS) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -376,150 +581,1093 @@ func (x *Decision) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Decision.ProtoReflect.Descriptor instead.
-func (*Decision) Descriptor() ([]byte, []int) {
-	return file_proto_needleware_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use TLS.ProtoReflect.Descriptor instead.
+func (*TLS) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Decision) GetCode() DecisionCode {
+func (x *TLS) GetServerName() string {
 	if x != nil {
-		return x.Code
+		return x.ServerName
 	}
-	return DecisionCode_ACCEPT
+	return ""
 }
 
-var File_proto_needleware_proto protoreflect.FileDescriptor
+func (x *TLS) GetNegotiatedProtocol() string {
+	if x != nil {
+		return x.NegotiatedProtocol
+	}
+	return ""
+}
 
-var file_proto_needleware_proto_rawDesc = []byte{
-	0x0a, 0x16, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61,
-	0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x13, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f,
-	0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x1a, 0x1b, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65,
-	0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x24, 0x0a, 0x0c, 0x43, 0x6f,
-	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
-	0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x22, 0x31, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x68,
-	0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12,
-	0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70,
-	0x6f, 0x72, 0x74, 0x22, 0x80, 0x01, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0x12, 0x3b, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27,
-	0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65,
-	0x77, 0x61, 0x72, 0x65, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61,
-	0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x37, 0x0a,
-	0x09, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xd3, 0x02, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x6e, 0x65,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x31, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x21, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65,
-	0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x49, 0x64, 0x52, 0x02, 0x69, 0x64, 0x12, 0x39, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x6d, 0x65, 0x2e,
-	0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65,
-	0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x63, 0x6f, 0x6c, 0x12, 0x42, 0x0a, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64, 0x64,
-	0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x65, 0x2e,
-	0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65,
-	0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
-	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x40, 0x0a, 0x0c, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
-	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
-	0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77,
-	0x61, 0x72, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x0c, 0x6c, 0x6f, 0x63,
-	0x61, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x3e, 0x0a, 0x08, 0x6d, 0x65, 0x74,
-	0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x65, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x65,
-	0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72,
-	0x65, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x08, 0x6d, 0x65,
-	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6d, 0x65,
-	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x64, 0x22, 0x41, 0x0a, 0x08,
-	0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70,
-	0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x44, 0x65, 0x63,
-	0x69, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x2a,
-	0x1c, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x07, 0x0a, 0x03, 0x55,
-	0x44, 0x50, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x43, 0x50, 0x10, 0x01, 0x2a, 0x26, 0x0a,
-	0x0c, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x0a, 0x0a,
-	0x06, 0x41, 0x43, 0x43, 0x45, 0x50, 0x54, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x4a,
-	0x45, 0x43, 0x54, 0x10, 0x01, 0x32, 0xab, 0x01, 0x0a, 0x0a, 0x4e, 0x65, 0x65, 0x64, 0x6c, 0x65,
-	0x77, 0x61, 0x72, 0x65, 0x12, 0x50, 0x0a, 0x0c, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x6e, 0x4f, 0x70,
-	0x65, 0x6e, 0x65, 0x64, 0x12, 0x1f, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e,
-	0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x1d, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73,
-	0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x44, 0x65, 0x63, 0x69,
-	0x73, 0x69, 0x6f, 0x6e, 0x22, 0x00, 0x12, 0x4b, 0x0a, 0x0c, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x6e,
-	0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x12, 0x21, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70,
-	0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x43, 0x6f, 0x6e,
-	0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x22, 0x00, 0x42, 0x06, 0x5a, 0x04, 0x2e, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+func (x *TLS) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
 }
 
-var (
-	file_proto_needleware_proto_rawDescOnce sync.Once
-	file_proto_needleware_proto_rawDescData = file_proto_needleware_proto_rawDesc
-)
+func (x *TLS) GetClientCertificate() *ClientCertificate {
+	if x != nil {
+		return x.ClientCertificate
+	}
+	return nil
+}
 
-func file_proto_needleware_proto_rawDescGZIP() []byte {
-	file_proto_needleware_proto_rawDescOnce.Do(func() {
-		file_proto_needleware_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_needleware_proto_rawDescData)
-	})
-	return file_proto_needleware_proto_rawDescData
+type ProxyProtocol struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version            uint32            `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	SourceAddress      *Address          `protobuf:"bytes,2,opt,name=sourceAddress,proto3" json:"sourceAddress,omitempty"`
+	DestinationAddress *Address          `protobuf:"bytes,3,opt,name=destinationAddress,proto3" json:"destinationAddress,omitempty"`
+	PeerAddress        *Address          `protobuf:"bytes,4,opt,name=peerAddress,proto3" json:"peerAddress,omitempty"`
+	Tlvs               map[uint32][]byte `protobuf:"bytes,5,rep,name=tlvs,proto3" json:"tlvs,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-var file_proto_needleware_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_proto_needleware_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
-var file_proto_needleware_proto_goTypes = []interface{}{
-	(Protocol)(0),         // 0: me.igops.needleware.Protocol
-	(DecisionCode)(0),     // 1: me.igops.needleware.DecisionCode
-	(*ConnectionId)(nil),  // 2: me.igops.needleware.ConnectionId
-	(*Address)(nil),       // 3: me.igops.needleware.Address
-	(*Metadata)(nil),      // 4: me.igops.needleware.Metadata
-	(*Connection)(nil),    // 5: me.igops.needleware.Connection
-	(*Decision)(nil),      // 6: me.igops.needleware.Decision
-	nil,                   // 7: me.igops.needleware.Metadata.DataEntry
-	(*emptypb.Empty)(nil), // 8: google.protobuf.Empty
+func (x *ProxyProtocol) Reset() {
+	*x = ProxyProtocol{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_needleware_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
-var file_proto_needleware_proto_depIdxs = []int32{
-	7, // 0: me.igops.needleware.Metadata.data:type_name -> me.igops.needleware.Metadata.DataEntry
-	2, // 1: me.igops.needleware.Connection.id:type_name -> me.igops.needleware.ConnectionId
-	0, // 2: me.igops.needleware.Connection.protocol:type_name -> me.igops.needleware.Protocol
-	3, // 3: me.igops.needleware.Connection.remoteAddress:type_name -> me.igops.needleware.Address
-	3, // 4: me.igops.needleware.Connection.localAddress:type_name -> me.igops.needleware.Address
-	4, // 5: me.igops.needleware.Connection.metadata:type_name -> me.igops.needleware.Metadata
-	1, // 6: me.igops.needleware.Decision.code:type_name -> me.igops.needleware.DecisionCode
-	5, // 7: me.igops.needleware.Needleware.onConnOpened:input_type -> me.igops.needleware.Connection
-	2, // 8: me.igops.needleware.Needleware.onConnClosed:input_type -> me.igops.needleware.ConnectionId
-	6, // 9: me.igops.needleware.Needleware.onConnOpened:output_type -> me.igops.needleware.Decision
-	8, // 10: me.igops.needleware.Needleware.onConnClosed:output_type -> google.protobuf.Empty
-	9, // [9:11] is the sub-list for method output_type
-	7, // [7:9] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+
+func (x *ProxyProtocol) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_proto_needleware_proto_init() }
-func file_proto_needleware_proto_init() {
-	if File_proto_needleware_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_proto_needleware_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ConnectionId); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+func (*ProxyProtocol) ProtoMessage() {}
+
+func (x *ProxyProtocol) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_proto_needleware_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Address); i {
-			case 0:
-				return &v.state
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProxyProtocol.ProtoReflect.Descriptor instead.
+func (*ProxyProtocol) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ProxyProtocol) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ProxyProtocol) GetSourceAddress() *Address {
+	if x != nil {
+		return x.SourceAddress
+	}
+	return nil
+}
+
+func (x *ProxyProtocol) GetDestinationAddress() *Address {
+	if x != nil {
+		return x.DestinationAddress
+	}
+	return nil
+}
+
+func (x *ProxyProtocol) GetPeerAddress() *Address {
+	if x != nil {
+		return x.PeerAddress
+	}
+	return nil
+}
+
+func (x *ProxyProtocol) GetTlvs() map[uint32][]byte {
+	if x != nil {
+		return x.Tlvs
+	}
+	return nil
+}
+
+type Route struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	EntryPoint string `protobuf:"bytes,1,opt,name=entryPoint,proto3" json:"entryPoint,omitempty"`
+	Router     string `protobuf:"bytes,2,opt,name=router,proto3" json:"router,omitempty"`
+	Service    string `protobuf:"bytes,3,opt,name=service,proto3" json:"service,omitempty"`
+}
+
+func (x *Route) Reset() {
+	*x = Route{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_needleware_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Route) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Route) ProtoMessage() {}
+
+func (x *Route) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Route.ProtoReflect.Descriptor instead.
+func (*Route) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Route) GetEntryPoint() string {
+	if x != nil {
+		return x.EntryPoint
+	}
+	return ""
+}
+
+func (x *Route) GetRouter() string {
+	if x != nil {
+		return x.Router
+	}
+	return ""
+}
+
+func (x *Route) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+type Connection struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id            *ConnectionId  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Protocol      Protocol       `protobuf:"varint,2,opt,name=protocol,proto3,enum=me.igops.needleware.Protocol" json:"protocol,omitempty"`
+	RemoteAddress *Address       `protobuf:"bytes,3,opt,name=remoteAddress,proto3" json:"remoteAddress,omitempty"`
+	LocalAddress  *Address       `protobuf:"bytes,4,opt,name=localAddress,proto3" json:"localAddress,omitempty"`
+	Tls           *TLS           `protobuf:"bytes,5,opt,name=tls,proto3,oneof" json:"tls,omitempty"`
+	ProxyProtocol *ProxyProtocol `protobuf:"bytes,6,opt,name=proxyProtocol,proto3,oneof" json:"proxyProtocol,omitempty"`
+	Route         *Route         `protobuf:"bytes,7,opt,name=route,proto3,oneof" json:"route,omitempty"`
+	Payload       []byte         `protobuf:"bytes,8,opt,name=payload,proto3,oneof" json:"payload,omitempty"`
+	Metadata      *Metadata      `protobuf:"bytes,101,opt,name=metadata,proto3,oneof" json:"metadata,omitempty"`
+}
+
+func (x *Connection) Reset() {
+	*x = Connection{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_needleware_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Connection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Connection) ProtoMessage() {}
+
+func (x *Connection) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Connection.ProtoReflect.Descriptor instead.
+func (*Connection) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Connection) GetId() *ConnectionId {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Connection) GetProtocol() Protocol {
+	if x != nil {
+		return x.Protocol
+	}
+	return Protocol_UDP
+}
+
+func (x *Connection) GetRemoteAddress() *Address {
+	if x != nil {
+		return x.RemoteAddress
+	}
+	return nil
+}
+
+func (x *Connection) GetLocalAddress() *Address {
+	if x != nil {
+		return x.LocalAddress
+	}
+	return nil
+}
+
+func (x *Connection) GetTls() *TLS {
+	if x != nil {
+		return x.Tls
+	}
+	return nil
+}
+
+func (x *Connection) GetProxyProtocol() *ProxyProtocol {
+	if x != nil {
+		return x.ProxyProtocol
+	}
+	return nil
+}
+
+func (x *Connection) GetRoute() *Route {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+func (x *Connection) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Connection) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type ProxyProtocolOverride struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version            uint32   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	SourceAddress      *Address `protobuf:"bytes,2,opt,name=sourceAddress,proto3,oneof" json:"sourceAddress,omitempty"`
+	DestinationAddress *Address `protobuf:"bytes,3,opt,name=destinationAddress,proto3,oneof" json:"destinationAddress,omitempty"`
+}
+
+func (x *ProxyProtocolOverride) Reset() {
+	*x = ProxyProtocolOverride{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_needleware_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProxyProtocolOverride) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProxyProtocolOverride) ProtoMessage() {}
+
+func (x *ProxyProtocolOverride) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProxyProtocolOverride.ProtoReflect.Descriptor instead.
+func (*ProxyProtocolOverride) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ProxyProtocolOverride) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ProxyProtocolOverride) GetSourceAddress() *Address {
+	if x != nil {
+		return x.SourceAddress
+	}
+	return nil
+}
+
+func (x *ProxyProtocolOverride) GetDestinationAddress() *Address {
+	if x != nil {
+		return x.DestinationAddress
+	}
+	return nil
+}
+
+type RoutingTarget struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ServerAddress string                 `protobuf:"bytes,1,opt,name=serverAddress,proto3" json:"serverAddress,omitempty"`
+	Service       string                 `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
+	ProxyProtocol *ProxyProtocolOverride `protobuf:"bytes,3,opt,name=proxyProtocol,proto3,oneof" json:"proxyProtocol,omitempty"`
+}
+
+func (x *RoutingTarget) Reset() {
+	*x = RoutingTarget{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_needleware_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RoutingTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoutingTarget) ProtoMessage() {}
+
+func (x *RoutingTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoutingTarget.ProtoReflect.Descriptor instead.
+func (*RoutingTarget) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RoutingTarget) GetServerAddress() string {
+	if x != nil {
+		return x.ServerAddress
+	}
+	return ""
+}
+
+func (x *RoutingTarget) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *RoutingTarget) GetProxyProtocol() *ProxyProtocolOverride {
+	if x != nil {
+		return x.ProxyProtocol
+	}
+	return nil
+}
+
+type Limits struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UploadBytesPerSecond   uint64 `protobuf:"varint,1,opt,name=uploadBytesPerSecond,proto3" json:"uploadBytesPerSecond,omitempty"`
+	DownloadBytesPerSecond uint64 `protobuf:"varint,2,opt,name=downloadBytesPerSecond,proto3" json:"downloadBytesPerSecond,omitempty"`
+	MaxTotalBytes          uint64 `protobuf:"varint,3,opt,name=maxTotalBytes,proto3" json:"maxTotalBytes,omitempty"`
+	MaxDurationMillis      uint64 `protobuf:"varint,4,opt,name=maxDurationMillis,proto3" json:"maxDurationMillis,omitempty"`
+}
+
+func (x *Limits) Reset() {
+	*x = Limits{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_needleware_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Limits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Limits) ProtoMessage() {}
+
+func (x *Limits) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Limits.ProtoReflect.Descriptor instead.
+func (*Limits) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Limits) GetUploadBytesPerSecond() uint64 {
+	if x != nil {
+		return x.UploadBytesPerSecond
+	}
+	return 0
+}
+
+func (x *Limits) GetDownloadBytesPerSecond() uint64 {
+	if x != nil {
+		return x.DownloadBytesPerSecond
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxTotalBytes() uint64 {
+	if x != nil {
+		return x.MaxTotalBytes
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxDurationMillis() uint64 {
+	if x != nil {
+		return x.MaxDurationMillis
+	}
+	return 0
+}
+
+type Decision struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code          DecisionCode   `protobuf:"varint,1,opt,name=code,proto3,enum=me.igops.needleware.DecisionCode" json:"code,omitempty"`
+	Target        *RoutingTarget `protobuf:"bytes,2,opt,name=target,proto3,oneof" json:"target,omitempty"`
+	Limits        *Limits        `protobuf:"bytes,3,opt,name=limits,proto3,oneof" json:"limits,omitempty"`
+	SyncRequested bool           `protobuf:"varint,4,opt,name=syncRequested,proto3" json:"syncRequested,omitempty"`
+}
+
+func (x *Decision) Reset() {
+	*x = Decision{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_needleware_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Decision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Decision) ProtoMessage() {}
+
+func (x *Decision) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Decision.ProtoReflect.Descriptor instead.
+func (*Decision) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Decision) GetCode() DecisionCode {
+	if x != nil {
+		return x.Code
+	}
+	return DecisionCode_ACCEPT
+}
+
+func (x *Decision) GetTarget() *RoutingTarget {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *Decision) GetLimits() *Limits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *Decision) GetSyncRequested() bool {
+	if x != nil {
+		return x.SyncRequested
+	}
+	return false
+}
+
+type OpenConnection struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Connection      *Connection `protobuf:"bytes,1,opt,name=connection,proto3" json:"connection,omitempty"`
+	StartedAtMillis int64       `protobuf:"varint,2,opt,name=startedAtMillis,proto3" json:"startedAtMillis,omitempty"`
+	BytesUploaded   uint64      `protobuf:"varint,3,opt,name=bytesUploaded,proto3" json:"bytesUploaded,omitempty"`
+	BytesDownloaded uint64      `protobuf:"varint,4,opt,name=bytesDownloaded,proto3" json:"bytesDownloaded,omitempty"`
+}
+
+func (x *OpenConnection) Reset() {
+	*x = OpenConnection{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_needleware_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OpenConnection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenConnection) ProtoMessage() {}
+
+func (x *OpenConnection) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenConnection.ProtoReflect.Descriptor instead.
+func (*OpenConnection) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *OpenConnection) GetConnection() *Connection {
+	if x != nil {
+		return x.Connection
+	}
+	return nil
+}
+
+func (x *OpenConnection) GetStartedAtMillis() int64 {
+	if x != nil {
+		return x.StartedAtMillis
+	}
+	return 0
+}
+
+func (x *OpenConnection) GetBytesUploaded() uint64 {
+	if x != nil {
+		return x.BytesUploaded
+	}
+	return 0
+}
+
+func (x *OpenConnection) GetBytesDownloaded() uint64 {
+	if x != nil {
+		return x.BytesDownloaded
+	}
+	return 0
+}
+
+type OpenConnections struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Instance    *Instance         `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Connections []*OpenConnection `protobuf:"bytes,2,rep,name=connections,proto3" json:"connections,omitempty"`
+}
+
+func (x *OpenConnections) Reset() {
+	*x = OpenConnections{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_needleware_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OpenConnections) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenConnections) ProtoMessage() {}
+
+func (x *OpenConnections) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenConnections.ProtoReflect.Descriptor instead.
+func (*OpenConnections) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *OpenConnections) GetInstance() *Instance {
+	if x != nil {
+		return x.Instance
+	}
+	return nil
+}
+
+func (x *OpenConnections) GetConnections() []*OpenConnection {
+	if x != nil {
+		return x.Connections
+	}
+	return nil
+}
+
+type Instance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *Instance) Reset() {
+	*x = Instance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_needleware_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Instance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Instance) ProtoMessage() {}
+
+func (x *Instance) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_needleware_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Instance.ProtoReflect.Descriptor instead.
+func (*Instance) Descriptor() ([]byte, []int) {
+	return file_proto_needleware_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Instance) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+var File_proto_needleware_proto protoreflect.FileDescriptor
+
+var file_proto_needleware_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61,
+	0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x13, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f,
+	0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x1a, 0x1b, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65,
+	0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x36, 0x0a, 0x0c, 0x43, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75,
+	0x69, 0x64, 0x22, 0x8c, 0x01, 0x0a, 0x10, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x38, 0x0a,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e,
+	0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77,
+	0x61, 0x72, 0x65, 0x2e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x65, 0x65,
+	0x64, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x65, 0x65, 0x64, 0x6c,
+	0x65, 0x22, 0x56, 0x0a, 0x15, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43,
+	0x6c, 0x6f, 0x73, 0x65, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x3d, 0x0a, 0x06, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x6d, 0x65, 0x2e,
+	0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65,
+	0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65,
+	0x64, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x31, 0x0a, 0x07, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x80, 0x01, 0x0a,
+	0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x3b, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f,
+	0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x4d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x2e, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x37, 0x0a, 0x09, 0x44, 0x61, 0x74, 0x61, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0x91, 0x02, 0x0a, 0x11, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x75, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x65, 0x72, 0x69, 0x61,
+	0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73,
+	0x65, 0x72, 0x69, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x64,
+	0x6e, 0x73, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x64,
+	0x6e, 0x73, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x70, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x70,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x72, 0x69,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x75, 0x72, 0x69, 0x73, 0x12, 0x26, 0x0a,
+	0x0e, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18,
+	0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70,
+	0x72, 0x69, 0x6e, 0x74, 0x53, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x11, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x53, 0x68, 0x61,
+	0x32, 0x35, 0x36, 0x22, 0xe0, 0x01, 0x0a, 0x03, 0x54, 0x4c, 0x53, 0x12, 0x1e, 0x0a, 0x0a, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2e, 0x0a, 0x12, 0x6e,
+	0x65, 0x67, 0x6f, 0x74, 0x69, 0x61, 0x74, 0x65, 0x64, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x6e, 0x65, 0x67, 0x6f, 0x74, 0x69, 0x61,
+	0x74, 0x65, 0x64, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x59, 0x0a, 0x11, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43,
+	0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x26, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64,
+	0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x48, 0x00, 0x52, 0x11, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01,
+	0x42, 0x14, 0x0a, 0x12, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x22, 0xf6, 0x02, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x78, 0x79,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x42, 0x0a, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x65, 0x2e, 0x69,
+	0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x4c, 0x0a, 0x12, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65,
+	0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x52, 0x12, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x12, 0x3e, 0x0a, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x65, 0x2e, 0x69,
+	0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x12, 0x40, 0x0a, 0x04, 0x74, 0x6c, 0x76, 0x73, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65,
+	0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x54, 0x6c, 0x76, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x04, 0x74, 0x6c, 0x76, 0x73, 0x1a, 0x37, 0x0a, 0x09, 0x54, 0x6c, 0x76, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0x59, 0x0a, 0x05, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x6e, 0x74, 0x72,
+	0x79, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x6e,
+	0x74, 0x72, 0x79, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x22, 0xd9, 0x04, 0x0a, 0x0a, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x31, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73,
+	0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x6e,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x52, 0x02, 0x69, 0x64, 0x12, 0x39, 0x0a, 0x08,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d,
+	0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65,
+	0x77, 0x61, 0x72, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x52, 0x08, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x42, 0x0a, 0x0d, 0x72, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c,
+	0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65,
+	0x77, 0x61, 0x72, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x0d, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x40, 0x0a, 0x0c, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65,
+	0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52,
+	0x0c, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x2f, 0x0a,
+	0x03, 0x74, 0x6c, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x65, 0x2e,
+	0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65,
+	0x2e, 0x54, 0x4c, 0x53, 0x48, 0x00, 0x52, 0x03, 0x74, 0x6c, 0x73, 0x88, 0x01, 0x01, 0x12, 0x4d,
+	0x0a, 0x0d, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73,
+	0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x50, 0x72, 0x6f, 0x78,
+	0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x48, 0x01, 0x52, 0x0d, 0x70, 0x72, 0x6f,
+	0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x35, 0x0a,
+	0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d,
+	0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61,
+	0x72, 0x65, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x02, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x03, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x88, 0x01, 0x01, 0x12, 0x3e, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x65, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73,
+	0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x48, 0x04, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x74, 0x6c, 0x73, 0x42, 0x10, 0x0a, 0x0e, 0x5f,
+	0x70, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x42, 0x08, 0x0a,
+	0x06, 0x5f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x70, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x4a, 0x04, 0x08, 0x09, 0x10, 0x64, 0x22, 0xf6, 0x01, 0x0a, 0x15, 0x50, 0x72, 0x6f, 0x78, 0x79,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x47, 0x0a, 0x0d, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65,
+	0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x48,
+	0x00, 0x52, 0x0d, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x51, 0x0a, 0x12, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c,
+	0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x48, 0x01, 0x52,
+	0x12, 0x64, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x64, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22,
+	0xb8, 0x01, 0x0a, 0x0d, 0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x55, 0x0a, 0x0d, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67,
+	0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x50,
+	0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x4f, 0x76, 0x65, 0x72,
+	0x72, 0x69, 0x64, 0x65, 0x48, 0x00, 0x52, 0x0d, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x70, 0x72, 0x6f,
+	0x78, 0x79, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x22, 0xc8, 0x01, 0x0a, 0x06, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x32, 0x0a, 0x14, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x14, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x12, 0x36, 0x0a, 0x16, 0x64, 0x6f, 0x77,
+	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x16, 0x64, 0x6f, 0x77, 0x6e, 0x6c,
+	0x6f, 0x61, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x12, 0x24, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x54, 0x6f, 0x74,
+	0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x11, 0x6d, 0x61, 0x78, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x11, 0x6d, 0x61, 0x78, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d,
+	0x69, 0x6c, 0x6c, 0x69, 0x73, 0x22, 0xf8, 0x01, 0x0a, 0x08, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x35, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x21, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64,
+	0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x43,
+	0x6f, 0x64, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x3f, 0x0a, 0x06, 0x74, 0x61, 0x72,
+	0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6d, 0x65, 0x2e, 0x69,
+	0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e,
+	0x52, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x48, 0x00, 0x52,
+	0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x38, 0x0a, 0x06, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6d, 0x65, 0x2e,
+	0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65,
+	0x2e, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x48, 0x01, 0x52, 0x06, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x73, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0d, 0x73, 0x79, 0x6e, 0x63, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x73, 0x79, 0x6e,
+	0x63, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73,
+	0x22, 0xcb, 0x01, 0x0a, 0x0e, 0x4f, 0x70, 0x65, 0x6e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x3f, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f,
+	0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x43, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x0f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x41, 0x74, 0x4d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x12, 0x24,
+	0x0a, 0x0d, 0x62, 0x79, 0x74, 0x65, 0x73, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x62, 0x79, 0x74, 0x65, 0x73, 0x55, 0x70, 0x6c, 0x6f,
+	0x61, 0x64, 0x65, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x44, 0x6f, 0x77,
+	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x22, 0x93,
+	0x01, 0x0a, 0x0f, 0x4f, 0x70, 0x65, 0x6e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x39, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e,
+	0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x45, 0x0a,
+	0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65,
+	0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x43, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0x1a, 0x0a, 0x08, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x2a, 0x1c, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x07, 0x0a, 0x03,
+	0x55, 0x44, 0x50, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x43, 0x50, 0x10, 0x01, 0x2a, 0x26,
+	0x0a, 0x0c, 0x44, 0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x0a,
+	0x0a, 0x06, 0x41, 0x43, 0x43, 0x45, 0x50, 0x54, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45,
+	0x4a, 0x45, 0x43, 0x54, 0x10, 0x01, 0x2a, 0x60, 0x0a, 0x0b, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4c, 0x4f, 0x53, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x1c, 0x0a, 0x18, 0x4d, 0x41, 0x58, 0x5f, 0x54, 0x4f, 0x54, 0x41, 0x4c, 0x5f, 0x42,
+	0x59, 0x54, 0x45, 0x53, 0x5f, 0x45, 0x58, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x01, 0x12,
+	0x19, 0x0a, 0x15, 0x4d, 0x41, 0x58, 0x5f, 0x44, 0x55, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x45, 0x58, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x45,
+	0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x03, 0x32, 0xb0, 0x03, 0x0a, 0x0a, 0x4e, 0x65, 0x65,
+	0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x12, 0x50, 0x0a, 0x0c, 0x6f, 0x6e, 0x43, 0x6f, 0x6e,
+	0x6e, 0x4f, 0x70, 0x65, 0x6e, 0x65, 0x64, 0x12, 0x1f, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f,
+	0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x43, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x1d, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67,
+	0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x44,
+	0x65, 0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0c, 0x6f, 0x6e, 0x43,
+	0x6f, 0x6e, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x12, 0x25, 0x2e, 0x6d, 0x65, 0x2e, 0x69,
+	0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72, 0x65, 0x2e,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64,
+	0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x59, 0x0a, 0x11, 0x6f, 0x6e,
+	0x43, 0x6f, 0x6e, 0x6e, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12,
+	0x2a, 0x2e, 0x6d, 0x65, 0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c,
+	0x65, 0x77, 0x61, 0x72, 0x65, 0x2e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x43, 0x6c, 0x6f, 0x73, 0x65, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x1a, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x4d, 0x0a, 0x12, 0x6f, 0x6e, 0x49, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x53, 0x68, 0x75, 0x74, 0x64, 0x6f, 0x77, 0x6e, 0x12, 0x1d, 0x2e, 0x6d, 0x65,
+	0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72,
+	0x65, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x13, 0x73, 0x79, 0x6e, 0x63, 0x4f, 0x70, 0x65, 0x6e,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x24, 0x2e, 0x6d, 0x65,
+	0x2e, 0x69, 0x67, 0x6f, 0x70, 0x73, 0x2e, 0x6e, 0x65, 0x65, 0x64, 0x6c, 0x65, 0x77, 0x61, 0x72,
+	0x65, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x42, 0x06, 0x5a, 0x04, 0x2e,
+	0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_needleware_proto_rawDescOnce sync.Once
+	file_proto_needleware_proto_rawDescData = file_proto_needleware_proto_rawDesc
+)
+
+func file_proto_needleware_proto_rawDescGZIP() []byte {
+	file_proto_needleware_proto_rawDescOnce.Do(func() {
+		file_proto_needleware_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_needleware_proto_rawDescData)
+	})
+	return file_proto_needleware_proto_rawDescData
+}
+
+var file_proto_needleware_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_proto_needleware_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_proto_needleware_proto_goTypes = []interface{}{
+	(Protocol)(0),                 // 0: me.igops.needleware.Protocol
+	(DecisionCode)(0),             // 1: me.igops.needleware.DecisionCode
+	(CloseReason)(0),              // 2: me.igops.needleware.CloseReason
+	(*ConnectionId)(nil),          // 3: me.igops.needleware.ConnectionId
+	(*ConnectionClosed)(nil),      // 4: me.igops.needleware.ConnectionClosed
+	(*ConnectionClosedBatch)(nil), // 5: me.igops.needleware.ConnectionClosedBatch
+	(*Address)(nil),               // 6: me.igops.needleware.Address
+	(*Metadata)(nil),              // 7: me.igops.needleware.Metadata
+	(*ClientCertificate)(nil),     // 8: me.igops.needleware.ClientCertificate
+	(*TLS)(nil),                   // 9: me.igops.needleware.TLS
+	(*ProxyProtocol)(nil),         // 10: me.igops.needleware.ProxyProtocol
+	(*Route)(nil),                 // 11: me.igops.needleware.Route
+	(*Connection)(nil),            // 12: me.igops.needleware.Connection
+	(*ProxyProtocolOverride)(nil), // 13: me.igops.needleware.ProxyProtocolOverride
+	(*RoutingTarget)(nil),         // 14: me.igops.needleware.RoutingTarget
+	(*Limits)(nil),                // 15: me.igops.needleware.Limits
+	(*Decision)(nil),              // 16: me.igops.needleware.Decision
+	(*OpenConnection)(nil),        // 17: me.igops.needleware.OpenConnection
+	(*OpenConnections)(nil),       // 18: me.igops.needleware.OpenConnections
+	(*Instance)(nil),              // 19: me.igops.needleware.Instance
+	nil,                           // 20: me.igops.needleware.Metadata.DataEntry
+	nil,                           // 21: me.igops.needleware.ProxyProtocol.TlvsEntry
+	(*emptypb.Empty)(nil),         // 22: google.protobuf.Empty
+}
+var file_proto_needleware_proto_depIdxs = []int32{
+	2,  // 0: me.igops.needleware.ConnectionClosed.reason:type_name -> me.igops.needleware.CloseReason
+	4,  // 1: me.igops.needleware.ConnectionClosedBatch.events:type_name -> me.igops.needleware.ConnectionClosed
+	20, // 2: me.igops.needleware.Metadata.data:type_name -> me.igops.needleware.Metadata.DataEntry
+	8,  // 3: me.igops.needleware.TLS.clientCertificate:type_name -> me.igops.needleware.ClientCertificate
+	6,  // 4: me.igops.needleware.ProxyProtocol.sourceAddress:type_name -> me.igops.needleware.Address
+	6,  // 5: me.igops.needleware.ProxyProtocol.destinationAddress:type_name -> me.igops.needleware.Address
+	6,  // 6: me.igops.needleware.ProxyProtocol.peerAddress:type_name -> me.igops.needleware.Address
+	21, // 7: me.igops.needleware.ProxyProtocol.tlvs:type_name -> me.igops.needleware.ProxyProtocol.TlvsEntry
+	3,  // 8: me.igops.needleware.Connection.id:type_name -> me.igops.needleware.ConnectionId
+	0,  // 9: me.igops.needleware.Connection.protocol:type_name -> me.igops.needleware.Protocol
+	6,  // 10: me.igops.needleware.Connection.remoteAddress:type_name -> me.igops.needleware.Address
+	6,  // 11: me.igops.needleware.Connection.localAddress:type_name -> me.igops.needleware.Address
+	9,  // 12: me.igops.needleware.Connection.tls:type_name -> me.igops.needleware.TLS
+	10, // 13: me.igops.needleware.Connection.proxyProtocol:type_name -> me.igops.needleware.ProxyProtocol
+	11, // 14: me.igops.needleware.Connection.route:type_name -> me.igops.needleware.Route
+	7,  // 15: me.igops.needleware.Connection.metadata:type_name -> me.igops.needleware.Metadata
+	6,  // 16: me.igops.needleware.ProxyProtocolOverride.sourceAddress:type_name -> me.igops.needleware.Address
+	6,  // 17: me.igops.needleware.ProxyProtocolOverride.destinationAddress:type_name -> me.igops.needleware.Address
+	13, // 18: me.igops.needleware.RoutingTarget.proxyProtocol:type_name -> me.igops.needleware.ProxyProtocolOverride
+	1,  // 19: me.igops.needleware.Decision.code:type_name -> me.igops.needleware.DecisionCode
+	14, // 20: me.igops.needleware.Decision.target:type_name -> me.igops.needleware.RoutingTarget
+	15, // 21: me.igops.needleware.Decision.limits:type_name -> me.igops.needleware.Limits
+	12, // 22: me.igops.needleware.OpenConnection.connection:type_name -> me.igops.needleware.Connection
+	19, // 23: me.igops.needleware.OpenConnections.instance:type_name -> me.igops.needleware.Instance
+	17, // 24: me.igops.needleware.OpenConnections.connections:type_name -> me.igops.needleware.OpenConnection
+	12, // 25: me.igops.needleware.Needleware.onConnOpened:input_type -> me.igops.needleware.Connection
+	4,  // 26: me.igops.needleware.Needleware.onConnClosed:input_type -> me.igops.needleware.ConnectionClosed
+	5,  // 27: me.igops.needleware.Needleware.onConnClosedBatch:input_type -> me.igops.needleware.ConnectionClosedBatch
+	19, // 28: me.igops.needleware.Needleware.onInstanceShutdown:input_type -> me.igops.needleware.Instance
+	18, // 29: me.igops.needleware.Needleware.syncOpenConnections:input_type -> me.igops.needleware.OpenConnections
+	16, // 30: me.igops.needleware.Needleware.onConnOpened:output_type -> me.igops.needleware.Decision
+	22, // 31: me.igops.needleware.Needleware.onConnClosed:output_type -> google.protobuf.Empty
+	22, // 32: me.igops.needleware.Needleware.onConnClosedBatch:output_type -> google.protobuf.Empty
+	22, // 33: me.igops.needleware.Needleware.onInstanceShutdown:output_type -> google.protobuf.Empty
+	22, // 34: me.igops.needleware.Needleware.syncOpenConnections:output_type -> google.protobuf.Empty
+	30, // [30:35] is the sub-list for method output_type
+	25, // [25:30] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
+}
+
+func init() { file_proto_needleware_proto_init() }
+func file_proto_needleware_proto_init() {
+	if File_proto_needleware_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_needleware_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConnectionId); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_needleware_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConnectionClosed); i {
+			case 0:
+				return &v.state
 			case 1:
 				return &v.sizeCache
 			case 2:
@@ -529,7 +1677,7 @@ func file_proto_needleware_proto_init() {
 			}
 		}
 		file_proto_needleware_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Metadata); i {
+			switch v := v.(*ConnectionClosedBatch); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -541,7 +1689,7 @@ func file_proto_needleware_proto_init() {
 			}
 		}
 		file_proto_needleware_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Connection); i {
+			switch v := v.(*Address); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -553,6 +1701,114 @@ func file_proto_needleware_proto_init() {
 			}
 		}
 		file_proto_needleware_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Metadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_needleware_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClientCertificate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_needleware_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TLS); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_needleware_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProxyProtocol); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_needleware_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Route); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_needleware_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Connection); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_needleware_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProxyProtocolOverride); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_needleware_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RoutingTarget); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_needleware_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Limits); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_needleware_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Decision); i {
 			case 0:
 				return &v.state
@@ -564,15 +1820,55 @@ func file_proto_needleware_proto_init() {
 				return nil
 			}
 		}
+		file_proto_needleware_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OpenConnection); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_needleware_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OpenConnections); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_needleware_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Instance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_proto_needleware_proto_msgTypes[3].OneofWrappers = []interface{}{}
+	file_proto_needleware_proto_msgTypes[6].OneofWrappers = []interface{}{}
+	file_proto_needleware_proto_msgTypes[9].OneofWrappers = []interface{}{}
+	file_proto_needleware_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	file_proto_needleware_proto_msgTypes[11].OneofWrappers = []interface{}{}
+	file_proto_needleware_proto_msgTypes[13].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_needleware_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   6,
+			NumEnums:      3,
+			NumMessages:   19,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -24,7 +24,10 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type NeedlewareClient interface {
 	OnConnOpened(ctx context.Context, in *Connection, opts ...grpc.CallOption) (*Decision, error)
-	OnConnClosed(ctx context.Context, in *ConnectionId, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	OnConnClosed(ctx context.Context, in *ConnectionClosed, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	OnConnClosedBatch(ctx context.Context, in *ConnectionClosedBatch, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	OnInstanceShutdown(ctx context.Context, in *Instance, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SyncOpenConnections(ctx context.Context, in *OpenConnections, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type needlewareClient struct {
@@ -44,7 +47,7 @@ func (c *needlewareClient) OnConnOpened(ctx context.Context, in *Connection, opt
 	return out, nil
 }
 
-func (c *needlewareClient) OnConnClosed(ctx context.Context, in *ConnectionId, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+func (c *needlewareClient) OnConnClosed(ctx context.Context, in *ConnectionClosed, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/me.igops.needleware.Needleware/onConnClosed", in, out, opts...)
 	if err != nil {
@@ -53,12 +56,42 @@ func (c *needlewareClient) OnConnClosed(ctx context.Context, in *ConnectionId, o
 	return out, nil
 }
 
+func (c *needlewareClient) OnConnClosedBatch(ctx context.Context, in *ConnectionClosedBatch, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/me.igops.needleware.Needleware/onConnClosedBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *needlewareClient) OnInstanceShutdown(ctx context.Context, in *Instance, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/me.igops.needleware.Needleware/onInstanceShutdown", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *needlewareClient) SyncOpenConnections(ctx context.Context, in *OpenConnections, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/me.igops.needleware.Needleware/syncOpenConnections", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NeedlewareServer is the server API for Needleware service.
 // All implementations should embed UnimplementedNeedlewareServer
 // for forward compatibility
 type NeedlewareServer interface {
 	OnConnOpened(context.Context, *Connection) (*Decision, error)
-	OnConnClosed(context.Context, *ConnectionId) (*emptypb.Empty, error)
+	OnConnClosed(context.Context, *ConnectionClosed) (*emptypb.Empty, error)
+	OnConnClosedBatch(context.Context, *ConnectionClosedBatch) (*emptypb.Empty, error)
+	OnInstanceShutdown(context.Context, *Instance) (*emptypb.Empty, error)
+	SyncOpenConnections(context.Context, *OpenConnections) (*emptypb.Empty, error)
 }
 
 // UnimplementedNeedlewareServer should be embedded to have forward compatible implementations.
@@ -68,9 +101,18 @@ type UnimplementedNeedlewareServer struct {
 func (UnimplementedNeedlewareServer) OnConnOpened(context.Context, *Connection) (*Decision, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method OnConnOpened not implemented")
 }
-func (UnimplementedNeedlewareServer) OnConnClosed(context.Context, *ConnectionId) (*emptypb.Empty, error) {
+func (UnimplementedNeedlewareServer) OnConnClosed(context.Context, *ConnectionClosed) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method OnConnClosed not implemented")
 }
+func (UnimplementedNeedlewareServer) OnConnClosedBatch(context.Context, *ConnectionClosedBatch) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OnConnClosedBatch not implemented")
+}
+func (UnimplementedNeedlewareServer) OnInstanceShutdown(context.Context, *Instance) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OnInstanceShutdown not implemented")
+}
+func (UnimplementedNeedlewareServer) SyncOpenConnections(context.Context, *OpenConnections) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncOpenConnections not implemented")
+}
 
 // UnsafeNeedlewareServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to NeedlewareServer will
@@ -102,7 +144,7 @@ func _Needleware_OnConnOpened_Handler(srv interface{}, ctx context.Context, dec
 }
 
 func _Needleware_OnConnClosed_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ConnectionId)
+	in := new(ConnectionClosed)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -114,7 +156,61 @@ func _Needleware_OnConnClosed_Handler(srv interface{}, ctx context.Context, dec
 		FullMethod: "/me.igops.needleware.Needleware/onConnClosed",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(NeedlewareServer).OnConnClosed(ctx, req.(*ConnectionId))
+		return srv.(NeedlewareServer).OnConnClosed(ctx, req.(*ConnectionClosed))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Needleware_OnConnClosedBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectionClosedBatch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeedlewareServer).OnConnClosedBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/me.igops.needleware.Needleware/onConnClosedBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeedlewareServer).OnConnClosedBatch(ctx, req.(*ConnectionClosedBatch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Needleware_OnInstanceShutdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Instance)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeedlewareServer).OnInstanceShutdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/me.igops.needleware.Needleware/onInstanceShutdown",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeedlewareServer).OnInstanceShutdown(ctx, req.(*Instance))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Needleware_SyncOpenConnections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenConnections)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NeedlewareServer).SyncOpenConnections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/me.igops.needleware.Needleware/syncOpenConnections",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NeedlewareServer).SyncOpenConnections(ctx, req.(*OpenConnections))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -134,6 +230,18 @@ var Needleware_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "onConnClosed",
 			Handler:    _Needleware_OnConnClosed_Handler,
 		},
+		{
+			MethodName: "onConnClosedBatch",
+			Handler:    _Needleware_OnConnClosedBatch_Handler,
+		},
+		{
+			MethodName: "onInstanceShutdown",
+			Handler:    _Needleware_OnInstanceShutdown_Handler,
+		},
+		{
+			MethodName: "syncOpenConnections",
+			Handler:    _Needleware_SyncOpenConnections_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/needleware.proto",
@@ -0,0 +1,437 @@
+package needleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/rs/zerolog"
+	"github.com/traefik/traefik/v3/pkg/metrics"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+)
+
+type overflowPolicy int
+
+const (
+	overflowDropOldest overflowPolicy = iota
+	overflowDropNewest
+	overflowBlock
+)
+
+func (p overflowPolicy) String() string {
+	switch p {
+	case overflowDropNewest:
+		return "dropNewest"
+	case overflowBlock:
+		return "block"
+	default:
+		return "dropOldest"
+	}
+}
+
+type closeQueueConfig struct {
+	queueSize       int
+	batchSize       int
+	flushInterval   time.Duration
+	onOverflow      overflowPolicy
+	spoolDir        string
+	deliveryTimeout time.Duration
+}
+
+// spoolCompactionThreshold is the number of events delivered or dropped after which the spool is compacted,
+// the events being marked as done in the spool until then.
+const spoolCompactionThreshold = 1024
+
+type queuedEvent struct {
+	seq   uint64
+	event *client.CloseEvent
+}
+
+// closeQueue delivers the close events of a needle in batches, retrying with an exponential backoff.
+// It outlives the needles built for a configuration, so that pending events survive a reload.
+type closeQueue struct {
+	name   string
+	logger zerolog.Logger
+
+	queueGauge       gokitmetrics.Gauge
+	deliveredCounter gokitmetrics.Counter
+	droppedCounter   gokitmetrics.Counter
+	retriesCounter   gokitmetrics.Counter
+
+	mu      sync.Mutex
+	notFull *sync.Cond
+	config  closeQueueConfig
+	client  client.Client
+	spool   *closeSpool
+	// spoolDone is the number of lines of the spool which are not pending events anymore.
+	spoolDone int
+	events    []queuedEvent
+	nextSeq   uint64
+	// running tells whether the delivery loop is running, which is the case as long as events are pending.
+	running bool
+	flushCh chan struct{}
+	// flushing tells that the events are delivered without waiting for the flush interval.
+	flushing bool
+	// drained are closed once there are no events left.
+	drained []chan struct{}
+	// deliveryCtx ends the delivery in progress, when the configuration is updated or the queue is closed.
+	deliveryCtx    context.Context
+	cancelDelivery context.CancelFunc
+	// closed tells that the events are not delivered anymore, they are only spooled.
+	closed bool
+}
+
+func newCloseQueue(name string, logger zerolog.Logger, registry metrics.Registry, config closeQueueConfig, c client.Client) *closeQueue {
+	q := &closeQueue{
+		name:             name,
+		logger:           logger,
+		queueGauge:       registry.NeedleCloseEventsQueueGauge().With("needle", name),
+		deliveredCounter: registry.NeedleCloseEventsDeliveredCounter().With("needle", name),
+		droppedCounter:   registry.NeedleCloseEventsDroppedCounter(),
+		retriesCounter:   registry.NeedleCloseEventsRetriesCounter().With("needle", name),
+		config:           config,
+		client:           c,
+		flushCh:          make(chan struct{}, 1),
+	}
+	q.notFull = sync.NewCond(&q.mu)
+	q.deliveryCtx, q.cancelDelivery = context.WithCancel(context.Background())
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.openSpool()
+
+	return q
+}
+
+// update applies the configuration of a newly built needle, keeping the pending events.
+func (q *closeQueue) update(config closeQueueConfig, c client.Client) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	spoolDirChanged := config.spoolDir != q.config.spoolDir
+	q.config = config
+	q.client = c
+
+	if spoolDirChanged {
+		if q.spool != nil {
+			if err := q.spool.close(); err != nil {
+				q.logger.Error().Err(err).Msg("Failed to close the close events spool")
+			}
+			q.spool = nil
+		}
+		q.openSpool()
+	}
+
+	// The delivery in progress is retried with the new client.
+	if !q.closed {
+		q.cancelDelivery()
+		q.deliveryCtx, q.cancelDelivery = context.WithCancel(context.Background())
+	}
+
+	// The queue may have been shrunk or the batch size reduced.
+	q.notFull.Broadcast()
+	q.signalFlush()
+}
+
+// close stops the delivery of the events, the pending and new ones being only spooled.
+// It is called once the instance is shutting down, after the last flush.
+func (q *closeQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	q.cancelDelivery()
+	q.notFull.Broadcast()
+	q.signalFlush()
+}
+
+// currentClient returns the client of the last configuration.
+func (q *closeQueue) currentClient() client.Client {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.client
+}
+
+// openSpool opens the spool of the configuration, if any, and queues the events it holds.
+// It must be called with the lock held.
+func (q *closeQueue) openSpool() {
+	if q.config.spoolDir == "" {
+		return
+	}
+
+	spool, events, err := openCloseSpool(q.config.spoolDir, q.name)
+	if err != nil {
+		q.logger.Error().Err(err).Msgf("Failed to open the close events spool in %s, events will not survive a restart", q.config.spoolDir)
+		return
+	}
+	q.spool = spool
+
+	for _, event := range events {
+		q.enqueue(event)
+	}
+	q.compactSpool()
+
+	if len(events) > 0 {
+		q.logger.Info().Msgf("Restored %d close events from the spool", len(events))
+	}
+}
+
+// push queues an event for delivery, applying the overflow policy if the queue is full.
+// With the block policy, it waits for room at most for the delivery timeout, and then drops the event.
+func (q *closeQueue) push(event *client.CloseEvent) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	deadline := time.Now().Add(q.config.deliveryTimeout)
+
+	for len(q.events) >= q.config.queueSize {
+		switch q.config.onOverflow {
+		case overflowDropNewest:
+			q.droppedCounter.With("needle", q.name, "policy", q.config.onOverflow.String()).Add(1)
+			q.logger.Debug().Msg("Close events queue is full, dropping the newest event")
+			return
+		case overflowBlock:
+			if q.closed || !q.waitNotFull(deadline) {
+				q.droppedCounter.With("needle", q.name, "policy", q.config.onOverflow.String()).Add(1)
+				q.logger.Debug().Msg("Close events queue is still full, dropping the newest event")
+				return
+			}
+		default:
+			q.droppedCounter.With("needle", q.name, "policy", q.config.onOverflow.String()).Add(1)
+			q.logger.Debug().Msg("Close events queue is full, dropping the oldest event")
+			q.remove(1)
+		}
+	}
+
+	q.enqueue(event)
+
+	if q.spool != nil {
+		if err := q.spool.append(q.events[len(q.events)-1]); err != nil {
+			q.logger.Error().Err(err).Msg("Failed to spool close event")
+		}
+	}
+}
+
+// waitNotFull waits for the queue to change, returning false if the deadline is exceeded.
+// It must be called with the lock held.
+func (q *closeQueue) waitNotFull(deadline time.Time) bool {
+	wait := time.Until(deadline)
+	if wait <= 0 {
+		return false
+	}
+
+	timer := time.AfterFunc(wait, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		q.notFull.Broadcast()
+	})
+	defer timer.Stop()
+
+	q.notFull.Wait()
+	return true
+}
+
+// enqueue appends an event and makes sure it is going to be delivered.
+// It must be called with the lock held.
+func (q *closeQueue) enqueue(event *client.CloseEvent) {
+	q.nextSeq++
+	q.events = append(q.events, queuedEvent{seq: q.nextSeq, event: event})
+	q.queueGauge.Set(float64(len(q.events)))
+
+	if len(q.events) >= q.config.batchSize {
+		q.signalFlush()
+	}
+
+	if !q.running && !q.closed {
+		q.running = true
+		go q.run()
+	}
+}
+
+func (q *closeQueue) signalFlush() {
+	select {
+	case q.flushCh <- struct{}{}:
+	default:
+	}
+}
+
+// flush delivers the pending events without waiting for the flush interval,
+// until there are none left or the context is done. It returns the number of events left.
+func (q *closeQueue) flush(ctx context.Context) int {
+	q.mu.Lock()
+	if len(q.events) == 0 {
+		q.mu.Unlock()
+		return 0
+	}
+	q.flushing = true
+	drained := make(chan struct{})
+	q.drained = append(q.drained, drained)
+	q.mu.Unlock()
+
+	q.signalFlush()
+
+	select {
+	case <-drained:
+		return 0
+	case <-ctx.Done():
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return len(q.events)
+	}
+}
+
+// run delivers the pending events until there are none left.
+func (q *closeQueue) run() {
+	for {
+		q.mu.Lock()
+		if len(q.events) == 0 {
+			q.running = false
+			q.flushing = false
+			for _, drained := range q.drained {
+				close(drained)
+			}
+			q.drained = nil
+			q.mu.Unlock()
+			return
+		}
+		full := q.flushing || len(q.events) >= q.config.batchSize
+		flushInterval := q.config.flushInterval
+		q.mu.Unlock()
+
+		if !full {
+			timer := time.NewTimer(flushInterval)
+			select {
+			case <-timer.C:
+			case <-q.flushCh:
+				timer.Stop()
+			}
+		}
+
+		batch := q.nextBatch()
+		if len(batch) == 0 {
+			continue
+		}
+
+		if err := q.deliver(batch); err != nil {
+			q.mu.Lock()
+			if q.closed {
+				q.running = false
+				q.mu.Unlock()
+				return
+			}
+			q.mu.Unlock()
+
+			// The configuration has been updated, the batch is delivered with the new one.
+			continue
+		}
+
+		q.ack(batch[len(batch)-1].seq)
+	}
+}
+
+func (q *closeQueue) nextBatch() []queuedEvent {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	size := len(q.events)
+	if size > q.config.batchSize {
+		size = q.config.batchSize
+	}
+
+	batch := make([]queuedEvent, size)
+	copy(batch, q.events)
+	return batch
+}
+
+// deliver sends the batch, retrying until it succeeds,
+// or until the configuration is updated or the queue closed.
+func (q *closeQueue) deliver(batch []queuedEvent) error {
+	events := make([]*client.CloseEvent, len(batch))
+	for i, event := range batch {
+		events[i] = event.event
+	}
+
+	q.mu.Lock()
+	deliveryCtx := q.deliveryCtx
+	q.mu.Unlock()
+
+	operation := func() error {
+		q.mu.Lock()
+		c, timeout := q.client, q.config.deliveryTimeout
+		q.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(deliveryCtx, timeout)
+		defer cancel()
+
+		return c.OnConnClosedBatch(events, ctx)
+	}
+
+	notify := func(err error, d time.Duration) {
+		q.retriesCounter.Add(1)
+		q.logger.Warn().Err(err).Msgf("Cannot deliver %d close events, retrying in %s", len(events), d)
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.MaxElapsedTime = 0
+
+	return backoff.RetryNotify(operation, backoff.WithContext(b, deliveryCtx), notify)
+}
+
+// ack removes the delivered events, up to the given sequence number.
+func (q *closeQueue) ack(seq uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delivered := 0
+	for delivered < len(q.events) && q.events[delivered].seq <= seq {
+		delivered++
+	}
+
+	q.deliveredCounter.Add(float64(delivered))
+	q.remove(delivered)
+	q.notFull.Broadcast()
+}
+
+// remove removes the given number of events at the head of the queue, marking them as done in the spool.
+// It must be called with the lock held.
+func (q *closeQueue) remove(count int) {
+	if count == 0 {
+		return
+	}
+
+	seq := q.events[count-1].seq
+	q.events = q.events[count:]
+	q.queueGauge.Set(float64(len(q.events)))
+
+	if q.spool == nil {
+		return
+	}
+
+	if err := q.spool.markDone(seq); err != nil {
+		q.logger.Error().Err(err).Msg("Failed to mark close events as done in the spool")
+	}
+	q.spoolDone += count + 1
+
+	if q.spoolDone >= spoolCompactionThreshold {
+		q.compactSpool()
+	}
+}
+
+// compactSpool rewrites the spool with the pending events only, and updates the queue gauge.
+// It must be called with the lock held.
+func (q *closeQueue) compactSpool() {
+	q.queueGauge.Set(float64(len(q.events)))
+
+	if q.spool == nil {
+		return
+	}
+	if err := q.spool.rewrite(q.events); err != nil {
+		q.logger.Error().Err(err).Msg("Failed to rewrite the close events spool")
+		return
+	}
+	q.spoolDone = 0
+}
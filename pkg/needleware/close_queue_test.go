@@ -0,0 +1,296 @@
+package needleware
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/metrics"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"github.com/traefik/traefik/v3/pkg/needleware/client/clienttest"
+)
+
+func TestCloseQueue_Batches(t *testing.T) {
+	c := &clienttest.Client{}
+	q := newTestCloseQueue(t, closeQueueConfig{queueSize: 10, batchSize: 2, flushInterval: time.Hour}, c)
+
+	for i := int32(1); i <= 4; i++ {
+		q.push(&client.CloseEvent{ConnId: i})
+	}
+
+	assert.Eventually(t, func() bool { return len(connIDBatches(c)) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, [][]int32{{1, 2}, {3, 4}}, connIDBatches(c))
+}
+
+func TestCloseQueue_FlushInterval(t *testing.T) {
+	c := &clienttest.Client{}
+	q := newTestCloseQueue(t, closeQueueConfig{queueSize: 10, batchSize: 100, flushInterval: 10 * time.Millisecond}, c)
+
+	q.push(&client.CloseEvent{ConnId: 1})
+
+	assert.Eventually(t, func() bool { return len(connIDBatches(c)) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, [][]int32{{1}}, connIDBatches(c))
+}
+
+func TestCloseQueue_Retry(t *testing.T) {
+	failures := 1
+	c := &clienttest.Client{OnClose: func([]*client.CloseEvent) error {
+		if failures > 0 {
+			failures--
+			return errors.New("unavailable")
+		}
+		return nil
+	}}
+	q := newTestCloseQueue(t, closeQueueConfig{queueSize: 10, batchSize: 1, flushInterval: time.Hour}, c)
+
+	q.push(&client.CloseEvent{ConnId: 1})
+
+	assert.Eventually(t, func() bool { return len(connIDBatches(c)) == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, [][]int32{{1}}, connIDBatches(c))
+}
+
+func TestCloseQueue_Overflow(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		policy   overflowPolicy
+		expected []int32
+	}{
+		{
+			desc:     "drop oldest",
+			policy:   overflowDropOldest,
+			expected: []int32{2, 3},
+		},
+		{
+			desc:     "drop newest",
+			policy:   overflowDropNewest,
+			expected: []int32{1, 2},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			// The flush interval keeps the events in the queue for the duration of the test.
+			q := newTestCloseQueue(t, closeQueueConfig{queueSize: 2, batchSize: 10, flushInterval: time.Hour, onOverflow: test.policy}, &clienttest.Client{})
+
+			for i := int32(1); i <= 3; i++ {
+				q.push(&client.CloseEvent{ConnId: i})
+			}
+
+			assert.Equal(t, test.expected, q.pendingConnIDs())
+		})
+	}
+}
+
+func TestCloseQueue_OverflowBlock(t *testing.T) {
+	c := &clienttest.Client{}
+	q := newTestCloseQueue(t, closeQueueConfig{queueSize: 1, batchSize: 10, flushInterval: 100 * time.Millisecond, onOverflow: overflowBlock}, c)
+
+	q.push(&client.CloseEvent{ConnId: 1})
+
+	pushed := make(chan struct{})
+	go func() {
+		q.push(&client.CloseEvent{ConnId: 2})
+		close(pushed)
+	}()
+
+	select {
+	case <-pushed:
+		t.Fatal("push should block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	select {
+	case <-pushed:
+	case <-time.After(time.Second):
+		t.Fatal("push should be unblocked once the queue has been delivered")
+	}
+
+	assert.Eventually(t, func() bool { return len(connIDBatches(c)) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, [][]int32{{1}, {2}}, connIDBatches(c))
+}
+
+func TestCloseQueue_OverflowBlock_Bounded(t *testing.T) {
+	unavailable := &clienttest.Client{OnClose: func([]*client.CloseEvent) error {
+		return errors.New("unavailable")
+	}}
+	config := closeQueueConfig{queueSize: 1, batchSize: 10, flushInterval: time.Hour, onOverflow: overflowBlock}
+	q := newTestCloseQueue(t, config, unavailable)
+	config.deliveryTimeout = 50 * time.Millisecond
+	q.update(config, unavailable)
+
+	q.push(&client.CloseEvent{ConnId: 1})
+
+	// The push gives up once the delivery timeout is exceeded.
+	start := time.Now()
+	q.push(&client.CloseEvent{ConnId: 2})
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, []int32{1}, q.pendingConnIDs())
+
+	// The push does not wait at all once the queue is closed.
+	config.deliveryTimeout = time.Hour
+	q.update(config, unavailable)
+	q.close()
+	q.push(&client.CloseEvent{ConnId: 3})
+	assert.Equal(t, []int32{1}, q.pendingConnIDs())
+}
+
+func TestCloseQueue_Close(t *testing.T) {
+	unavailable := &clienttest.Client{OnClose: func([]*client.CloseEvent) error {
+		return errors.New("unavailable")
+	}}
+	q := newTestCloseQueue(t, closeQueueConfig{queueSize: 10, batchSize: 1, flushInterval: time.Hour}, unavailable)
+
+	q.push(&client.CloseEvent{ConnId: 1})
+	q.close()
+
+	// The delivery retries end, the event staying pending.
+	assert.Eventually(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		return !q.running
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int32{1}, q.pendingConnIDs())
+}
+
+func TestCloseQueue_Update_Retry(t *testing.T) {
+	attempted := make(chan struct{}, 1)
+	unavailable := &clienttest.Client{OnClose: func([]*client.CloseEvent) error {
+		select {
+		case attempted <- struct{}{}:
+		default:
+		}
+		return errors.New("unavailable")
+	}}
+	config := closeQueueConfig{queueSize: 10, batchSize: 1, flushInterval: time.Hour}
+	q := newTestCloseQueue(t, config, unavailable)
+
+	q.push(&client.CloseEvent{ConnId: 1})
+	<-attempted
+
+	// The batch is delivered with the new client, without waiting for the backoff.
+	c := &clienttest.Client{}
+	config.deliveryTimeout = time.Second
+	q.update(config, c)
+
+	assert.Eventually(t, func() bool { return len(connIDBatches(c)) == 1 }, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestCloseQueue_Spool_DropOldest(t *testing.T) {
+	dir := t.TempDir()
+	config := closeQueueConfig{queueSize: 2, batchSize: 10, flushInterval: time.Hour, spoolDir: dir}
+
+	q := newTestCloseQueue(t, config, &clienttest.Client{})
+	for i := int32(1); i <= 3; i++ {
+		q.push(&client.CloseEvent{ConnId: i})
+	}
+
+	// The dropped event is marked as done, the spool is not rewritten.
+	content, err := os.ReadFile(q.spool.path)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 4)
+
+	restored := newTestCloseQueue(t, config, &clienttest.Client{})
+	assert.Equal(t, []int32{2, 3}, restored.pendingConnIDs())
+}
+
+func TestCloseQueue_Spool(t *testing.T) {
+	dir := t.TempDir()
+	config := closeQueueConfig{queueSize: 10, batchSize: 10, flushInterval: time.Hour, spoolDir: dir}
+
+	q := newTestCloseQueue(t, config, &clienttest.Client{})
+	q.push(&client.CloseEvent{ConnId: 1})
+	q.push(&client.CloseEvent{ConnId: 2, Reason: client.CloseReasonMaxDurationExceeded})
+
+	// A new queue for the same needle, as created after a restart, restores the pending events.
+	c := &clienttest.Client{}
+	restored := newTestCloseQueue(t, config, c)
+	assert.Equal(t, []int32{1, 2}, restored.pendingConnIDs())
+	spoolPath := restored.spool.path
+
+	restored.update(closeQueueConfig{queueSize: 10, batchSize: 1, flushInterval: time.Hour, spoolDir: dir}, c)
+
+	assert.Eventually(t, func() bool { return len(connIDBatches(c)) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		events, err := (&closeSpool{path: spoolPath}).read()
+		return err == nil && len(events) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCloseQueue_Flush(t *testing.T) {
+	c := &clienttest.Client{}
+	q := newTestCloseQueue(t, closeQueueConfig{queueSize: 10, batchSize: 2, flushInterval: time.Hour}, c)
+
+	for i := int32(1); i <= 3; i++ {
+		q.push(&client.CloseEvent{ConnId: i})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.Equal(t, 0, q.flush(ctx))
+	assert.Equal(t, [][]int32{{1, 2}, {3}}, connIDBatches(c))
+}
+
+func TestCloseQueue_Flush_Timeout(t *testing.T) {
+	c := &clienttest.Client{OnClose: func([]*client.CloseEvent) error {
+		return errors.New("unavailable")
+	}}
+	q := newTestCloseQueue(t, closeQueueConfig{queueSize: 10, batchSize: 10, flushInterval: time.Hour}, c)
+
+	q.push(&client.CloseEvent{ConnId: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, 1, q.flush(ctx))
+}
+
+func newTestCloseQueue(t *testing.T, config closeQueueConfig, c client.Client) *closeQueue {
+	t.Helper()
+
+	config.deliveryTimeout = time.Second
+	q := newCloseQueue("foo", zerolog.Nop(), metrics.NewVoidRegistry(), config, c)
+	t.Cleanup(func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		if q.spool != nil {
+			require.NoError(t, q.spool.close())
+		}
+	})
+
+	return q
+}
+
+func (q *closeQueue) pendingConnIDs() []int32 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var ids []int32
+	for _, event := range q.events {
+		ids = append(ids, event.event.ConnId)
+	}
+	return ids
+}
+
+func connIDBatches(c *clienttest.Client) [][]int32 {
+	var batches [][]int32
+	for _, batch := range c.Batches() {
+		var ids []int32
+		for _, event := range batch {
+			ids = append(ids, event.ConnId)
+		}
+		batches = append(batches, ids)
+	}
+	return batches
+}
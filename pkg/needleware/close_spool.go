@@ -0,0 +1,180 @@
+package needleware
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+)
+
+// closeSpool persists the pending close events of a needle in a JSON lines file,
+// so that they are delivered after a restart.
+// The events are appended with their sequence number, and the delivered or dropped ones
+// are marked as done by appending the sequence number of the last one,
+// until the spool is rewritten with the pending events only.
+type closeSpool struct {
+	path string
+	file *os.File
+}
+
+type spooledEvent struct {
+	Seq uint64 `json:"seq,omitempty"`
+	// Done marks the events up to the given sequence number as done, the line holding no event.
+	Done    uint64 `json:"done,omitempty"`
+	ConnID  int32  `json:"connId"`
+	ConnUID string `json:"connUid,omitempty"`
+	Reason  int    `json:"reason,omitempty"`
+	Needle  string `json:"needle,omitempty"`
+}
+
+// openCloseSpool opens the spool of the given needle in dir, returning the events it already holds.
+func openCloseSpool(dir string, needleName string) (*closeSpool, []*client.CloseEvent, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, nil, err
+	}
+
+	spool := &closeSpool{path: filepath.Join(dir, url.PathEscape(needleName)+".jsonl")}
+
+	events, err := spool.read()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spool.file, err = os.OpenFile(spool.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return spool, events, nil
+}
+
+func (s *closeSpool) read() ([]*client.CloseEvent, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var spooled []spooledEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event spooledEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			// A partially written line is expected after a crash, the following ones are still readable.
+			continue
+		}
+
+		if event.Done == 0 {
+			spooled = append(spooled, event)
+			continue
+		}
+
+		// The events are done in sequence order.
+		done := 0
+		for done < len(spooled) && spooled[done].Seq <= event.Done {
+			done++
+		}
+		spooled = spooled[done:]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	events := make([]*client.CloseEvent, 0, len(spooled))
+	for _, event := range spooled {
+		events = append(events, &client.CloseEvent{ConnId: event.ConnID, ConnUID: event.ConnUID, Reason: client.CloseReason(event.Reason), Needle: event.Needle})
+	}
+	return events, nil
+}
+
+// append adds an event at the end of the spool.
+func (s *closeSpool) append(event queuedEvent) error {
+	line, err := marshalSpooledEvent(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.file.Write(line)
+	return err
+}
+
+// markDone marks the events up to the given sequence number as done.
+func (s *closeSpool) markDone(seq uint64) error {
+	line, err := json.Marshal(struct {
+		Done uint64 `json:"done"`
+	}{Done: seq})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// rewrite replaces the content of the spool with the given events.
+func (s *closeSpool) rewrite(events []queuedEvent) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	writer := bufio.NewWriter(tmp)
+	for _, event := range events {
+		line, err := marshalSpooledEvent(event)
+		if err != nil {
+			_ = tmp.Close()
+			return err
+		}
+		if _, err := writer.Write(line); err != nil {
+			_ = tmp.Close()
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("reopening spool: %w", err)
+	}
+
+	_ = s.file.Close()
+	s.file = file
+	return nil
+}
+
+func (s *closeSpool) close() error {
+	return s.file.Close()
+}
+
+func marshalSpooledEvent(queued queuedEvent) ([]byte, error) {
+	event := queued.event
+	line, err := json.Marshal(spooledEvent{Seq: queued.seq, ConnID: event.ConnId, ConnUID: event.ConnUID, Reason: int(event.Reason), Needle: event.Needle})
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
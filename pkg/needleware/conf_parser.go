@@ -17,6 +17,15 @@ const (
 	defaultTimeout    = 5 * time.Second
 	defaultOnTimeout  = DecisionRefReject
 	defaultOnError    = DecisionRefReject
+	defaultOnOverload = DecisionRefReject
+
+	defaultMaxQueue = 1024
+
+	defaultRetryBackoff = 10 * time.Millisecond
+
+	defaultCloseQueueSize     = 1024
+	defaultCloseBatchSize     = 100
+	defaultCloseFlushInterval = time.Second
 )
 
 func defaultNotifyOnClose() map[DecisionRef]bool {
@@ -29,6 +38,9 @@ func defaultNotifyOnClose() map[DecisionRef]bool {
 type needleConfParser struct {
 	conf   *runtime.NeedleInfo
 	logger zerolog.Logger
+
+	// grpcConn is the connection of the built gRPC client, if any.
+	grpcConn *grpc.ClientConn
 }
 
 func (n *needleConfParser) buildClient() (client.Client, bool) {
@@ -63,6 +75,7 @@ func (n *needleConfParser) buildGRPCClient() (client.Client, bool) {
 		n.logger.Error().Err(err).Msg("failed to create gRPC connection")
 		return nil, false
 	}
+	n.grpcConn = grpcConn
 
 	return client.NewGRPCClient(pb.NewNeedlewareClient(grpcConn)), true
 }
@@ -95,6 +108,23 @@ func (n *needleConfParser) validGRPCCredentials() (credentials.TransportCredenti
 	return insecure.NewCredentials(), true
 }
 
+func (n *needleConfParser) validMode() (DecisionMode, bool) {
+	decision := n.conf.Decision
+	if decision == nil || decision.Mode == "" {
+		return DecisionModeEnforce, true
+	}
+	switch strings.ToLower(decision.Mode) {
+	case "enforce":
+		return DecisionModeEnforce, true
+	case "shadow":
+		return DecisionModeShadow, true
+	case "optimistic":
+		return DecisionModeOptimistic, true
+	}
+	n.logger.Error().Msgf("unknown decision.mode value: %s", decision.Mode)
+	return 0, false
+}
+
 func (n *needleConfParser) validOnTimeout() (DecisionRef, bool) {
 	decision := n.conf.Decision
 	if decision == nil || decision.OnTimeout == "" {
@@ -125,6 +155,30 @@ func (n *needleConfParser) validOnError() (DecisionRef, bool) {
 	return 0, false
 }
 
+func (n *needleConfParser) validOnOverload() (DecisionRef, bool) {
+	decision := n.conf.Decision
+	if decision == nil || decision.OnOverload == "" {
+		return defaultOnOverload, true
+	}
+	switch strings.ToLower(decision.OnOverload) {
+	case "accept":
+		return DecisionRefAccept, true
+	case "reject":
+		return DecisionRefReject, true
+	}
+	n.logger.Error().Msgf("unknown decision.onOverload value: %s", decision.OnOverload)
+	return 0, false
+}
+
+// fallbackNeedle returns the name of the fallback needle, already qualified by the caller, or an empty string if there is none.
+func (n *needleConfParser) fallbackNeedle() string {
+	decision := n.conf.Decision
+	if decision == nil {
+		return ""
+	}
+	return decision.FallbackNeedle
+}
+
 func (n *needleConfParser) validNotifyOnClose() (map[DecisionRef]bool, bool) {
 	var m = make(map[DecisionRef]bool)
 	if len(n.conf.NotifyConnClose) == 0 {
@@ -159,3 +213,131 @@ func (n *needleConfParser) validConnTimeout() (time.Duration, bool) {
 	}
 	return duration, true
 }
+
+func (n *needleConfParser) validRetry(connTimeout time.Duration) (retryConfig, bool) {
+	config := retryConfig{attempts: 1, backoff: defaultRetryBackoff}
+
+	cc := n.conf.Client
+	if cc == nil || cc.Retry == nil {
+		return config, true
+	}
+
+	if cc.Retry.Attempts < 0 {
+		n.logger.Error().Msgf("invalid client.retry.attempts value: %d", cc.Retry.Attempts)
+		return config, false
+	}
+	if cc.Retry.Attempts > 0 {
+		config.attempts = cc.Retry.Attempts
+	}
+
+	if cc.Retry.PerAttemptTimeout != "" {
+		timeout, err := time.ParseDuration(cc.Retry.PerAttemptTimeout)
+		if err != nil || timeout < 0 {
+			n.logger.Error().Err(err).Msgf("invalid client.retry.perAttemptTimeout value: %s", cc.Retry.PerAttemptTimeout)
+			return config, false
+		}
+		if timeout >= connTimeout {
+			n.logger.Warn().Msgf("client.retry.perAttemptTimeout (%s) is not shorter than client.timeout (%s), there will be no retry after a timeout", timeout, connTimeout)
+		}
+		config.perAttemptTimeout = timeout
+	}
+
+	return config, true
+}
+
+// validConcurrency returns the maximum numbers of concurrent and queued decisions.
+func (n *needleConfParser) validConcurrency() (int, int, bool) {
+	cc := n.conf.Client
+	if cc == nil {
+		return 0, defaultMaxQueue, true
+	}
+
+	if cc.MaxConcurrent < 0 {
+		n.logger.Error().Msgf("invalid client.maxConcurrent value: %d", cc.MaxConcurrent)
+		return 0, 0, false
+	}
+	if cc.MaxQueue < 0 {
+		n.logger.Error().Msgf("invalid client.maxQueue value: %d", cc.MaxQueue)
+		return 0, 0, false
+	}
+	if cc.MaxQueue > 0 && cc.MaxConcurrent == 0 {
+		n.logger.Warn().Msg("client.maxQueue is ignored without client.maxConcurrent")
+	}
+
+	maxQueue := defaultMaxQueue
+	if cc.MaxQueue > 0 {
+		maxQueue = cc.MaxQueue
+	}
+
+	return cc.MaxConcurrent, maxQueue, true
+}
+
+func (n *needleConfParser) validHedgingDelay() (time.Duration, bool) {
+	cc := n.conf.Client
+	if cc == nil || cc.Hedging == nil || cc.Hedging.Delay == "" {
+		return 0, true
+	}
+
+	delay, err := time.ParseDuration(cc.Hedging.Delay)
+	if err != nil || delay < 0 {
+		n.logger.Error().Err(err).Msgf("invalid client.hedging.delay value: %s", cc.Hedging.Delay)
+		return 0, false
+	}
+	return delay, true
+}
+
+func (n *needleConfParser) validCloseQueueConfig(deliveryTimeout time.Duration) (closeQueueConfig, bool) {
+	config := closeQueueConfig{
+		queueSize:       defaultCloseQueueSize,
+		batchSize:       defaultCloseBatchSize,
+		flushInterval:   defaultCloseFlushInterval,
+		onOverflow:      overflowDropOldest,
+		deliveryTimeout: deliveryTimeout,
+	}
+
+	ce := n.conf.CloseEvents
+	if ce == nil {
+		return config, true
+	}
+
+	if ce.QueueSize < 0 {
+		n.logger.Error().Msgf("invalid closeEvents.queueSize value: %d", ce.QueueSize)
+		return config, false
+	}
+	if ce.QueueSize > 0 {
+		config.queueSize = ce.QueueSize
+	}
+
+	if ce.BatchSize < 0 {
+		n.logger.Error().Msgf("invalid closeEvents.batchSize value: %d", ce.BatchSize)
+		return config, false
+	}
+	if ce.BatchSize > 0 {
+		config.batchSize = ce.BatchSize
+	}
+
+	if ce.FlushInterval != "" {
+		flushInterval, err := time.ParseDuration(ce.FlushInterval)
+		if err != nil || flushInterval < 0 {
+			n.logger.Error().Err(err).Msgf("invalid closeEvents.flushInterval value: %s", ce.FlushInterval)
+			return config, false
+		}
+		config.flushInterval = flushInterval
+	}
+
+	switch strings.ToLower(ce.OnOverflow) {
+	case "", "dropoldest":
+		config.onOverflow = overflowDropOldest
+	case "dropnewest":
+		config.onOverflow = overflowDropNewest
+	case "block":
+		config.onOverflow = overflowBlock
+	default:
+		n.logger.Error().Msgf("unknown closeEvents.onOverflow value: %s", ce.OnOverflow)
+		return config, false
+	}
+
+	config.spoolDir = ce.SpoolDir
+
+	return config, true
+}
@@ -0,0 +1,168 @@
+package needleware
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"google.golang.org/grpc/connectivity"
+)
+
+type liveConn struct {
+	decision  *DecisionWrapper
+	startedAt time.Time
+}
+
+// connRegistry holds the open connections accepted by a needle, to resynchronize its decision service.
+// Like the close queues, it outlives the needles built for a configuration.
+type connRegistry struct {
+	instance string
+	logger   zerolog.Logger
+	now      func() time.Time
+
+	mu      sync.Mutex
+	client  client.Client
+	timeout time.Duration
+	conns   map[string]*liveConn
+	// syncing tells whether a snapshot is being sent, syncPending that another one has been requested meanwhile.
+	syncing     bool
+	syncPending bool
+}
+
+func newConnRegistry(instance string, logger zerolog.Logger, c client.Client, timeout time.Duration) *connRegistry {
+	return &connRegistry{
+		instance: instance,
+		logger:   logger,
+		now:      time.Now,
+		client:   c,
+		timeout:  timeout,
+		conns:    map[string]*liveConn{},
+	}
+}
+
+// update applies the configuration of a newly built needle, keeping the open connections.
+func (r *connRegistry) update(c client.Client, timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.client = c
+	r.timeout = timeout
+}
+
+func (r *connRegistry) add(decision *DecisionWrapper) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.conns[decision.Criteria.ConnUID] = &liveConn{decision: decision, startedAt: r.now()}
+}
+
+func (r *connRegistry) remove(decision *DecisionWrapper) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.conns, decision.Criteria.ConnUID)
+}
+
+// snapshot returns the open connections, oldest first.
+func (r *connRegistry) snapshot() []*client.OpenConnection {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conns := make([]*client.OpenConnection, 0, len(r.conns))
+	for _, conn := range r.conns {
+		conns = append(conns, &client.OpenConnection{
+			Criteria:        conn.decision.Criteria,
+			StartedAt:       conn.startedAt,
+			BytesUploaded:   conn.decision.Traffic.Uploaded(),
+			BytesDownloaded: conn.decision.Traffic.Downloaded(),
+		})
+	}
+
+	sort.Slice(conns, func(i, j int) bool {
+		return conns[i].StartedAt.Before(conns[j].StartedAt)
+	})
+	return conns
+}
+
+// requestSync sends a snapshot of the open connections to the decision service, in the background.
+// Requests made while a snapshot is being sent are coalesced into a single new one.
+func (r *connRegistry) requestSync() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.syncing {
+		r.syncPending = true
+		return
+	}
+	r.syncing = true
+
+	go r.runSync()
+}
+
+func (r *connRegistry) runSync() {
+	for {
+		r.sync()
+
+		r.mu.Lock()
+		if !r.syncPending {
+			r.syncing = false
+			r.mu.Unlock()
+			return
+		}
+		r.syncPending = false
+		r.mu.Unlock()
+	}
+}
+
+func (r *connRegistry) sync() {
+	conns := r.snapshot()
+
+	r.mu.Lock()
+	c, timeout := r.client, r.timeout
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := c.SyncOpenConnections(r.instance, conns, ctx); err != nil {
+		r.logger.Warn().Err(err).Msgf("Cannot send the %d open connections to the decision service", len(conns))
+		return
+	}
+
+	r.logger.Debug().Msgf("Sent the %d open connections to the decision service", len(conns))
+}
+
+// connectivityWatcher is implemented by grpc.ClientConn.
+type connectivityWatcher interface {
+	GetState() connectivity.State
+	WaitForStateChange(ctx context.Context, sourceState connectivity.State) bool
+}
+
+// watchReconnects calls onReconnect each time the connection is ready again after having been lost,
+// until the context is done.
+func watchReconnects(ctx context.Context, conn connectivityWatcher, onReconnect func()) {
+	state := conn.GetState()
+	wasReady := state == connectivity.Ready
+	lost := false
+
+	for conn.WaitForStateChange(ctx, state) {
+		state = conn.GetState()
+
+		switch state {
+		case connectivity.Ready:
+			if wasReady && lost {
+				onReconnect()
+			}
+			wasReady = true
+			lost = false
+		case connectivity.TransientFailure:
+			// Going idle only means that the connection is not used, the decision service keeps its state.
+			lost = wasReady
+		case connectivity.Shutdown:
+			return
+		}
+	}
+}
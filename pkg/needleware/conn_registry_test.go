@@ -0,0 +1,147 @@
+package needleware
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"github.com/traefik/traefik/v3/pkg/needleware/client/clienttest"
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestBasicNeedle_SyncOpenConnections(t *testing.T) {
+	syncRequested := false
+	c := &clienttest.Client{Decide: func(context.Context, *client.DecisionCriteria) *client.DecisionResponse {
+		response := clienttest.Accept()
+		response.Decision.SyncRequested = syncRequested
+		return response
+	}}
+	n := newTestNeedle(t, DecisionModeEnforce, &labelsCounter{}, c)
+
+	var decisions []*DecisionWrapper
+	for i := 0; i < 3; i++ {
+		criteria, err := n.NewUDPCriteria("127.0.0.1:40000", "127.0.0.1:53")
+		require.NoError(t, err)
+
+		decision, err := n.Decide(criteria)
+		require.NoError(t, err)
+		decisions = append(decisions, decision)
+	}
+
+	decisions[0].Traffic.AddUploaded(10)
+	decisions[0].Traffic.AddDownloaded(20)
+	n.OnConnClose(decisions[1])
+
+	// The decision service asks for the open connections along with a decision.
+	syncRequested = true
+	criteria, err := n.NewUDPCriteria("127.0.0.1:40000", "127.0.0.1:53")
+	require.NoError(t, err)
+	_, err = n.Decide(criteria)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(c.Syncs()) == 1 }, time.Second, 10*time.Millisecond)
+
+	var connIDs []string
+	for _, conn := range c.Syncs()[0] {
+		connIDs = append(connIDs, conn.Criteria.ConnUID)
+	}
+	assert.ElementsMatch(t, []string{decisions[0].Criteria.ConnUID, decisions[2].Criteria.ConnUID, criteria.ConnUID}, connIDs)
+
+	for _, conn := range c.Syncs()[0] {
+		if conn.Criteria.ConnUID == decisions[0].Criteria.ConnUID {
+			assert.Equal(t, uint64(10), conn.BytesUploaded)
+			assert.Equal(t, uint64(20), conn.BytesDownloaded)
+		}
+	}
+}
+
+func TestConnRegistry_Snapshot(t *testing.T) {
+	registry := newConnRegistry("test", zerolog.Nop(), &clienttest.Client{}, time.Second)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, uid := range []string{"b", "a", "c"} {
+		registry.now = func() time.Time { return start.Add(time.Duration(i) * time.Second) }
+		registry.add(&DecisionWrapper{Criteria: &client.DecisionCriteria{ConnUID: uid}})
+	}
+
+	var connIDs []string
+	for _, conn := range registry.snapshot() {
+		connIDs = append(connIDs, conn.Criteria.ConnUID)
+	}
+	assert.Equal(t, []string{"b", "a", "c"}, connIDs)
+}
+
+func TestWatchReconnects(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		states   []connectivity.State
+		expected int
+	}{
+		{
+			desc:     "first connection",
+			states:   []connectivity.State{connectivity.Connecting, connectivity.Ready},
+			expected: 0,
+		},
+		{
+			desc:     "reconnection",
+			states:   []connectivity.State{connectivity.Ready, connectivity.TransientFailure, connectivity.Connecting, connectivity.Ready},
+			expected: 1,
+		},
+		{
+			desc:     "going idle is not a loss",
+			states:   []connectivity.State{connectivity.Ready, connectivity.Idle, connectivity.Connecting, connectivity.Ready},
+			expected: 0,
+		},
+		{
+			desc:     "reconnection after going idle",
+			states:   []connectivity.State{connectivity.Ready, connectivity.Idle, connectivity.Connecting, connectivity.TransientFailure, connectivity.Ready},
+			expected: 1,
+		},
+		{
+			desc:     "never connected",
+			states:   []connectivity.State{connectivity.Connecting, connectivity.TransientFailure, connectivity.Ready},
+			expected: 0,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			reconnects := 0
+			watchReconnects(context.Background(), &fakeConnectivity{states: test.states}, func() { reconnects++ })
+
+			assert.Equal(t, test.expected, reconnects)
+		})
+	}
+}
+
+// fakeConnectivity goes through the given states, then reports that the watch is over.
+type fakeConnectivity struct {
+	mu     sync.Mutex
+	states []connectivity.State
+}
+
+func (f *fakeConnectivity) GetState() connectivity.State {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.states[0]
+}
+
+func (f *fakeConnectivity) WaitForStateChange(_ context.Context, _ connectivity.State) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.states) == 1 {
+		return false
+	}
+	f.states = f.states[1:]
+	return true
+}
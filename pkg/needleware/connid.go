@@ -0,0 +1,92 @@
+package needleware
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"strings"
+	"time"
+)
+
+// crockford is the base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// maxInstanceLen bounds the instance prefix of the connection IDs.
+const maxInstanceLen = 32
+
+// connIDGenerator generates connection IDs made of an instance prefix and a ULID, separated by the last dash,
+// so that they are unique across the Traefik instances sharing a decision service.
+// It is safe for concurrent use.
+type connIDGenerator struct {
+	instance string
+	now      func() time.Time
+}
+
+func newConnIDGenerator(instance string) *connIDGenerator {
+	return &connIDGenerator{instance: instance, now: time.Now}
+}
+
+// next returns a new connection ID, along with the legacy 31 bits ID derived from its random part.
+func (g *connIDGenerator) next() (string, int32) {
+	var id [16]byte
+
+	ms := uint64(g.now().UnixMilli())
+	id[0] = byte(ms >> 40)
+	id[1] = byte(ms >> 32)
+	id[2] = byte(ms >> 24)
+	id[3] = byte(ms >> 16)
+	id[4] = byte(ms >> 8)
+	id[5] = byte(ms)
+
+	// crypto/rand is safe for concurrent use, unlike a shared math/rand source.
+	_, _ = rand.Read(id[6:])
+
+	// Same range as before: from MaxInt32 / 2 to MaxInt32.
+	legacy := int32(1<<30 | binary.BigEndian.Uint32(id[6:10])&(1<<30-1))
+
+	return g.instance + "-" + encodeULID(id), legacy
+}
+
+// encodeULID encodes the 128 bits of a ULID into its 26 characters representation.
+func encodeULID(id [16]byte) string {
+	var dst [26]byte
+
+	// The 130 bits of the representation are the 128 bits of the ID, left-padded with two zero bits.
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	for i := len(dst) - 1; i >= 0; i-- {
+		dst[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(dst[:])
+}
+
+// instanceID returns the prefix identifying this Traefik instance in the connection IDs:
+// the host name when available, a random identifier otherwise.
+func instanceID() string {
+	hostname, _ := os.Hostname()
+
+	instance := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return -1
+		}
+	}, hostname)
+
+	if len(instance) > maxInstanceLen {
+		instance = instance[:maxInstanceLen]
+	}
+
+	if instance == "" {
+		var b [4]byte
+		_, _ = rand.Read(b[:])
+		instance = hex.EncodeToString(b[:])
+	}
+
+	return instance
+}
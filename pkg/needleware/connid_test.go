@@ -0,0 +1,66 @@
+package needleware
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnIDGenerator_next(t *testing.T) {
+	g := newConnIDGenerator("traefik-0")
+	g.now = func() time.Time { return time.UnixMilli(1469918176385) }
+
+	uid, legacy := g.next()
+
+	ulid, ok := strings.CutPrefix(uid, "traefik-0-")
+	require.True(t, ok)
+	assert.Len(t, ulid, 26)
+	// The timestamp of the ULID specification example.
+	assert.Equal(t, "01ARYZ6S41", ulid[:10])
+	assert.GreaterOrEqual(t, legacy, int32(1<<30))
+}
+
+func TestConnIDGenerator_next_concurrent(t *testing.T) {
+	g := newConnIDGenerator("traefik")
+
+	const goroutines, perGoroutine = 8, 1000
+
+	var mu sync.Mutex
+	seen := make(map[string]struct{}, goroutines*perGoroutine)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ids := make([]string, perGoroutine)
+			for j := range ids {
+				ids[j], _ = g.next()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range ids {
+				seen[id] = struct{}{}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, seen, goroutines*perGoroutine)
+}
+
+func TestEncodeULID(t *testing.T) {
+	var id [16]byte
+	assert.Equal(t, "00000000000000000000000000", encodeULID(id))
+
+	for i := range id {
+		id[i] = 0xff
+	}
+	assert.Equal(t, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", encodeULID(id))
+}
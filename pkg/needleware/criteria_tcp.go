@@ -0,0 +1,117 @@
+package needleware
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// netConnUnwrapper is implemented by connections wrapping another one, like tls.Conn does.
+type netConnUnwrapper interface {
+	NetConn() net.Conn
+}
+
+// tlsInfo returns the TLS state of conn, if it is a TLS connection which completed its handshake.
+func tlsInfo(conn tcp.WriteCloser) *client.TLSInfo {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil
+	}
+
+	state := tlsConn.ConnectionState()
+	if !state.HandshakeComplete {
+		return nil
+	}
+
+	info := &client.TLSInfo{
+		ServerName:         state.ServerName,
+		NegotiatedProtocol: state.NegotiatedProtocol,
+		Version:            state.Version,
+	}
+
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		fingerprint := sha256.Sum256(cert.Raw)
+
+		info.ClientCertificate = &client.ClientCertificate{
+			Subject:           cert.Subject.String(),
+			Issuer:            cert.Issuer.String(),
+			SerialNumber:      cert.SerialNumber.String(),
+			DNSNames:          cert.DNSNames,
+			EmailAddresses:    cert.EmailAddresses,
+			FingerprintSHA256: hex.EncodeToString(fingerprint[:]),
+		}
+		for _, ip := range cert.IPAddresses {
+			info.ClientCertificate.IPAddresses = append(info.ClientCertificate.IPAddresses, ip.String())
+		}
+		for _, uri := range cert.URIs {
+			info.ClientCertificate.URIs = append(info.ClientCertificate.URIs, uri.String())
+		}
+	}
+
+	return info
+}
+
+// proxyProtocolInfo returns the PROXY protocol header received on conn, if any.
+// It walks down the connection wrappers until it finds the PROXY protocol connection.
+func proxyProtocolInfo(conn net.Conn) (*client.ProxyProtocolInfo, error) {
+	for conn != nil {
+		switch c := conn.(type) {
+		case *proxyproto.Conn:
+			return newProxyProtocolInfo(c)
+		case netConnUnwrapper:
+			conn = c.NetConn()
+		default:
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
+
+func newProxyProtocolInfo(conn *proxyproto.Conn) (*client.ProxyProtocolInfo, error) {
+	header := conn.ProxyHeader()
+	if header == nil || header.SourceAddr == nil || header.DestinationAddr == nil {
+		return nil, nil
+	}
+
+	sourceHost, sourcePort, err := parseHostPort(header.SourceAddr.String())
+	if err != nil {
+		return nil, err
+	}
+	destinationHost, destinationPort, err := parseHostPort(header.DestinationAddr.String())
+	if err != nil {
+		return nil, err
+	}
+	peerHost, peerPort, err := parseHostPort(conn.Raw().RemoteAddr().String())
+	if err != nil {
+		return nil, err
+	}
+
+	info := &client.ProxyProtocolInfo{
+		Version:         header.Version,
+		SourceHost:      sourceHost,
+		SourcePort:      sourcePort,
+		DestinationHost: destinationHost,
+		DestinationPort: destinationPort,
+		PeerHost:        peerHost,
+		PeerPort:        peerPort,
+	}
+
+	tlvs, err := header.TLVs()
+	if err != nil {
+		return nil, err
+	}
+	if len(tlvs) > 0 {
+		info.TLVs = make(map[uint32][]byte, len(tlvs))
+		for _, tlv := range tlvs {
+			info.TLVs[uint32(tlv.Type)] = tlv.Value
+		}
+	}
+
+	return info, nil
+}
@@ -0,0 +1,132 @@
+package needleware
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
+)
+
+type wrapperConn struct {
+	net.Conn
+}
+
+func (c wrapperConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+func TestProxyProtocolInfo(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		header   *proxyproto.Header
+		expected bool
+	}{
+		{
+			desc: "PROXY protocol v1",
+			header: &proxyproto.Header{
+				Version:           1,
+				Command:           proxyproto.PROXY,
+				TransportProtocol: proxyproto.TCPv4,
+				SourceAddr:        &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234},
+				DestinationAddr:   &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 5432},
+			},
+			expected: true,
+		},
+		{
+			desc:     "no PROXY protocol header",
+			expected: false,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			defer listener.Close()
+
+			go func() {
+				clientConn, err := net.Dial("tcp", listener.Addr().String())
+				if err != nil {
+					return
+				}
+				defer clientConn.Close()
+
+				if test.header != nil {
+					_, _ = test.header.WriteTo(clientConn)
+				}
+				_, _ = clientConn.Write([]byte("ping"))
+			}()
+
+			serverConn, err := listener.Accept()
+			require.NoError(t, err)
+
+			conn := proxyproto.NewConn(serverConn)
+			defer conn.Close()
+
+			info, err := proxyProtocolInfo(wrapperConn{Conn: conn})
+			require.NoError(t, err)
+
+			if !test.expected {
+				assert.Nil(t, info)
+				return
+			}
+
+			require.NotNil(t, info)
+			assert.Equal(t, byte(1), info.Version)
+			assert.Equal(t, "10.0.0.1", info.SourceHost)
+			assert.Equal(t, int32(1234), info.SourcePort)
+			assert.Equal(t, "10.0.0.2", info.DestinationHost)
+			assert.Equal(t, int32(5432), info.DestinationPort)
+			assert.Equal(t, "127.0.0.1", info.PeerHost)
+		})
+	}
+}
+
+func TestTLSInfo(t *testing.T) {
+	certPEM, keyPEM, err := generate.KeyPair("client.example.com", time.Time{})
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	clientConn, serverConn := net.Pipe()
+
+	server := tls.Server(serverConn, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAnyClientCert,
+		NextProtos:   []string{"postgresql"},
+	})
+	defer server.Close()
+
+	go func() {
+		client := tls.Client(clientConn, &tls.Config{
+			ServerName:         "db.example.com",
+			Certificates:       []tls.Certificate{cert},
+			NextProtos:         []string{"postgresql"},
+			InsecureSkipVerify: true,
+		})
+		if err := client.Handshake(); err == nil {
+			_, _ = io.Copy(io.Discard, client)
+		}
+	}()
+
+	assert.Nil(t, tlsInfo(server))
+
+	require.NoError(t, server.Handshake())
+
+	info := tlsInfo(server)
+	require.NotNil(t, info)
+	assert.Equal(t, "db.example.com", info.ServerName)
+	assert.Equal(t, "postgresql", info.NegotiatedProtocol)
+	require.NotNil(t, info.ClientCertificate)
+	assert.Equal(t, []string{"client.example.com"}, info.ClientCertificate.DNSNames)
+	assert.Len(t, info.ClientCertificate.FingerprintSHA256, 64)
+}
@@ -0,0 +1,133 @@
+package needleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+)
+
+var errOverloaded = errors.New("too many pending decisions")
+
+// decisionLimiter bounds the number of decisions asked for at the same time,
+// the ones over the limit waiting in a bounded queue.
+// It is kept across configurations, so that a reload does not reset the pending decisions count.
+type decisionLimiter struct {
+	mu            sync.Mutex
+	maxConcurrent int
+	maxQueue      int
+	active        int
+	// waiters are the queued decisions, in order, each one being granted a slot by closing its channel.
+	waiters []chan struct{}
+
+	queueGauge gokitmetrics.Gauge
+}
+
+// newDecisionLimiter returns a limiter allowing maxConcurrent decisions, or nil if maxConcurrent is zero.
+// At most maxQueue decisions wait for a slot, the other ones being rejected right away.
+func newDecisionLimiter(maxConcurrent, maxQueue int, queueGauge gokitmetrics.Gauge) *decisionLimiter {
+	if maxConcurrent <= 0 {
+		return nil
+	}
+
+	return &decisionLimiter{
+		maxConcurrent: maxConcurrent,
+		maxQueue:      maxQueue,
+		queueGauge:    queueGauge,
+	}
+}
+
+// update changes the limits, the decisions already acquired or queued being kept.
+// A zero maxConcurrent lifts the limit, granting a slot to all the queued decisions.
+func (l *decisionLimiter) update(maxConcurrent, maxQueue int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.maxConcurrent = maxConcurrent
+	l.maxQueue = maxQueue
+	l.grant()
+}
+
+// acquire waits for a slot until the context is done.
+// It returns errOverloaded if the queue is full, and the error of the context if it is done first.
+func (l *decisionLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+
+	if len(l.waiters) == 0 && l.hasSlot() {
+		l.active++
+		l.mu.Unlock()
+		return nil
+	}
+
+	if len(l.waiters) >= l.maxQueue {
+		l.mu.Unlock()
+		return errOverloaded
+	}
+
+	ready := make(chan struct{})
+	l.waiters = append(l.waiters, ready)
+	l.queueGauge.Add(1)
+
+	l.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.removeWaiter(ready) {
+		// The slot was granted meanwhile, it goes to the next decision.
+		l.active--
+		l.grant()
+	}
+	return ctx.Err()
+}
+
+func (l *decisionLimiter) release() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.active--
+	l.grant()
+}
+
+func (l *decisionLimiter) hasSlot() bool {
+	return l.maxConcurrent <= 0 || l.active < l.maxConcurrent
+}
+
+// grant gives the free slots to the queued decisions, in order.
+func (l *decisionLimiter) grant() {
+	for len(l.waiters) > 0 && l.hasSlot() {
+		ready := l.waiters[0]
+		l.waiters = l.waiters[1:]
+		l.queueGauge.Add(-1)
+
+		l.active++
+		close(ready)
+	}
+}
+
+// removeWaiter removes the given queued decision, and reports whether it was still queued.
+func (l *decisionLimiter) removeWaiter(ready chan struct{}) bool {
+	for i, waiter := range l.waiters {
+		if waiter == ready {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			l.queueGauge.Add(-1)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package needleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"github.com/traefik/traefik/v3/pkg/needleware/client/clienttest"
+)
+
+func TestDecisionLimiter(t *testing.T) {
+	gauge := generic.NewGauge("queued")
+	limiter := newDecisionLimiter(1, 1, gauge)
+
+	require.NoError(t, limiter.acquire(context.Background()))
+
+	// The second decision waits in the queue, the third one does not fit.
+	acquired := make(chan error, 1)
+	go func() { acquired <- limiter.acquire(context.Background()) }()
+	require.Eventually(t, func() bool { return gauge.Value() == 1 }, time.Second, 10*time.Millisecond)
+
+	assert.ErrorIs(t, limiter.acquire(context.Background()), errOverloaded)
+
+	limiter.release()
+	require.NoError(t, <-acquired)
+	assert.Equal(t, float64(0), gauge.Value())
+
+	// The queued decision gives up once its context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.acquire(ctx), context.DeadlineExceeded)
+	assert.Equal(t, float64(0), gauge.Value())
+}
+
+func TestDecisionLimiter_Update(t *testing.T) {
+	gauge := generic.NewGauge("queued")
+	limiter := newDecisionLimiter(1, 2, gauge)
+
+	require.NoError(t, limiter.acquire(context.Background()))
+
+	acquired := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { acquired <- limiter.acquire(context.Background()) }()
+	}
+	require.Eventually(t, func() bool { return gauge.Value() == 2 }, time.Second, 10*time.Millisecond)
+
+	// Raising the limit grants a slot to the queued decisions.
+	limiter.update(2, 2)
+	require.NoError(t, <-acquired)
+	assert.Equal(t, float64(1), gauge.Value())
+
+	// Lifting the limit grants a slot to all of them.
+	limiter.update(0, 2)
+	require.NoError(t, <-acquired)
+	assert.Equal(t, float64(0), gauge.Value())
+	assert.Equal(t, 3, limiter.active)
+}
+
+func TestBasicNeedle_Decide_Overload(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		onOverload DecisionRef
+		expected   client.DecisionCode
+	}{
+		{
+			desc:       "reject",
+			onOverload: DecisionRefReject,
+			expected:   client.DecisionConnRejected,
+		},
+		{
+			desc:       "accept",
+			onOverload: DecisionRefAccept,
+			expected:   client.DecisionConnAccepted,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			n := newTestNeedle(t, DecisionModeEnforce, &labelsCounter{}, &clienttest.Client{})
+			n.limiter = newDecisionLimiter(1, 0, generic.NewGauge("queued"))
+			n.connTimeout = 10 * time.Millisecond
+			n.onOverload = test.onOverload
+
+			// The only slot is taken by a pending decision.
+			require.NoError(t, n.limiter.acquire(context.Background()))
+			defer n.limiter.release()
+
+			criteria, err := n.NewUDPCriteria("127.0.0.1:40000", "127.0.0.1:53")
+			require.NoError(t, err)
+
+			decision, err := n.Decide(criteria)
+			require.NoError(t, err)
+
+			assert.Equal(t, client.StatusDecisionOverloaded, decision.Status)
+			assert.Equal(t, test.expected, decision.DecisionCode)
+		})
+	}
+}
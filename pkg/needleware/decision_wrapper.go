@@ -1,11 +1,102 @@
 package needleware
 
-import "github.com/traefik/traefik/v3/pkg/needleware/client"
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"github.com/traefik/traefik/v3/pkg/shaping"
+)
 
 type DecisionWrapper struct {
 	Status       client.DecisionStatus
 	DecisionCode client.DecisionCode
 	Criteria     *client.DecisionCriteria
+	// Target is the routing target requested by an accepting decision, if any.
+	Target *client.RoutingTarget
+	// Limits are the limits requested by an accepting decision, if any.
+	Limits *client.ConnLimits
+	// CloseReason tells why the connection has been closed, once it is.
+	CloseReason client.CloseReason
+	// Mode is the mode of the needle which made the decision.
+	// Unless it is enforced, the decision is provisional and accepts the connection.
+	Mode DecisionMode
+	// Needle is the name of the needle which made the decision, which is a fallback needle
+	// if the decision service of the needle asked for it was unavailable.
+	Needle string
+	// Traffic counts the bytes of the accepted connection, as reported in the snapshots of the open connections.
+	Traffic Traffic
+
+	// late is set for optimistic decisions, until the actual decision is made.
+	late *lateDecision
+	// decided is closed once the actual decision of a provisional one is made.
+	decided chan struct{}
+}
+
+// Traffic counts the bytes exchanged with the client of a connection.
+// Upload is the direction from the client to the server, download the opposite one.
+type Traffic struct {
+	uploaded   atomic.Uint64
+	downloaded atomic.Uint64
+}
+
+// AddUploaded counts bytes read from the client.
+func (t *Traffic) AddUploaded(n int) {
+	if n > 0 {
+		t.uploaded.Add(uint64(n))
+	}
+}
+
+// AddDownloaded counts bytes written to the client.
+func (t *Traffic) AddDownloaded(n int) {
+	if n > 0 {
+		t.downloaded.Add(uint64(n))
+	}
+}
+
+// Uploaded returns the bytes read from the client so far.
+func (t *Traffic) Uploaded() uint64 {
+	return t.uploaded.Load()
+}
+
+// Downloaded returns the bytes written to the client so far.
+func (t *Traffic) Downloaded() uint64 {
+	return t.downloaded.Load()
+}
+
+// lateDecision holds the outcome of an optimistic decision, which is made while the connection is served.
+type lateDecision struct {
+	mu       sync.Mutex
+	rejected bool
+	onReject func()
+}
+
+func (l *lateDecision) reject() {
+	l.mu.Lock()
+	l.rejected = true
+	onReject := l.onReject
+	l.mu.Unlock()
+
+	if onReject != nil {
+		onReject()
+	}
+}
+
+// setDecidedBy records the needle which made the actual decision of a provisional one,
+// which is a fallback needle if the decision service of the needle was unavailable.
+// It is read once decided is closed.
+func (dw *DecisionWrapper) setDecidedBy(decision *DecisionWrapper, err error) {
+	if err == nil && decision.Needle != "" {
+		dw.Needle = decision.Needle
+	}
+}
+
+// waitDecided waits until the actual decision is made, if the decision is a provisional one.
+func (dw *DecisionWrapper) waitDecided() {
+	if dw.decided != nil {
+		<-dw.decided
+	}
 }
 
 func (dw *DecisionWrapper) ConnAccepted() bool {
@@ -15,3 +106,68 @@ func (dw *DecisionWrapper) ConnAccepted() bool {
 func (dw *DecisionWrapper) ConnRejected() bool {
 	return dw.DecisionCode == client.DecisionConnRejected
 }
+
+// OnLateReject registers the function closing the connection if an optimistic decision turns out to be a reject.
+// It is called right away if the reject has already been received, and never for other decisions.
+func (dw *DecisionWrapper) OnLateReject(closeConn func()) {
+	if dw.late == nil {
+		return
+	}
+
+	dw.late.mu.Lock()
+	rejected := dw.late.rejected
+	dw.late.onReject = closeConn
+	dw.late.mu.Unlock()
+
+	if rejected {
+		closeConn()
+	}
+}
+
+// LateRejected tells whether an optimistic decision turned out to be a reject.
+func (dw *DecisionWrapper) LateRejected() bool {
+	if dw.late == nil {
+		return false
+	}
+
+	dw.late.mu.Lock()
+	defer dw.late.mu.Unlock()
+
+	return dw.late.rejected
+}
+
+// NewShapingLimits returns the limits the proxy should enforce on the connection,
+// or nil if the decision does not restrict it.
+func (dw *DecisionWrapper) NewShapingLimits() *shaping.Limits {
+	if dw.Limits == nil {
+		return nil
+	}
+
+	limits := &shaping.Limits{
+		UploadBytesPerSecond:   toInt64(dw.Limits.UploadBytesPerSecond),
+		DownloadBytesPerSecond: toInt64(dw.Limits.DownloadBytesPerSecond),
+		MaxTotalBytes:          toInt64(dw.Limits.MaxTotalBytes),
+		MaxDuration:            dw.Limits.MaxDuration,
+	}
+	if limits.IsZero() {
+		return nil
+	}
+	return limits
+}
+
+// SetShapingCloseReason records why the proxy closed the connection, if it did.
+func (dw *DecisionWrapper) SetShapingCloseReason(reason shaping.CloseReason) {
+	switch reason {
+	case shaping.CloseReasonMaxTotalBytes:
+		dw.CloseReason = client.CloseReasonMaxTotalBytesExceeded
+	case shaping.CloseReasonMaxDuration:
+		dw.CloseReason = client.CloseReasonMaxDurationExceeded
+	}
+}
+
+func toInt64(value uint64) int64 {
+	if value > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(value)
+}
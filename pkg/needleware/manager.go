@@ -2,27 +2,65 @@ package needleware
 
 import (
 	"context"
+	"fmt"
+	"sync"
+
+	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/logs"
-	"math/rand"
-	"time"
+	"github.com/traefik/traefik/v3/pkg/metrics"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
 )
 
 type Manager struct {
-	logger  zerolog.Logger
-	needles map[string]Needle
+	logger          zerolog.Logger
+	needles         map[string]Needle
+	metricsRegistry metrics.Registry
+	connIDs         *connIDGenerator
+
+	// shutdownCtx is canceled once the instance is shutting down, ending the pending decisions.
+	shutdownCtx     context.Context
+	cancelDecisions context.CancelFunc
+
+	closeQueuesMu sync.Mutex
+	// closeQueues are kept across configurations, by needle name.
+	closeQueues map[string]*closeQueue
+	// connRegistries are kept across configurations, by needle name.
+	connRegistries map[string]*connRegistry
+	// limiters are kept across configurations, by needle name.
+	limiters map[string]*decisionLimiter
+	// cancelWatches stops watching the connections of the clients of the previous configuration.
+	cancelWatches context.CancelFunc
 }
 
-func NewManager() *Manager {
+func NewManager(metricsRegistry metrics.Registry) *Manager {
+	shutdownCtx, cancelDecisions := context.WithCancel(context.Background())
+
 	return &Manager{
-		needles: map[string]Needle{},
+		needles:         map[string]Needle{},
+		metricsRegistry: metricsRegistry,
+		closeQueues:     map[string]*closeQueue{},
+		connRegistries:  map[string]*connRegistry{},
+		limiters:        map[string]*decisionLimiter{},
+		connIDs:         newConnIDGenerator(instanceID()),
+		shutdownCtx:     shutdownCtx,
+		cancelDecisions: cancelDecisions,
 	}
 }
 
+// BuildNeedles builds the needles of the configuration, replacing the previous ones.
 func (m *Manager) BuildNeedles(rootCtx context.Context, conf *runtime.Configuration) {
-	randSource := rand.NewSource(time.Now().UnixNano())
+	m.needles = map[string]Needle{}
+
+	if m.cancelWatches != nil {
+		m.cancelWatches()
+	}
+	var watchCtx context.Context
+	watchCtx, m.cancelWatches = context.WithCancel(context.Background())
+
+	fallbacks := map[string]string{}
 
 	for k, v := range conf.Needles {
 		logger := log.Ctx(rootCtx).With().Str(logs.NeedleName, k).Logger()
@@ -36,6 +74,10 @@ func (m *Manager) BuildNeedles(rootCtx context.Context, conf *runtime.Configurat
 		if !ok {
 			continue
 		}
+		mode, ok := parser.validMode()
+		if !ok {
+			continue
+		}
 		onTimeout, ok := parser.validOnTimeout()
 		if !ok {
 			continue
@@ -44,6 +86,10 @@ func (m *Manager) BuildNeedles(rootCtx context.Context, conf *runtime.Configurat
 		if !ok {
 			continue
 		}
+		onOverload, ok := parser.validOnOverload()
+		if !ok {
+			continue
+		}
 		notifyOnClose, ok := parser.validNotifyOnClose()
 		if !ok {
 			continue
@@ -52,29 +98,199 @@ func (m *Manager) BuildNeedles(rootCtx context.Context, conf *runtime.Configurat
 		if !ok {
 			continue
 		}
+		retry, ok := parser.validRetry(connTimeout)
+		if !ok {
+			continue
+		}
+		hedgingDelay, ok := parser.validHedgingDelay()
+		if !ok {
+			continue
+		}
+		maxConcurrent, maxQueue, ok := parser.validConcurrency()
+		if !ok {
+			continue
+		}
+		closeQueueConfig, ok := parser.validCloseQueueConfig(connTimeout)
+		if !ok {
+			continue
+		}
+
+		queue := m.closeQueue(k, logger, closeQueueConfig, client)
+
+		registry, exists := m.connRegistries[k]
+		if exists {
+			registry.update(client, connTimeout)
+		} else {
+			registry = newConnRegistry(m.connIDs.instance, logger, client, connTimeout)
+			m.connRegistries[k] = registry
+		}
+
+		// The decision service may have lost its state while unreachable.
+		if parser.grpcConn != nil {
+			go watchReconnects(watchCtx, parser.grpcConn, registry.requestSync)
+		}
+
+		if fallback := parser.fallbackNeedle(); fallback != "" {
+			fallbacks[k] = fallback
+		}
 
 		m.needles[k] = &BasicNeedle{
-			client:        client,
-			logger:        logger,
-			connTimeout:   connTimeout,
-			randSource:    randSource,
-			onTimeout:     onTimeout,
-			onError:       onError,
-			notifyOnClose: notifyOnClose,
+			name:             k,
+			client:           client,
+			logger:           logger,
+			connTimeout:      connTimeout,
+			retry:            retry,
+			hedgingDelay:     hedgingDelay,
+			limiter:          m.decisionLimiter(k, maxConcurrent, maxQueue),
+			connIDs:          m.connIDs,
+			mode:             mode,
+			decisionsCounter: m.metricsRegistry.NeedleDecisionsCounter().With("needle", k, "mode", mode.String()),
+			onTimeout:        onTimeout,
+			onError:          onError,
+			onOverload:       onOverload,
+			notifyOnClose:    notifyOnClose,
+			closeQueue:       queue,
+			conns:            registry,
+			shutdownCtx:      m.shutdownCtx,
+			tracer:           opentracing.GlobalTracer(),
 		}
 	}
+
+	m.resolveFallbacks(rootCtx, fallbacks)
 }
 
-func (m *Manager) GetNeedle(needle string, metadata map[string]string) Needle {
+// resolveFallbacks links the needles to their fallback needle, by name.
+// The needles whose fallback needle does not exist, or which are part of a fallback loop, are removed.
+func (m *Manager) resolveFallbacks(rootCtx context.Context, fallbacks map[string]string) {
+	invalid := map[string]bool{}
+
+	for name := range fallbacks {
+		logger := log.Ctx(rootCtx).With().Str(logs.NeedleName, name).Logger()
+
+		visited := map[string]bool{name: true}
+		for current := name; fallbacks[current] != ""; current = fallbacks[current] {
+			next := fallbacks[current]
+			if _, ok := m.needles[next]; !ok {
+				logger.Error().Msgf("fallback needle %s does not exist", next)
+				invalid[name] = true
+				break
+			}
+			if visited[next] {
+				logger.Error().Msgf("fallback needle loop detected on %s", next)
+				invalid[name] = true
+				break
+			}
+			visited[next] = true
+		}
+	}
+
+	for name := range invalid {
+		delete(m.needles, name)
+	}
+
+	for name, fallback := range fallbacks {
+		if invalid[name] {
+			continue
+		}
+		m.needles[name].(*BasicNeedle).fallback = m.needles[fallback].(*BasicNeedle)
+	}
+}
+
+// closeQueue returns the close queue of the needle, updated with the given configuration.
+func (m *Manager) closeQueue(name string, logger zerolog.Logger, config closeQueueConfig, c client.Client) *closeQueue {
+	m.closeQueuesMu.Lock()
+	defer m.closeQueuesMu.Unlock()
+
+	queue, exists := m.closeQueues[name]
+	if exists {
+		queue.update(config, c)
+		return queue
+	}
+
+	queue = newCloseQueue(name, logger, m.metricsRegistry, config, c)
+	m.closeQueues[name] = queue
+	return queue
+}
+
+// decisionLimiter returns the decision limiter of the needle, updated with the given limits,
+// or nil if the decisions of the needle are not limited.
+func (m *Manager) decisionLimiter(name string, maxConcurrent, maxQueue int) *decisionLimiter {
+	limiter, exists := m.limiters[name]
+	if maxConcurrent <= 0 {
+		if exists {
+			// Lifts the limit for the decisions still waiting for a slot.
+			limiter.update(0, maxQueue)
+			delete(m.limiters, name)
+		}
+		return nil
+	}
+
+	if exists {
+		limiter.update(maxConcurrent, maxQueue)
+		return limiter
+	}
+
+	limiter = newDecisionLimiter(maxConcurrent, maxQueue, m.metricsRegistry.NeedleDecisionQueueGauge().With("needle", name))
+	m.limiters[name] = limiter
+	return limiter
+}
+
+// CancelDecisions ends the pending decisions, which reject their connection, as well as the upcoming ones.
+// It is called when the instance starts shutting down.
+func (m *Manager) CancelDecisions() {
+	m.cancelDecisions()
+}
+
+// Shutdown cancels the pending decisions, delivers the pending close events until the context is done,
+// and finally tells the decision services that the instance is shutting down.
+// It is meant to be called once the entry points are stopped, so that their connections are closed.
+func (m *Manager) Shutdown(ctx context.Context) {
+	m.cancelDecisions()
+
+	m.closeQueuesMu.Lock()
+	queues := make([]*closeQueue, 0, len(m.closeQueues))
+	for _, queue := range m.closeQueues {
+		queues = append(queues, queue)
+	}
+	m.closeQueuesMu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, queue := range queues {
+		wg.Add(1)
+		go func(queue *closeQueue) {
+			defer wg.Done()
+
+			if left := queue.flush(ctx); left > 0 {
+				queue.logger.Warn().Msgf("Shutting down with %d close events not delivered", left)
+			}
+			queue.close()
+
+			if err := queue.currentClient().OnInstanceShutdown(m.connIDs.instance, ctx); err != nil {
+				queue.logger.Warn().Err(err).Msg("Cannot notify the decision service of the shutdown")
+			}
+		}(queue)
+	}
+	wg.Wait()
+}
+
+// GetNeedle returns the needle with the given name, sending the given metadata along with the connections.
+// The metadata values are Go templates, rendered with a MetadataContext for each connection.
+func (m *Manager) GetNeedle(needle string, metadata map[string]string) (Needle, error) {
 	n := m.needles[needle]
 	if n == nil {
-		return nil
+		return nil, fmt.Errorf("needle %q does not exist", needle)
 	}
 	if metadata == nil {
-		return n
+		return n, nil
 	}
+
+	meta, err := newMetadataTemplate(metadata)
+	if err != nil {
+		return nil, err
+	}
+
 	return &NeedleWithMeta{
 		needle: n,
-		meta:   metadata,
-	}
+		meta:   meta,
+	}, nil
 }
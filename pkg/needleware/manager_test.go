@@ -0,0 +1,95 @@
+package needleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/metrics"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"github.com/traefik/traefik/v3/pkg/needleware/client/clienttest"
+)
+
+func TestManager_Shutdown(t *testing.T) {
+	manager := NewManager(metrics.NewVoidRegistry())
+
+	c := &clienttest.Client{}
+	queue := manager.closeQueue("foo", zerolog.Nop(), closeQueueConfig{queueSize: 10, batchSize: 10, flushInterval: time.Hour, deliveryTimeout: time.Second}, c)
+	queue.push(&client.CloseEvent{ConnId: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	manager.Shutdown(ctx)
+
+	assert.Error(t, manager.shutdownCtx.Err())
+	assert.Equal(t, [][]int32{{1}}, connIDBatches(c))
+	assert.Equal(t, []string{manager.connIDs.instance}, c.Shutdowns())
+}
+
+func TestManager_BuildNeedles_Fallback(t *testing.T) {
+	needle := func(fallback string) *runtime.NeedleInfo {
+		return &runtime.NeedleInfo{Needle: &dynamic.Needle{
+			Endpoint: "127.0.0.1:1",
+			Decision: &dynamic.NeedleDecision{FallbackNeedle: fallback},
+		}}
+	}
+
+	manager := NewManager(metrics.NewVoidRegistry())
+	manager.BuildNeedles(context.Background(), &runtime.Configuration{Needles: map[string]*runtime.NeedleInfo{
+		"foo@file":     needle("bar@file"),
+		"bar@file":     needle(""),
+		"loop1@file":   needle("loop2@file"),
+		"loop2@file":   needle("loop1@file"),
+		"toloop@file":  needle("loop1@file"),
+		"missing@file": needle("unknown@file"),
+		"other@docker": needle("bar@file"),
+	}})
+	t.Cleanup(manager.cancelWatches)
+
+	var names []string
+	for name := range manager.needles {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"foo@file", "bar@file", "other@docker"}, names)
+
+	assert.Equal(t, manager.needles["bar@file"], manager.needles["foo@file"].(*BasicNeedle).fallback)
+	assert.Equal(t, manager.needles["bar@file"], manager.needles["other@docker"].(*BasicNeedle).fallback)
+	assert.Nil(t, manager.needles["bar@file"].(*BasicNeedle).fallback)
+}
+
+func TestManager_BuildNeedles_Limiter(t *testing.T) {
+	needles := func(maxConcurrent, maxQueue int) *runtime.Configuration {
+		return &runtime.Configuration{Needles: map[string]*runtime.NeedleInfo{
+			"foo@file": {Needle: &dynamic.Needle{
+				Endpoint: "127.0.0.1:1",
+				Client:   &dynamic.NeedleClient{MaxConcurrent: maxConcurrent, MaxQueue: maxQueue},
+			}},
+		}}
+	}
+
+	manager := NewManager(metrics.NewVoidRegistry())
+	t.Cleanup(func() { manager.cancelWatches() })
+
+	manager.BuildNeedles(context.Background(), needles(1, 0))
+	limiter := manager.needles["foo@file"].(*BasicNeedle).limiter
+	require.NotNil(t, limiter)
+	assert.Equal(t, defaultMaxQueue, limiter.maxQueue)
+
+	// The pending decision keeps its slot across the reload.
+	require.NoError(t, limiter.acquire(context.Background()))
+
+	manager.BuildNeedles(context.Background(), needles(1, 10))
+	assert.Same(t, limiter, manager.needles["foo@file"].(*BasicNeedle).limiter)
+	assert.Equal(t, 10, limiter.maxQueue)
+	assert.Equal(t, 1, limiter.active)
+
+	manager.BuildNeedles(context.Background(), needles(0, 0))
+	assert.Nil(t, manager.needles["foo@file"].(*BasicNeedle).limiter)
+	assert.Empty(t, manager.limiters)
+}
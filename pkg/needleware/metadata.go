@@ -0,0 +1,131 @@
+package needleware
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+)
+
+// MetadataContext is the data the metadata templates are rendered with, for each connection.
+type MetadataContext struct {
+	// Protocol is either tcp or udp.
+	Protocol   string
+	ClientIP   string
+	ClientPort int32
+	LocalIP    string
+	LocalPort  int32
+	// SNI is the server name sent by the client, when the connection is terminated by Traefik.
+	SNI        string
+	EntryPoint string
+	Router     string
+	Service    string
+
+	proxyProtocol *client.ProxyProtocolInfo
+}
+
+// TLV returns the value of the PROXY protocol TLV of the given type, or an empty string if it was not received.
+func (c *MetadataContext) TLV(typ int) string {
+	if c.proxyProtocol == nil {
+		return ""
+	}
+	return string(c.proxyProtocol.TLVs[uint32(typ)])
+}
+
+func newMetadataContext(criteria *client.DecisionCriteria) *MetadataContext {
+	ctx := &MetadataContext{
+		Protocol:      "udp",
+		ClientIP:      criteria.RemoteHost,
+		ClientPort:    criteria.RemotePort,
+		LocalIP:       criteria.LocalHost,
+		LocalPort:     criteria.LocalPort,
+		proxyProtocol: criteria.ProxyProtocol,
+	}
+	if criteria.Protocol == client.ProtocolTCP {
+		ctx.Protocol = "tcp"
+	}
+	if criteria.TLS != nil {
+		ctx.SNI = criteria.TLS.ServerName
+	}
+	if criteria.Route != nil {
+		ctx.EntryPoint = criteria.Route.EntryPoint
+		ctx.Router = criteria.Route.Router
+		ctx.Service = criteria.Route.Service
+	}
+	return ctx
+}
+
+// metadataFuncMap holds the functions available to the metadata templates.
+// As the metadata comes from the providers, and is sent to the decision service,
+// the functions giving access to the environment of Traefik are left out.
+var metadataFuncMap = newMetadataFuncMap()
+
+func newMetadataFuncMap() template.FuncMap {
+	funcMap := sprig.TxtFuncMap()
+	delete(funcMap, "env")
+	delete(funcMap, "expandenv")
+	delete(funcMap, "getHostByName")
+	return funcMap
+}
+
+// metadataTemplate renders the metadata of the connections, the values being Go templates.
+type metadataTemplate struct {
+	// static holds the values without template actions, which are the same for all the connections.
+	static    map[string]string
+	templates map[string]*template.Template
+}
+
+func newMetadataTemplate(metadata map[string]string) (*metadataTemplate, error) {
+	m := &metadataTemplate{
+		static:    map[string]string{},
+		templates: map[string]*template.Template{},
+	}
+
+	for key, value := range metadata {
+		if !strings.Contains(value, "{{") {
+			m.static[key] = value
+			continue
+		}
+
+		tmpl, err := template.New(key).Funcs(metadataFuncMap).Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template for metadata %q: %w", key, err)
+		}
+		m.templates[key] = tmpl
+	}
+
+	return m, nil
+}
+
+// render returns the metadata of the connection.
+// The values which cannot be rendered are left out, and reported in the returned error.
+func (m *metadataTemplate) render(criteria *client.DecisionCriteria) (map[string]string, error) {
+	if len(m.templates) == 0 {
+		return m.static, nil
+	}
+
+	metadata := make(map[string]string, len(m.static)+len(m.templates))
+	for key, value := range m.static {
+		metadata[key] = value
+	}
+
+	data := newMetadataContext(criteria)
+
+	var errs []string
+	var value strings.Builder
+	for key, tmpl := range m.templates {
+		value.Reset()
+		if err := tmpl.Execute(&value, data); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		metadata[key] = value.String()
+	}
+
+	if len(errs) > 0 {
+		return metadata, fmt.Errorf("rendering metadata: %s", strings.Join(errs, ", "))
+	}
+	return metadata, nil
+}
@@ -0,0 +1,103 @@
+package needleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+)
+
+func TestMetadataTemplate_Render(t *testing.T) {
+	criteria := &client.DecisionCriteria{
+		Protocol:   client.ProtocolTCP,
+		RemoteHost: "10.0.0.1",
+		RemotePort: 40000,
+		LocalHost:  "192.168.0.1",
+		LocalPort:  5432,
+		TLS:        &client.TLSInfo{ServerName: "foo.example.com"},
+		Route:      &client.Route{EntryPoint: "postgres", Router: "db@file"},
+		ProxyProtocol: &client.ProxyProtocolInfo{
+			TLVs: map[uint32][]byte{0xE0: []byte("vpc-1234")},
+		},
+	}
+
+	testCases := []struct {
+		desc        string
+		metadata    map[string]string
+		expected    map[string]string
+		expectedErr bool
+	}{
+		{
+			desc:     "static value",
+			metadata: map[string]string{"env": "prod"},
+			expected: map[string]string{"env": "prod"},
+		},
+		{
+			desc: "templates",
+			metadata: map[string]string{
+				"env":    "prod",
+				"tenant": `{{ .SNI | trimSuffix ".example.com" }}`,
+				"route":  "{{ .EntryPoint }}/{{ .Router }}",
+				"client": "{{ .ClientIP }}:{{ .ClientPort }}",
+				"local":  "{{ .Protocol }}://{{ .LocalIP }}:{{ .LocalPort }}",
+				"vpc":    "{{ .TLV 0xE0 }}",
+				"other":  "{{ .TLV 0xE1 }}",
+			},
+			expected: map[string]string{
+				"env":    "prod",
+				"tenant": "foo",
+				"route":  "postgres/db@file",
+				"client": "10.0.0.1:40000",
+				"local":  "tcp://192.168.0.1:5432",
+				"vpc":    "vpc-1234",
+				"other":  "",
+			},
+		},
+		{
+			desc: "failing template left out",
+			metadata: map[string]string{
+				"env":  "prod",
+				"fail": `{{ fail "no tenant" }}`,
+			},
+			expected:    map[string]string{"env": "prod"},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			tmpl, err := newMetadataTemplate(test.metadata)
+			require.NoError(t, err)
+
+			metadata, err := tmpl.render(criteria)
+			if test.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expected, metadata)
+		})
+	}
+}
+
+func TestNewMetadataTemplate_Invalid(t *testing.T) {
+	_, err := newMetadataTemplate(map[string]string{"tenant": "{{ .SNI "})
+	assert.Error(t, err)
+}
+
+func TestNewMetadataTemplate_EnvironmentFuncs(t *testing.T) {
+	testCases := []string{
+		`{{ env "HOME" }}`,
+		`{{ expandenv "$HOME" }}`,
+		`{{ getHostByName "localhost" }}`,
+	}
+
+	for _, value := range testCases {
+		_, err := newMetadataTemplate(map[string]string{"secret": value})
+		assert.ErrorContains(t, err, "not defined", value)
+	}
+}
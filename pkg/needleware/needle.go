@@ -2,10 +2,11 @@ package needleware
 
 import (
 	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"github.com/traefik/traefik/v3/pkg/tcp"
 )
 
 type Needle interface {
-	NewTCPCriteria(remoteAddr string, localAddr string) (*client.DecisionCriteria, error)
+	NewTCPCriteria(conn tcp.WriteCloser, route *client.Route) (*client.DecisionCriteria, error)
 	NewUDPCriteria(remoteAddr string, localAddr string) (*client.DecisionCriteria, error)
 	Decide(criteria *client.DecisionCriteria) (*DecisionWrapper, error)
 	OnConnClose(decision *DecisionWrapper)
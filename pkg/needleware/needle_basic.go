@@ -2,10 +2,15 @@ package needleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/cenkalti/backoff/v4"
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog"
+	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/needleware/client"
-	"math/rand"
+	"github.com/traefik/traefik/v3/pkg/tcp"
 	"time"
 )
 
@@ -16,18 +21,80 @@ const (
 	DecisionRefReject
 )
 
+// DecisionMode tells how the decisions of a needle are applied to the connections.
+type DecisionMode int
+
+const (
+	// DecisionModeEnforce waits for the decision before serving the connection.
+	DecisionModeEnforce DecisionMode = iota
+	// DecisionModeShadow always accepts the connection, the decision is only recorded.
+	DecisionModeShadow
+	// DecisionModeOptimistic accepts the connection right away, and closes it if the decision is a reject.
+	DecisionModeOptimistic
+)
+
+func (m DecisionMode) String() string {
+	switch m {
+	case DecisionModeShadow:
+		return "shadow"
+	case DecisionModeOptimistic:
+		return "optimistic"
+	default:
+		return "enforce"
+	}
+}
+
+// retryConfig tells how many times a decision is asked for, each attempt being bounded by perAttemptTimeout if set.
+// The attempts are spaced by a jittered exponential backoff starting at backoff, zero meaning no wait.
+type retryConfig struct {
+	attempts          int
+	perAttemptTimeout time.Duration
+	backoff           time.Duration
+}
+
+// maxRetryBackoffFactor bounds the backoff between two attempts, relative to the first one.
+const maxRetryBackoffFactor = 10
+
 type BasicNeedle struct {
+	name          string
 	client        client.Client
 	logger        zerolog.Logger
 	connTimeout   time.Duration
-	randSource    rand.Source
+	retry         retryConfig
+	hedgingDelay  time.Duration
+	limiter       *decisionLimiter
+	connIDs       *connIDGenerator
+	mode          DecisionMode
 	onTimeout     DecisionRef
 	onError       DecisionRef
+	onOverload    DecisionRef
 	notifyOnClose map[DecisionRef]bool
+	closeQueue    *closeQueue
+	// fallback decides instead of this needle on errors and timeouts, if set.
+	fallback *BasicNeedle
+	conns    *connRegistry
+	// shutdownCtx is canceled once the instance is shutting down, ending the pending decisions.
+	shutdownCtx context.Context
+	// tracer traces the decision requests, the connection ID being set on their span.
+	tracer opentracing.Tracer
+
+	decisionsCounter gokitmetrics.Counter
 }
 
-func (n *BasicNeedle) NewTCPCriteria(remoteAddr string, localAddr string) (*client.DecisionCriteria, error) {
-	return n.newCriteria(remoteAddr, localAddr, client.ProtocolTCP)
+func (n *BasicNeedle) NewTCPCriteria(conn tcp.WriteCloser, route *client.Route) (*client.DecisionCriteria, error) {
+	criteria, err := n.newCriteria(conn.RemoteAddr().String(), conn.LocalAddr().String(), client.ProtocolTCP)
+	if err != nil {
+		return nil, err
+	}
+
+	criteria.ProxyProtocol, err = proxyProtocolInfo(conn)
+	if err != nil {
+		return nil, err
+	}
+	criteria.TLS = tlsInfo(conn)
+	criteria.Route = route
+
+	return criteria, nil
 }
 
 func (n *BasicNeedle) NewUDPCriteria(remoteAddr string, localAddr string) (*client.DecisionCriteria, error) {
@@ -35,10 +102,135 @@ func (n *BasicNeedle) NewUDPCriteria(remoteAddr string, localAddr string) (*clie
 }
 
 func (n *BasicNeedle) Decide(criteria *client.DecisionCriteria) (*DecisionWrapper, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), n.connTimeout)
+	switch n.mode {
+	case DecisionModeShadow:
+		provisional := n.provisionalDecision(criteria)
+		go func() {
+			defer close(provisional.decided)
+
+			decision, err := n.decide(criteria)
+			n.record(criteria, decision, err)
+			provisional.setDecidedBy(decision, err)
+		}()
+		n.conns.add(provisional)
+		return provisional, nil
+
+	case DecisionModeOptimistic:
+		provisional := n.provisionalDecision(criteria)
+		provisional.late = &lateDecision{}
+		go func() {
+			defer close(provisional.decided)
+
+			decision, err := n.decide(criteria)
+			n.record(criteria, decision, err)
+			provisional.setDecidedBy(decision, err)
+			if err == nil && decision.ConnRejected() {
+				n.logger.Debug().Str(logs.ConnID, criteria.ConnUID).Msg("Closing optimistically accepted connection after a reject")
+				provisional.late.reject()
+			}
+		}()
+		n.conns.add(provisional)
+		return provisional, nil
+
+	default:
+		decision, err := n.decide(criteria)
+		n.record(criteria, decision, err)
+		if err == nil && decision.ConnAccepted() {
+			n.conns.add(decision)
+		}
+		return decision, err
+	}
+}
+
+// provisionalDecision is the decision applied to a connection while the actual one is made asynchronously.
+func (n *BasicNeedle) provisionalDecision(criteria *client.DecisionCriteria) *DecisionWrapper {
+	return &DecisionWrapper{
+		Status:       client.StatusDecisionLoaded,
+		DecisionCode: client.DecisionConnAccepted,
+		Criteria:     criteria,
+		Mode:         n.mode,
+		Needle:       n.name,
+		decided:      make(chan struct{}),
+	}
+}
+
+// record counts the decision and, when it is not enforced, logs what it would have done.
+func (n *BasicNeedle) record(criteria *client.DecisionCriteria, decision *DecisionWrapper, err error) {
+	if err != nil {
+		return
+	}
+
+	code, status := decisionLabels(decision)
+	n.decisionsCounter.With("decision", code, "status", status).Add(1)
+
+	if n.mode == DecisionModeEnforce {
+		return
+	}
+
+	n.logger.Debug().
+		Str(logs.ConnID, criteria.ConnUID).
+		Str(logs.NeedleMode, n.mode.String()).
+		Str(logs.NeedleDecision, code).
+		Str(logs.NeedleDecisionStatus, status).
+		Msgf("Asynchronous decision made for connection from %s:%d to %s:%d",
+			criteria.RemoteHost, criteria.RemotePort, criteria.LocalHost, criteria.LocalPort)
+}
+
+func decisionLabels(decision *DecisionWrapper) (string, string) {
+	code := "accept"
+	if decision.ConnRejected() {
+		code = "reject"
+	}
+
+	switch decision.Status {
+	case client.StatusDecisionError:
+		return code, "error"
+	case client.StatusDecisionTimeout:
+		return code, "timeout"
+	case client.StatusDecisionCanceled:
+		return code, "canceled"
+	case client.StatusDecisionOverloaded:
+		return code, "overloaded"
+	default:
+		return code, "loaded"
+	}
+}
+
+// decide makes the decision, recording which needle made it.
+func (n *BasicNeedle) decide(criteria *client.DecisionCriteria) (*DecisionWrapper, error) {
+	decision, err := n.ask(criteria)
+	if decision != nil && decision.Needle == "" {
+		decision.Needle = n.name
+	}
+	return decision, err
+}
+
+func (n *BasicNeedle) ask(criteria *client.DecisionCriteria) (*DecisionWrapper, error) {
+	if n.shutdownCtx.Err() != nil {
+		return n.decideOnShutdown(criteria)
+	}
+
+	ctx, cancel := context.WithTimeout(n.shutdownCtx, n.connTimeout)
 	defer cancel()
 
-	decisionResponse := n.client.OnConnOpened(criteria, ctx)
+	span, ctx := opentracing.StartSpanFromContextWithTracer(ctx, n.tracer, "needleware.decide")
+	defer span.Finish()
+	span.SetTag("traefik.needle.name", n.name)
+	span.SetTag("traefik.needle.conn_id", criteria.ConnUID)
+
+	if err := n.limiter.acquire(ctx); err != nil {
+		if n.shutdownCtx.Err() != nil {
+			return n.decideOnShutdown(criteria)
+		}
+		return n.decideOnOverload(criteria, err)
+	}
+	defer n.limiter.release()
+
+	decisionResponse := n.requestDecision(ctx, criteria)
+
+	if n.shutdownCtx.Err() != nil {
+		return n.decideOnShutdown(criteria)
+	}
 
 	switch decisionResponse.Status {
 	case client.StatusDecisionLoaded:
@@ -53,16 +245,151 @@ func (n *BasicNeedle) Decide(criteria *client.DecisionCriteria) (*DecisionWrappe
 	return nil, fmt.Errorf("should never happen: unknown decision status %d; please validate it in the client code", decisionResponse.Status)
 }
 
+// requestDecision asks for the decision, retrying and hedging the requests as configured until ctx is done.
+// Asking for a decision is idempotent, the connection being identified by the criteria.
+func (n *BasicNeedle) requestDecision(ctx context.Context, criteria *client.DecisionCriteria) *client.DecisionResponse {
+	var response *client.DecisionResponse
+	var b backoff.BackOff
+	for attempt := 1; ; attempt++ {
+		response = n.hedgedRequest(ctx, criteria)
+		if response.Loaded() || attempt >= n.retry.attempts || ctx.Err() != nil {
+			break
+		}
+
+		if b == nil {
+			b = n.retryBackOff()
+		}
+		wait := b.NextBackOff()
+
+		n.logger.Debug().Err(response.Err).Str(logs.ConnID, criteria.ConnUID).Msgf("Decision attempt %d failed, retrying in %s", attempt, wait)
+
+		if !sleepWithin(ctx, wait) {
+			break
+		}
+	}
+
+	// The budget of the client ran out, whatever the outcome of the last attempt.
+	if !response.Loaded() && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &client.DecisionResponse{Status: client.StatusDecisionTimeout}
+	}
+	return response
+}
+
+// retryBackOff returns the backoff spacing the attempts of a decision request.
+func (n *BasicNeedle) retryBackOff() backoff.BackOff {
+	if n.retry.backoff <= 0 {
+		return &backoff.ZeroBackOff{}
+	}
+
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = n.retry.backoff
+	b.MaxInterval = maxRetryBackoffFactor * n.retry.backoff
+	b.MaxElapsedTime = 0
+	b.Reset()
+	return b
+}
+
+// sleepWithin waits for the given duration, and returns false without waiting
+// if the deadline of the context comes first, as there would be no budget left for another attempt.
+// It also returns false if the context is done meanwhile.
+func sleepWithin(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= d {
+		return false
+	}
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return ctx.Err() == nil
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// hedgedRequest sends a second request if the first one is still pending after the hedging delay,
+// the first loaded decision winning. The slower request is canceled.
+func (n *BasicNeedle) hedgedRequest(ctx context.Context, criteria *client.DecisionCriteria) *client.DecisionResponse {
+	if n.hedgingDelay <= 0 {
+		return n.request(ctx, criteria)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses := make(chan *client.DecisionResponse, 2)
+	send := func() { responses <- n.request(ctx, criteria) }
+
+	go send()
+	pending := 1
+
+	timer := time.NewTimer(n.hedgingDelay)
+	defer timer.Stop()
+
+	var response *client.DecisionResponse
+	for pending > 0 {
+		select {
+		case response = <-responses:
+			pending--
+			if response.Loaded() {
+				return response
+			}
+		case <-timer.C:
+			n.logger.Debug().Str(logs.ConnID, criteria.ConnUID).Msgf("No decision after %s, hedging the request", n.hedgingDelay)
+			go send()
+			pending++
+		}
+	}
+	return response
+}
+
+// request sends a single decision request, bounded by the per attempt timeout.
+func (n *BasicNeedle) request(ctx context.Context, criteria *client.DecisionCriteria) *client.DecisionResponse {
+	if n.retry.perAttemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.retry.perAttemptTimeout)
+		defer cancel()
+	}
+
+	return n.client.OnConnOpened(criteria, ctx)
+}
+
 func (n *BasicNeedle) OnConnClose(decision *DecisionWrapper) {
+	// The connection is registered by this needle, even when decided by the fallback needle.
+	n.conns.remove(decision)
+
+	// The decision service must not be notified of the close before it is asked for the decision,
+	// which is bounded by the timeout of the needle.
+	decision.waitDecided()
+
+	n.notifyClose(decision)
+}
+
+// notifyClose notifies the decision service which decided on the connection that it is closed.
+func (n *BasicNeedle) notifyClose(decision *DecisionWrapper) {
+	// The decision service of the fallback needle is the one knowing about the connection.
+	if decision.Needle != n.name && n.fallback != nil {
+		n.fallback.notifyClose(decision)
+		return
+	}
+
 	if n.notifyOnClose[DecisionRefAccept] && decision.ConnAccepted() ||
 		n.notifyOnClose[DecisionRefReject] && decision.ConnRejected() {
-		// try delivering the event no matter how long it takes
-		go func() {
-			err := n.client.OnConnClosed(decision.Criteria.ConnId, context.Background())
-			if err != nil {
-				n.logger.Error().Err(err).Msgf("Cannot deliver OnConnClosed event")
-			}
-		}()
+		reason := decision.CloseReason
+		if decision.LateRejected() {
+			reason = client.CloseReasonRejected
+		}
+
+		n.closeQueue.push(&client.CloseEvent{
+			ConnId:  decision.Criteria.ConnId,
+			ConnUID: decision.Criteria.ConnUID,
+			Reason:  reason,
+			Needle:  decision.Needle,
+		})
 	}
 }
 
@@ -75,29 +402,38 @@ func (n *BasicNeedle) newCriteria(remoteAddr string, localAddr string, protocol
 	if err != nil {
 		return nil, err
 	}
+	connUID, connID := n.connIDs.next()
 	return &client.DecisionCriteria{
 		RemoteHost: remoteHost,
 		RemotePort: remotePort,
 		LocalHost:  localHost,
 		LocalPort:  localPort,
 		Protocol:   protocol,
-		ConnId:     n.generateConnId(),
+		ConnId:     connID,
+		ConnUID:    connUID,
 	}, nil
 }
 
 func (n *BasicNeedle) decideOnLoaded(criteria *client.DecisionCriteria, decision *client.DecisionResponse) (*DecisionWrapper, error) {
+	if decision.Decision != nil && decision.Decision.SyncRequested {
+		n.logger.Debug().Msg("Decision service requested the open connections")
+		n.conns.requestSync()
+	}
+
 	if decision.ConnAccepted() {
-		n.logger.Debug().Msgf("Connection from %s:%d to %s:%d accepted",
+		n.logger.Debug().Str(logs.ConnID, criteria.ConnUID).Msgf("Connection from %s:%d to %s:%d accepted",
 			criteria.RemoteHost, criteria.RemotePort, criteria.LocalHost, criteria.LocalPort)
 		return &DecisionWrapper{
 			Status:       client.StatusDecisionLoaded,
 			DecisionCode: client.DecisionConnAccepted,
 			Criteria:     criteria,
+			Target:       decision.Decision.Target,
+			Limits:       decision.Decision.Limits,
 		}, nil
 	}
 
 	if decision.ConnRejected() {
-		n.logger.Debug().Msgf("Connection from %s:%d to %s:%d rejected",
+		n.logger.Debug().Str(logs.ConnID, criteria.ConnUID).Msgf("Connection from %s:%d to %s:%d rejected",
 			criteria.RemoteHost, criteria.RemotePort, criteria.LocalHost, criteria.LocalPort)
 		return &DecisionWrapper{
 			Status:       client.StatusDecisionLoaded,
@@ -110,7 +446,10 @@ func (n *BasicNeedle) decideOnLoaded(criteria *client.DecisionCriteria, decision
 }
 
 func (n *BasicNeedle) decideOnError(criteria *client.DecisionCriteria, decision *client.DecisionResponse) (*DecisionWrapper, error) {
-	n.logger.Error().Err(decision.Err).Msgf("Cannot load decision")
+	n.logger.Error().Err(decision.Err).Str(logs.ConnID, criteria.ConnUID).Msgf("Cannot load decision")
+	if n.fallback != nil {
+		return n.decideWithFallback(criteria)
+	}
 	if n.onError == DecisionRefAccept {
 		return &DecisionWrapper{
 			Status:       client.StatusDecisionError,
@@ -129,7 +468,10 @@ func (n *BasicNeedle) decideOnError(criteria *client.DecisionCriteria, decision
 }
 
 func (n *BasicNeedle) decideOnTimeout(criteria *client.DecisionCriteria) (*DecisionWrapper, error) {
-	n.logger.Debug().Msgf("Decision timeout")
+	n.logger.Debug().Str(logs.ConnID, criteria.ConnUID).Msgf("Decision timeout")
+	if n.fallback != nil {
+		return n.decideWithFallback(criteria)
+	}
 	if n.onTimeout == DecisionRefAccept {
 		return &DecisionWrapper{
 			Status:       client.StatusDecisionTimeout,
@@ -147,7 +489,37 @@ func (n *BasicNeedle) decideOnTimeout(criteria *client.DecisionCriteria) (*Decis
 	return nil, fmt.Errorf("should never happen: unknown onTimeout code %d; please validate it while creating the needle", n.onError)
 }
 
-func (n *BasicNeedle) generateConnId() int32 {
-	// 10 digits random int from MaxInt32 / 2 to MaxInt32
-	return int32(1<<30 + rand.New(n.randSource).Intn(1<<30))
+func (n *BasicNeedle) decideOnOverload(criteria *client.DecisionCriteria, err error) (*DecisionWrapper, error) {
+	n.logger.Debug().Err(err).Str(logs.ConnID, criteria.ConnUID).Msgf("Decision overloaded")
+	if n.onOverload == DecisionRefAccept {
+		return &DecisionWrapper{
+			Status:       client.StatusDecisionOverloaded,
+			DecisionCode: client.DecisionConnAccepted,
+			Criteria:     criteria,
+		}, nil
+	}
+	if n.onOverload == DecisionRefReject {
+		return &DecisionWrapper{
+			Status:       client.StatusDecisionOverloaded,
+			DecisionCode: client.DecisionConnRejected,
+			Criteria:     criteria,
+		}, nil
+	}
+	return nil, fmt.Errorf("should never happen: unknown onOverload code %d; please validate it while creating the needle", n.onOverload)
+}
+
+// decideWithFallback asks the fallback needle for the decision, its mode being ignored.
+func (n *BasicNeedle) decideWithFallback(criteria *client.DecisionCriteria) (*DecisionWrapper, error) {
+	n.logger.Debug().Str(logs.ConnID, criteria.ConnUID).Msgf("Asking fallback needle %s for the decision", n.fallback.name)
+	return n.fallback.decide(criteria)
+}
+
+// decideOnShutdown rejects the connection, as the instance is shutting down.
+func (n *BasicNeedle) decideOnShutdown(criteria *client.DecisionCriteria) (*DecisionWrapper, error) {
+	n.logger.Debug().Str(logs.ConnID, criteria.ConnUID).Msgf("Decision canceled, shutting down")
+	return &DecisionWrapper{
+		Status:       client.StatusDecisionCanceled,
+		DecisionCode: client.DecisionConnRejected,
+		Criteria:     criteria,
+	}, nil
 }
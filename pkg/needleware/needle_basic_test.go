@@ -0,0 +1,478 @@
+package needleware
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gokitmetrics "github.com/go-kit/kit/metrics"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"github.com/traefik/traefik/v3/pkg/needleware/client/clienttest"
+)
+
+func TestBasicNeedle_Decide_Mode(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		mode             DecisionMode
+		expectedRejected bool
+		expectedLate     bool
+	}{
+		{
+			desc:             "enforce",
+			mode:             DecisionModeEnforce,
+			expectedRejected: true,
+		},
+		{
+			desc: "shadow",
+			mode: DecisionModeShadow,
+		},
+		{
+			desc:         "optimistic",
+			mode:         DecisionModeOptimistic,
+			expectedLate: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			counter := &labelsCounter{}
+			c := &clienttest.Client{Decide: func(context.Context, *client.DecisionCriteria) *client.DecisionResponse {
+				return clienttest.Reject()
+			}}
+			n := newTestNeedle(t, test.mode, counter, c)
+
+			criteria, err := n.NewUDPCriteria("10.0.0.1:1234", "10.0.0.2:53")
+			require.NoError(t, err)
+
+			decision, err := n.Decide(criteria)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedRejected, decision.ConnRejected())
+			assert.Equal(t, test.mode, decision.Mode)
+
+			// The decision is counted even if it is not enforced.
+			assert.Eventually(t, func() bool { return len(counter.get()) == 1 }, time.Second, 10*time.Millisecond)
+			assert.Equal(t, []string{"decision", "reject", "status", "loaded"}, counter.get()[0])
+
+			closed := make(chan struct{})
+			decision.OnLateReject(func() { close(closed) })
+
+			if !test.expectedLate {
+				assert.False(t, decision.LateRejected())
+				return
+			}
+
+			select {
+			case <-closed:
+			case <-time.After(time.Second):
+				t.Fatal("The connection should be closed by the late reject")
+			}
+
+			n.OnConnClose(decision)
+			assert.Eventually(t, func() bool { return len(c.Closed()) == 1 }, time.Second, 10*time.Millisecond)
+			assert.Equal(t, client.CloseReasonRejected, c.Closed()[0].Reason)
+		})
+	}
+}
+
+func TestBasicNeedle_OnConnClose_Provisional(t *testing.T) {
+	for _, mode := range []DecisionMode{DecisionModeShadow, DecisionModeOptimistic} {
+		mode := mode
+		t.Run(mode.String(), func(t *testing.T) {
+			t.Parallel()
+
+			release := make(chan struct{})
+			c := &clienttest.Client{Decide: func(context.Context, *client.DecisionCriteria) *client.DecisionResponse {
+				<-release
+				return clienttest.Accept()
+			}}
+
+			n := newTestNeedle(t, mode, &labelsCounter{}, c)
+
+			criteria, err := n.NewUDPCriteria("127.0.0.1:40000", "127.0.0.1:53")
+			require.NoError(t, err)
+
+			decision, err := n.Decide(criteria)
+			require.NoError(t, err)
+
+			closed := make(chan struct{})
+			go func() {
+				n.OnConnClose(decision)
+				close(closed)
+			}()
+
+			// The close waits for the decision request.
+			select {
+			case <-closed:
+				t.Fatal("The close should wait for the decision")
+			case <-time.After(50 * time.Millisecond):
+			}
+			assert.Empty(t, c.Batches())
+
+			close(release)
+
+			select {
+			case <-closed:
+			case <-time.After(time.Second):
+				t.Fatal("The close should be notified once decided")
+			}
+			require.Eventually(t, func() bool { return len(c.Batches()) == 1 }, time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestBasicNeedle_Decide_Tracing(t *testing.T) {
+	tracer := mocktracer.New()
+
+	var spanCtx opentracing.SpanContext
+	c := &clienttest.Client{Decide: func(ctx context.Context, _ *client.DecisionCriteria) *client.DecisionResponse {
+		if span := opentracing.SpanFromContext(ctx); span != nil {
+			spanCtx = span.Context()
+		}
+		return clienttest.Accept()
+	}}
+
+	n := newTestNeedle(t, DecisionModeEnforce, &labelsCounter{}, c)
+	n.name = "foo"
+	n.tracer = tracer
+
+	criteria, err := n.NewUDPCriteria("127.0.0.1:40000", "127.0.0.1:53")
+	require.NoError(t, err)
+
+	_, err = n.Decide(criteria)
+	require.NoError(t, err)
+
+	// The decision is requested within the span.
+	spans := tracer.FinishedSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, spans[0].Context(), spanCtx)
+	assert.Equal(t, "needleware.decide", spans[0].OperationName)
+	assert.Equal(t, "foo", spans[0].Tag("traefik.needle.name"))
+	assert.Equal(t, criteria.ConnUID, spans[0].Tag("traefik.needle.conn_id"))
+}
+
+func TestBasicNeedle_Decide_Shutdown(t *testing.T) {
+	shutdownCtx, cancelDecisions := context.WithCancel(context.Background())
+
+	decided := make(chan struct{})
+	c := &clienttest.Client{Decide: func(context.Context, *client.DecisionCriteria) *client.DecisionResponse {
+		cancelDecisions()
+		close(decided)
+		return clienttest.Accept()
+	}}
+
+	n := newTestNeedle(t, DecisionModeEnforce, &labelsCounter{}, c)
+	n.shutdownCtx = shutdownCtx
+
+	criteria, err := n.NewUDPCriteria("127.0.0.1:40000", "127.0.0.1:53")
+	require.NoError(t, err)
+
+	// The decision pending while shutting down is canceled.
+	decision, err := n.Decide(criteria)
+	require.NoError(t, err)
+	<-decided
+	assert.Equal(t, client.StatusDecisionCanceled, decision.Status)
+	assert.True(t, decision.ConnRejected())
+
+	// The next ones are not even asked for.
+	decision, err = n.Decide(criteria)
+	require.NoError(t, err)
+	assert.Equal(t, client.StatusDecisionCanceled, decision.Status)
+	assert.Len(t, c.Opened(), 1)
+}
+
+func TestBasicNeedle_Decide_RetryAndHedging(t *testing.T) {
+	// slowFirst blocks the first request until its context is done.
+	slowFirst := func(calls *atomic.Int32) func(context.Context, *client.DecisionCriteria) *client.DecisionResponse {
+		return func(ctx context.Context, _ *client.DecisionCriteria) *client.DecisionResponse {
+			if calls.Add(1) == 1 {
+				<-ctx.Done()
+				return &client.DecisionResponse{Status: client.StatusDecisionTimeout}
+			}
+			return clienttest.Reject()
+		}
+	}
+
+	failingFirst := func(calls *atomic.Int32) func(context.Context, *client.DecisionCriteria) *client.DecisionResponse {
+		return func(context.Context, *client.DecisionCriteria) *client.DecisionResponse {
+			if calls.Add(1) == 1 {
+				return &client.DecisionResponse{Status: client.StatusDecisionError, Err: errors.New("unavailable")}
+			}
+			return clienttest.Reject()
+		}
+	}
+
+	alwaysSlow := func(calls *atomic.Int32) func(context.Context, *client.DecisionCriteria) *client.DecisionResponse {
+		return func(ctx context.Context, _ *client.DecisionCriteria) *client.DecisionResponse {
+			calls.Add(1)
+			<-ctx.Done()
+			return &client.DecisionResponse{Status: client.StatusDecisionTimeout}
+		}
+	}
+
+	testCases := []struct {
+		desc           string
+		decide         func(calls *atomic.Int32) func(context.Context, *client.DecisionCriteria) *client.DecisionResponse
+		retry          retryConfig
+		hedgingDelay   time.Duration
+		expectedStatus client.DecisionStatus
+		expectedCalls  int32
+	}{
+		{
+			desc:           "no retry",
+			decide:         failingFirst,
+			expectedStatus: client.StatusDecisionError,
+			expectedCalls:  1,
+		},
+		{
+			desc:           "retry after an error",
+			decide:         failingFirst,
+			retry:          retryConfig{attempts: 3},
+			expectedStatus: client.StatusDecisionLoaded,
+			expectedCalls:  2,
+		},
+		{
+			desc:           "retry after a per attempt timeout",
+			decide:         slowFirst,
+			retry:          retryConfig{attempts: 2, perAttemptTimeout: 50 * time.Millisecond},
+			expectedStatus: client.StatusDecisionLoaded,
+			expectedCalls:  2,
+		},
+		{
+			desc:           "hedged request wins",
+			decide:         slowFirst,
+			hedgingDelay:   10 * time.Millisecond,
+			expectedStatus: client.StatusDecisionLoaded,
+			expectedCalls:  2,
+		},
+		{
+			desc:           "budget exhausted",
+			decide:         alwaysSlow,
+			retry:          retryConfig{attempts: 10, perAttemptTimeout: 100 * time.Millisecond},
+			expectedStatus: client.StatusDecisionTimeout,
+			expectedCalls:  3,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			c := &clienttest.Client{Decide: test.decide(&calls)}
+
+			n := newTestNeedle(t, DecisionModeEnforce, &labelsCounter{}, c)
+			n.connTimeout = 250 * time.Millisecond
+			n.retry = test.retry
+			n.hedgingDelay = test.hedgingDelay
+
+			criteria, err := n.NewUDPCriteria("127.0.0.1:40000", "127.0.0.1:53")
+			require.NoError(t, err)
+
+			decision, err := n.Decide(criteria)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedStatus, decision.Status)
+			assert.True(t, decision.ConnRejected())
+			assert.Equal(t, test.expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestBasicNeedle_Decide_RetryBackoff(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		backoff        time.Duration
+		expectedStatus client.DecisionStatus
+		expectedCalls  int32
+		expectedMin    time.Duration
+		expectedMax    time.Duration
+	}{
+		{
+			desc:           "attempts spaced by the backoff",
+			backoff:        40 * time.Millisecond,
+			expectedStatus: client.StatusDecisionError,
+			expectedCalls:  3,
+			// The jitter keeps the waits above half of the 40ms and 80ms backoffs.
+			expectedMin: 60 * time.Millisecond,
+			expectedMax: 250 * time.Millisecond,
+		},
+		{
+			desc:           "no wait beyond the budget",
+			backoff:        time.Second,
+			expectedStatus: client.StatusDecisionError,
+			expectedCalls:  1,
+			expectedMax:    100 * time.Millisecond,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			c := &clienttest.Client{Decide: func(context.Context, *client.DecisionCriteria) *client.DecisionResponse {
+				calls.Add(1)
+				return &client.DecisionResponse{Status: client.StatusDecisionError, Err: errors.New("unavailable")}
+			}}
+
+			n := newTestNeedle(t, DecisionModeEnforce, &labelsCounter{}, c)
+			n.connTimeout = 250 * time.Millisecond
+			n.retry = retryConfig{attempts: 3, backoff: test.backoff}
+
+			criteria, err := n.NewUDPCriteria("127.0.0.1:40000", "127.0.0.1:53")
+			require.NoError(t, err)
+
+			start := time.Now()
+			decision, err := n.Decide(criteria)
+			require.NoError(t, err)
+			elapsed := time.Since(start)
+
+			assert.Equal(t, test.expectedStatus, decision.Status)
+			assert.Equal(t, test.expectedCalls, calls.Load())
+			assert.GreaterOrEqual(t, elapsed, test.expectedMin)
+			assert.Less(t, elapsed, test.expectedMax)
+		})
+	}
+}
+
+func TestBasicNeedle_Decide_Fallback(t *testing.T) {
+	unavailable := &clienttest.Client{Decide: func(context.Context, *client.DecisionCriteria) *client.DecisionResponse {
+		return &client.DecisionResponse{Status: client.StatusDecisionError, Err: errors.New("unavailable")}
+	}}
+	n := newTestNeedle(t, DecisionModeEnforce, &labelsCounter{}, unavailable)
+	n.name = "foo"
+
+	local := &clienttest.Client{}
+	fallback := newTestNeedle(t, DecisionModeEnforce, &labelsCounter{}, local)
+	fallback.name = "bar"
+	n.fallback = fallback
+
+	criteria, err := n.NewUDPCriteria("127.0.0.1:40000", "127.0.0.1:53")
+	require.NoError(t, err)
+
+	decision, err := n.Decide(criteria)
+	require.NoError(t, err)
+	assert.True(t, decision.ConnAccepted())
+	assert.Equal(t, "bar", decision.Needle)
+	assert.Len(t, local.Opened(), 1)
+	assert.Len(t, n.conns.snapshot(), 1)
+
+	// The close event goes to the decision service of the fallback needle.
+	n.OnConnClose(decision)
+
+	assert.Empty(t, n.conns.snapshot())
+	assert.Empty(t, fallback.conns.snapshot())
+
+	require.Eventually(t, func() bool { return len(local.Batches()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "bar", local.Batches()[0][0].Needle)
+	assert.Empty(t, unavailable.Batches())
+}
+
+func TestBasicNeedle_Decide_Fallback_Provisional(t *testing.T) {
+	for _, mode := range []DecisionMode{DecisionModeShadow, DecisionModeOptimistic} {
+		mode := mode
+		t.Run(mode.String(), func(t *testing.T) {
+			t.Parallel()
+
+			unavailable := &clienttest.Client{Decide: func(context.Context, *client.DecisionCriteria) *client.DecisionResponse {
+				return &client.DecisionResponse{Status: client.StatusDecisionError, Err: errors.New("unavailable")}
+			}}
+			n := newTestNeedle(t, mode, &labelsCounter{}, unavailable)
+			n.name = "foo"
+
+			local := &clienttest.Client{}
+			fallback := newTestNeedle(t, DecisionModeEnforce, &labelsCounter{}, local)
+			fallback.name = "bar"
+			n.fallback = fallback
+
+			criteria, err := n.NewUDPCriteria("127.0.0.1:40000", "127.0.0.1:53")
+			require.NoError(t, err)
+
+			decision, err := n.Decide(criteria)
+			require.NoError(t, err)
+			assert.True(t, decision.ConnAccepted())
+			assert.Len(t, n.conns.snapshot(), 1)
+
+			// The close event goes to the decision service of the fallback needle, which made the actual decision.
+			n.OnConnClose(decision)
+
+			assert.Equal(t, "bar", decision.Needle)
+			assert.Len(t, local.Opened(), 1)
+			assert.Empty(t, n.conns.snapshot())
+			assert.Empty(t, fallback.conns.snapshot())
+
+			require.Eventually(t, func() bool { return len(local.Batches()) == 1 }, time.Second, 10*time.Millisecond)
+			assert.Equal(t, "bar", local.Batches()[0][0].Needle)
+			assert.Empty(t, unavailable.Batches())
+		})
+	}
+}
+
+func newTestNeedle(t *testing.T, mode DecisionMode, counter gokitmetrics.Counter, c client.Client) *BasicNeedle {
+	t.Helper()
+
+	return &BasicNeedle{
+		client:           c,
+		logger:           zerolog.Nop(),
+		connTimeout:      time.Second,
+		connIDs:          newConnIDGenerator("test"),
+		mode:             mode,
+		decisionsCounter: counter,
+		onTimeout:        DecisionRefReject,
+		onError:          DecisionRefReject,
+		notifyOnClose:    map[DecisionRef]bool{DecisionRefAccept: true},
+		closeQueue:       newTestCloseQueue(t, closeQueueConfig{queueSize: 10, batchSize: 1, flushInterval: time.Hour}, c),
+		conns:            newConnRegistry("test", zerolog.Nop(), c, time.Second),
+		shutdownCtx:      context.Background(),
+		tracer:           opentracing.NoopTracer{},
+	}
+}
+
+// labelsCounter records the label values of each increment.
+type labelsCounter struct {
+	mu     sync.Mutex
+	labels []string
+	root   *labelsCounter
+	adds   [][]string
+}
+
+func (c *labelsCounter) With(labelValues ...string) gokitmetrics.Counter {
+	root := c
+	if c.root != nil {
+		root = c.root
+	}
+	return &labelsCounter{labels: append(append([]string(nil), c.labels...), labelValues...), root: root}
+}
+
+func (c *labelsCounter) Add(float64) {
+	root := c
+	if c.root != nil {
+		root = c.root
+	}
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+
+	root.adds = append(root.adds, c.labels)
+}
+
+func (c *labelsCounter) get() [][]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([][]string(nil), c.adds...)
+}
@@ -1,24 +1,43 @@
 package needleware
 
 import (
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"github.com/traefik/traefik/v3/pkg/tcp"
 )
 
 type NeedleWithMeta struct {
 	needle Needle
-	meta   map[string]string
+	meta   *metadataTemplate
 }
 
-func (n *NeedleWithMeta) NewTCPCriteria(remoteAddr string, localAddr string) (*client.DecisionCriteria, error) {
-	criteria, err := n.needle.NewTCPCriteria(remoteAddr, localAddr)
-	criteria.Metadata = n.meta
-	return criteria, err
+func (n *NeedleWithMeta) NewTCPCriteria(conn tcp.WriteCloser, route *client.Route) (*client.DecisionCriteria, error) {
+	criteria, err := n.needle.NewTCPCriteria(conn, route)
+	if err != nil {
+		return nil, err
+	}
+	n.setMetadata(criteria)
+	return criteria, nil
 }
 
 func (n *NeedleWithMeta) NewUDPCriteria(remoteAddr string, localAddr string) (*client.DecisionCriteria, error) {
 	criteria, err := n.needle.NewUDPCriteria(remoteAddr, localAddr)
-	criteria.Metadata = n.meta
-	return criteria, err
+	if err != nil {
+		return nil, err
+	}
+	n.setMetadata(criteria)
+	return criteria, nil
+}
+
+// setMetadata renders the metadata of the connection, once its criteria are known.
+// The values which cannot be rendered are not sent, rather than failing the decision.
+func (n *NeedleWithMeta) setMetadata(criteria *client.DecisionCriteria) {
+	metadata, err := n.meta.render(criteria)
+	if err != nil {
+		log.Debug().Err(err).Str(logs.ConnID, criteria.ConnUID).Msg("Cannot render the needle metadata of the connection")
+	}
+	criteria.Metadata = metadata
 }
 
 func (n *NeedleWithMeta) Decide(criteria *client.DecisionCriteria) (*DecisionWrapper, error) {
@@ -0,0 +1,152 @@
+package refserver
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rules describes the decisions of the reference server.
+// The first matching rule decides, the default decision applies when none matches.
+//
+//	default: accept
+//	rules:
+//	  - name: block-private
+//	    match:
+//	      protocol: tcp
+//	      remoteCIDRs: ["10.0.0.0/8"]
+//	      localPorts: [5432]
+//	      serverName: db.example.com
+//	      entryPoint: postgres
+//	      metadata:
+//	        tenant: foo
+//	    decision: reject
+//	    delay: 100ms
+type Rules struct {
+	// Default is the decision applied when no rule matches: accept (the default) or reject.
+	Default string `yaml:"default"`
+	Rules   []Rule `yaml:"rules"`
+}
+
+// Rule associates a decision to the connections it matches.
+type Rule struct {
+	Name  string `yaml:"name"`
+	Match Match  `yaml:"match"`
+	// Decision is accept (the default) or reject.
+	Decision string `yaml:"decision"`
+	// Delay is how long to wait before answering, to simulate a slow decision service.
+	Delay  time.Duration `yaml:"delay"`
+	Target *Target       `yaml:"target"`
+	Limits *Limits       `yaml:"limits"`
+}
+
+// Match holds the conditions a connection must all fulfill to match a rule, empty ones being ignored.
+type Match struct {
+	// Protocol is tcp or udp.
+	Protocol    string            `yaml:"protocol"`
+	RemoteCIDRs []string          `yaml:"remoteCIDRs"`
+	LocalPorts  []int32           `yaml:"localPorts"`
+	ServerName  string            `yaml:"serverName"`
+	EntryPoint  string            `yaml:"entryPoint"`
+	Router      string            `yaml:"router"`
+	Metadata    map[string]string `yaml:"metadata"`
+
+	prefixes []netip.Prefix
+}
+
+// Target is the routing target of an accepting rule.
+type Target struct {
+	ServerAddress string `yaml:"serverAddress"`
+	Service       string `yaml:"service"`
+}
+
+// Limits are the limits of an accepting rule.
+type Limits struct {
+	UploadBytesPerSecond   uint64        `yaml:"uploadBytesPerSecond"`
+	DownloadBytesPerSecond uint64        `yaml:"downloadBytesPerSecond"`
+	MaxTotalBytes          uint64        `yaml:"maxTotalBytes"`
+	MaxDuration            time.Duration `yaml:"maxDuration"`
+}
+
+// LoadRules reads and validates the rules of the given YAML file.
+func LoadRules(path string) (*Rules, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRules(content)
+}
+
+// ParseRules parses and validates YAML rules.
+func ParseRules(content []byte) (*Rules, error) {
+	rules := &Rules{}
+	if err := yaml.Unmarshal(content, rules); err != nil {
+		return nil, fmt.Errorf("parsing rules: %w", err)
+	}
+
+	if err := validDecision(rules.Default); err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+
+	for i := range rules.Rules {
+		rule := &rules.Rules[i]
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i)
+		}
+
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+	}
+
+	return rules, nil
+}
+
+func (r *Rule) validate() error {
+	if err := validDecision(r.Decision); err != nil {
+		return err
+	}
+
+	if r.Delay < 0 {
+		return fmt.Errorf("invalid delay: %s", r.Delay)
+	}
+
+	switch strings.ToLower(r.Match.Protocol) {
+	case "", "tcp", "udp":
+	default:
+		return fmt.Errorf("unknown protocol: %s", r.Match.Protocol)
+	}
+
+	for _, cidr := range r.Match.RemoteCIDRs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid remote CIDR: %w", err)
+		}
+		r.Match.prefixes = append(r.Match.prefixes, prefix.Masked())
+	}
+
+	if r.rejects() && (r.Target != nil || r.Limits != nil) {
+		return errors.New("target and limits only apply to accepting rules")
+	}
+
+	return nil
+}
+
+func (r *Rule) rejects() bool {
+	return strings.EqualFold(r.Decision, "reject")
+}
+
+func validDecision(decision string) error {
+	switch strings.ToLower(decision) {
+	case "", "accept", "reject":
+		return nil
+	default:
+		return fmt.Errorf("unknown decision: %s", decision)
+	}
+}
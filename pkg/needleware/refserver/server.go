@@ -0,0 +1,184 @@
+// Package refserver implements a reference Needleware decision server, driven by static rules.
+// It is meant for local development and tests, not for production.
+package refserver
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/needleware/client/pb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// Server is a Needleware gRPC server deciding on the connections according to its rules.
+type Server struct {
+	pb.UnimplementedNeedlewareServer
+
+	rules *Rules
+}
+
+// NewServer creates a server deciding according to the given rules.
+func NewServer(rules *Rules) *Server {
+	return &Server{rules: rules}
+}
+
+// OnConnOpened implements the pb.NeedlewareServer interface.
+func (s *Server) OnConnOpened(ctx context.Context, conn *pb.Connection) (*pb.Decision, error) {
+	rule := s.match(conn)
+
+	logger := log.With().Str(logs.ConnID, conn.GetId().GetUid()).Logger()
+
+	if rule == nil {
+		decision := &pb.Decision{Code: decisionCode(s.rules.Default)}
+		logger.Info().Msgf("No rule matches the connection from %s, decision: %s", address(conn.GetRemoteAddress()), decision.GetCode())
+		return decision, nil
+	}
+
+	if rule.Delay > 0 {
+		timer := time.NewTimer(rule.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	decision := &pb.Decision{Code: decisionCode(rule.Decision)}
+	if rule.Target != nil {
+		decision.Target = &pb.RoutingTarget{
+			ServerAddress: rule.Target.ServerAddress,
+			Service:       rule.Target.Service,
+		}
+	}
+	if rule.Limits != nil {
+		decision.Limits = &pb.Limits{
+			UploadBytesPerSecond:   rule.Limits.UploadBytesPerSecond,
+			DownloadBytesPerSecond: rule.Limits.DownloadBytesPerSecond,
+			MaxTotalBytes:          rule.Limits.MaxTotalBytes,
+			MaxDurationMillis:      uint64(rule.Limits.MaxDuration.Milliseconds()),
+		}
+	}
+
+	logger.Info().Msgf("Rule %s matches the connection from %s, decision: %s", rule.Name, address(conn.GetRemoteAddress()), decision.GetCode())
+
+	return decision, nil
+}
+
+// OnConnClosed implements the pb.NeedlewareServer interface.
+func (s *Server) OnConnClosed(_ context.Context, event *pb.ConnectionClosed) (*emptypb.Empty, error) {
+	log.Info().Str(logs.ConnID, event.GetUid()).Msgf("Connection %d closed: %s", event.GetValue(), event.GetReason())
+	return &emptypb.Empty{}, nil
+}
+
+// OnConnClosedBatch implements the pb.NeedlewareServer interface.
+func (s *Server) OnConnClosedBatch(ctx context.Context, batch *pb.ConnectionClosedBatch) (*emptypb.Empty, error) {
+	for _, event := range batch.GetEvents() {
+		_, _ = s.OnConnClosed(ctx, event)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// OnInstanceShutdown implements the pb.NeedlewareServer interface.
+func (s *Server) OnInstanceShutdown(_ context.Context, instance *pb.Instance) (*emptypb.Empty, error) {
+	log.Info().Msgf("Instance %s is shutting down", instance.GetId())
+	return &emptypb.Empty{}, nil
+}
+
+// SyncOpenConnections implements the pb.NeedlewareServer interface.
+func (s *Server) SyncOpenConnections(_ context.Context, snapshot *pb.OpenConnections) (*emptypb.Empty, error) {
+	log.Info().Msgf("Instance %s has %d open connections", snapshot.GetInstance().GetId(), len(snapshot.GetConnections()))
+
+	for _, conn := range snapshot.GetConnections() {
+		log.Debug().Str(logs.ConnID, conn.GetConnection().GetId().GetUid()).
+			Msgf("Connection from %s open since %s, %d bytes uploaded, %d bytes downloaded",
+				address(conn.GetConnection().GetRemoteAddress()), time.UnixMilli(conn.GetStartedAtMillis()),
+				conn.GetBytesUploaded(), conn.GetBytesDownloaded())
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// match returns the first rule matching the connection, if any.
+func (s *Server) match(conn *pb.Connection) *Rule {
+	for i := range s.rules.Rules {
+		if s.rules.Rules[i].Match.matches(conn) {
+			return &s.rules.Rules[i]
+		}
+	}
+	return nil
+}
+
+func (m *Match) matches(conn *pb.Connection) bool {
+	if m.Protocol != "" && !strings.EqualFold(m.Protocol, conn.GetProtocol().String()) {
+		return false
+	}
+
+	if len(m.prefixes) > 0 && !m.matchesRemote(conn.GetRemoteAddress().GetHost()) {
+		return false
+	}
+
+	if len(m.LocalPorts) > 0 && !containsPort(m.LocalPorts, conn.GetLocalAddress().GetPort()) {
+		return false
+	}
+
+	if m.ServerName != "" && !strings.EqualFold(m.ServerName, conn.GetTls().GetServerName()) {
+		return false
+	}
+
+	if m.EntryPoint != "" && m.EntryPoint != conn.GetRoute().GetEntryPoint() {
+		return false
+	}
+
+	if m.Router != "" && m.Router != conn.GetRoute().GetRouter() {
+		return false
+	}
+
+	for key, value := range m.Metadata {
+		if conn.GetMetadata().GetData()[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *Match) matchesRemote(host string) bool {
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+
+	for _, prefix := range m.prefixes {
+		if prefix.Contains(addr.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPort(ports []int32, port int32) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+func decisionCode(decision string) pb.DecisionCode {
+	if strings.EqualFold(decision, "reject") {
+		return pb.DecisionCode_REJECT
+	}
+	return pb.DecisionCode_ACCEPT
+}
+
+func address(addr *pb.Address) string {
+	return net.JoinHostPort(addr.GetHost(), strconv.Itoa(int(addr.GetPort())))
+}
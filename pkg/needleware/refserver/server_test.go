@@ -0,0 +1,153 @@
+package refserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+	"github.com/traefik/traefik/v3/pkg/needleware/client/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const testRules = `
+default: reject
+rules:
+  - name: private
+    match:
+      protocol: tcp
+      remoteCIDRs: ["10.0.0.0/8"]
+    decision: reject
+  - name: postgres
+    match:
+      localPorts: [5432]
+      metadata:
+        tenant: foo
+    target:
+      service: db@file
+    limits:
+      maxDuration: 1m
+`
+
+func TestServer_OnConnOpened(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		conn     *pb.Connection
+		expected *pb.Decision
+	}{
+		{
+			desc: "first matching rule",
+			conn: &pb.Connection{
+				Protocol:      pb.Protocol_TCP,
+				RemoteAddress: &pb.Address{Host: "10.1.2.3", Port: 1234},
+				LocalAddress:  &pb.Address{Host: "127.0.0.1", Port: 5432},
+				Metadata:      &pb.Metadata{Data: map[string]string{"tenant": "foo"}},
+			},
+			expected: &pb.Decision{Code: pb.DecisionCode_REJECT},
+		},
+		{
+			desc: "accepting rule with target and limits",
+			conn: &pb.Connection{
+				Protocol:      pb.Protocol_TCP,
+				RemoteAddress: &pb.Address{Host: "192.168.1.1", Port: 1234},
+				LocalAddress:  &pb.Address{Host: "127.0.0.1", Port: 5432},
+				Metadata:      &pb.Metadata{Data: map[string]string{"tenant": "foo"}},
+			},
+			expected: &pb.Decision{
+				Code:   pb.DecisionCode_ACCEPT,
+				Target: &pb.RoutingTarget{Service: "db@file"},
+				Limits: &pb.Limits{MaxDurationMillis: 60000},
+			},
+		},
+		{
+			desc: "default decision",
+			conn: &pb.Connection{
+				Protocol:      pb.Protocol_UDP,
+				RemoteAddress: &pb.Address{Host: "192.168.1.1", Port: 1234},
+				LocalAddress:  &pb.Address{Host: "127.0.0.1", Port: 53},
+			},
+			expected: &pb.Decision{Code: pb.DecisionCode_REJECT},
+		},
+	}
+
+	rules, err := ParseRules([]byte(testRules))
+	require.NoError(t, err)
+
+	server := NewServer(rules)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			decision, err := server.OnConnOpened(context.Background(), test.conn)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected.GetCode(), decision.GetCode())
+			assert.Equal(t, test.expected.GetTarget().GetService(), decision.GetTarget().GetService())
+			assert.Equal(t, test.expected.GetLimits().GetMaxDurationMillis(), decision.GetLimits().GetMaxDurationMillis())
+		})
+	}
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		rules string
+	}{
+		{
+			desc:  "unknown decision",
+			rules: "rules: [{decision: drop}]",
+		},
+		{
+			desc:  "invalid CIDR",
+			rules: "rules: [{match: {remoteCIDRs: [foo]}}]",
+		},
+		{
+			desc:  "rejecting rule with target",
+			rules: "rules: [{decision: reject, target: {service: foo}}]",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseRules([]byte(test.rules))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestServer_GRPCClient(t *testing.T) {
+	rules, err := ParseRules([]byte("rules: [{match: {protocol: tcp}, decision: reject, delay: 10ms}]"))
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterNeedlewareServer(grpcServer, NewServer(rules))
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	c := client.NewGRPCClient(pb.NewNeedlewareClient(conn))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	response := c.OnConnOpened(&client.DecisionCriteria{Protocol: client.ProtocolTCP, RemoteHost: "127.0.0.1", RemotePort: 1234}, ctx)
+	require.NoError(t, response.Err)
+	assert.True(t, response.ConnRejected())
+
+	assert.NoError(t, c.OnConnClosedBatch([]*client.CloseEvent{{ConnId: 1}}, ctx))
+}
@@ -0,0 +1,21 @@
+package needleware
+
+import (
+	"context"
+
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
+)
+
+type routeContextKey struct{}
+
+// AddRouteInContext returns a copy of ctx holding the given route,
+// so that needle middlewares built within it can report where the connection comes through.
+func AddRouteInContext(ctx context.Context, route client.Route) context.Context {
+	return context.WithValue(ctx, routeContextKey{}, route)
+}
+
+// GetRoute returns the route stored in ctx, if any.
+func GetRoute(ctx context.Context) (client.Route, bool) {
+	route, ok := ctx.Value(routeContextKey{}).(client.Route)
+	return route, ok
+}
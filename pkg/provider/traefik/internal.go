@@ -181,7 +181,13 @@ func (i *Provider) getEntryPointPort(name string, def *static.Redirections) (str
 		return "", fmt.Errorf("'to' entry point field references a non-existing entry point: %s", def.EntryPoint.To)
 	}
 
-	_, port, err := net.SplitHostPort(dst.GetAddress())
+	address, err := dst.GetFirstAddress()
+	if err != nil {
+		return "", fmt.Errorf("invalid entry point %q address %q: %w",
+			name, i.staticCfg.EntryPoints[def.EntryPoint.To].Address, err)
+	}
+
+	_, port, err := net.SplitHostPort(address)
 	if err != nil {
 		return "", fmt.Errorf("invalid entry point %q address %q: %w",
 			name, i.staticCfg.EntryPoints[def.EntryPoint.To].Address, err)
@@ -351,6 +357,8 @@ func (i *Provider) serverTransportTCP(cfg *dynamic.Configuration) {
 	st := &dynamic.TCPServersTransport{
 		DialTimeout:   i.staticCfg.TCPServersTransport.DialTimeout,
 		DialKeepAlive: i.staticCfg.TCPServersTransport.DialKeepAlive,
+		IdleTimeout:   i.staticCfg.TCPServersTransport.IdleTimeout,
+		MaxLifetime:   i.staticCfg.TCPServersTransport.MaxLifetime,
 	}
 
 	if i.staticCfg.TCPServersTransport.TLS != nil {
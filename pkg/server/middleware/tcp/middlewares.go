@@ -10,6 +10,7 @@ import (
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/inflightconn"
 	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ipallowlist"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/ratelimiter"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 )
@@ -107,15 +108,22 @@ func (b *Builder) buildConstructor(ctx context.Context, middlewareName string) (
 		}
 	}
 
+	// RateLimit
+	if config.RateLimit != nil {
+		middleware = func(next tcp.Handler) (tcp.Handler, error) {
+			return ratelimiter.New(ctx, next, *config.RateLimit, middlewareName)
+		}
+	}
+
 	// Needles
 	if config.Needle != nil {
 		middleware = func(next tcp.Handler) (tcp.Handler, error) {
 			needleName := provider.GetQualifiedName(ctx, config.Needle.Id)
-			needle := b.needles.GetNeedle(needleName, config.Needle.Metadata)
-			if needle == nil {
-				return nil, fmt.Errorf("invalid middleware %q configuration: needle %q does not exist", middlewareName, needleName)
+			needle, err := b.needles.GetNeedle(needleName, config.Needle.Metadata)
+			if err != nil {
+				return nil, fmt.Errorf("invalid middleware %q configuration: %w", middlewareName, err)
 			}
-			return tcpneedle.New(ctx, next, needle, middlewareName)
+			return tcpneedle.New(ctx, next, *config.Needle, needle, middlewareName)
 		}
 	}
 
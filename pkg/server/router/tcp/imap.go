@@ -0,0 +1,97 @@
+package tcp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+const imapCapabilities = "IMAP4rev1 STARTTLS LOGINDISABLED"
+
+// imapNegotiator negotiates the IMAP STARTTLS session (RFC 3501), in which the server speaks first.
+type imapNegotiator struct{}
+
+func (imapNegotiator) serverFirst() bool {
+	return true
+}
+
+func (imapNegotiator) detect(*bufio.Reader) (bool, error) {
+	return false, nil
+}
+
+// negotiate greets the client, and answers its commands until it issues the STARTTLS command.
+func (imapNegotiator) negotiate(conn tcp.WriteCloser, br *bufio.Reader) (*startTLSBackend, error) {
+	if _, err := conn.Write([]byte("* OK [CAPABILITY " + imapCapabilities + "] traefik ready\r\n")); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < maxStartTLSCommands; i++ {
+		line, err := readStartTLSLine(br)
+		if err != nil {
+			return nil, err
+		}
+
+		tag, command, _ := strings.Cut(line, " ")
+		command, _, _ = strings.Cut(command, " ")
+
+		var reply string
+		switch strings.ToUpper(command) {
+		case "CAPABILITY":
+			reply = "* CAPABILITY " + imapCapabilities + "\r\n" + tag + " OK CAPABILITY completed\r\n"
+		case "NOOP":
+			reply = tag + " OK NOOP completed\r\n"
+		case "STARTTLS":
+			if _, err := conn.Write([]byte(tag + " OK Begin TLS negotiation now\r\n")); err != nil {
+				return nil, err
+			}
+
+			// The backend greeting has already been replaced by the one of Traefik.
+			greeting := startTLSExchange{response: imapGreeting}
+
+			return &startTLSBackend{
+				passthrough: []startTLSExchange{
+					greeting,
+					{request: []byte(tag + " STARTTLS\r\n"), response: imapTaggedReply(tag)},
+				},
+				termination: []startTLSExchange{greeting},
+			}, nil
+		case "LOGOUT":
+			_, _ = conn.Write([]byte("* BYE traefik logging out\r\n" + tag + " OK LOGOUT completed\r\n"))
+			return nil, errStartTLSClosed
+		default:
+			reply = tag + " BAD Must issue a STARTTLS command first\r\n"
+		}
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, errors.New("too many IMAP commands before STARTTLS")
+}
+
+// imapGreeting checks that the backend greeted with an OK status, as STARTTLS is only allowed before authentication.
+var imapGreeting = lineResponse(func(line string) (bool, error) {
+	if !strings.HasPrefix(line, "* OK") {
+		return false, fmt.Errorf("unexpected greeting from IMAP server: %q", line)
+	}
+	return true, nil
+})
+
+// imapTaggedReply returns the response checking that the backend completed the command with the given tag.
+// The untagged replies are ignored.
+func imapTaggedReply(tag string) func(b []byte) (int, error) {
+	return lineResponse(func(line string) (bool, error) {
+		if strings.HasPrefix(line, "* ") {
+			return false, nil
+		}
+
+		if !strings.HasPrefix(line, tag+" OK") {
+			return false, fmt.Errorf("unexpected reply from IMAP server: %q", line)
+		}
+		return true, nil
+	})
+}
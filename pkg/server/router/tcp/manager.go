@@ -13,6 +13,8 @@ import (
 	"github.com/traefik/traefik/v3/pkg/middlewares/snicheck"
 	httpmuxer "github.com/traefik/traefik/v3/pkg/muxer/http"
 	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
+	"github.com/traefik/traefik/v3/pkg/needleware"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	tcpservice "github.com/traefik/traefik/v3/pkg/server/service/tcp"
 	"github.com/traefik/traefik/v3/pkg/tcp"
@@ -79,7 +81,7 @@ func (m *Manager) BuildHandlers(rootCtx context.Context, entryPoints []string) m
 		routers := entryPointsRouters[entryPointName]
 
 		logger := log.Ctx(rootCtx).With().Str(logs.EntryPointName, entryPointName).Logger()
-		ctx := logger.WithContext(rootCtx)
+		ctx := needleware.AddRouteInContext(logger.WithContext(rootCtx), client.Route{EntryPoint: entryPointName})
 
 		handler, err := m.buildEntryPointHandler(ctx, routers, entryPointsRoutersHTTP[entryPointName], m.httpHandlers[entryPointName], m.httpsHandlers[entryPointName])
 		if err != nil {
@@ -260,10 +262,19 @@ func (m *Manager) buildEntryPointHandler(ctx context.Context, configs map[string
 
 // addTCPHandlers creates the TCP handlers defined in configs, and adds them to router.
 func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtime.TCPRouterInfo, router *Router) {
+	// The routes with the same TLS options share their TLS config,
+	// which tells whether a route can serve a connection terminated with the TLS config of another one.
+	tlsConfigs := make(map[string]*tls.Config)
+
 	for routerName, routerConfig := range configs {
 		logger := log.Ctx(ctx).With().Str(logs.RouterName, routerName).Logger()
 		ctxRouter := logger.WithContext(provider.AddInContext(ctx, routerName))
 
+		route, _ := needleware.GetRoute(ctxRouter)
+		route.Router = routerName
+		route.Service = provider.GetQualifiedName(ctxRouter, routerConfig.Service)
+		ctxRouter = needleware.AddRouteInContext(ctxRouter, route)
+
 		if routerConfig.Priority == 0 {
 			routerConfig.Priority = tcpmuxer.GetRulePriority(routerConfig.Rule)
 		}
@@ -348,19 +359,24 @@ func (m *Manager) addTCPHandlers(ctx context.Context, configs map[string]*runtim
 			tlsOptionsName = provider.GetQualifiedName(ctxRouter, tlsOptionsName)
 		}
 
-		tlsConf, err := m.tlsManager.Get(traefiktls.DefaultTLSStoreName, tlsOptionsName)
-		if err != nil {
-			routerConfig.AddError(err, true)
-			logger.Error().Err(err).Send()
-
-			logger.Debug().Msgf("Adding special TLS closing route for %q because broken TLS options %s", routerConfig.Rule, tlsOptionsName)
-
-			if err := router.muxerTCPTLS.AddRoute(routerConfig.Rule, routerConfig.Priority, &brokenTLSRouter{}); err != nil {
+		tlsConf, ok := tlsConfigs[tlsOptionsName]
+		if !ok {
+			tlsConf, err = m.tlsManager.Get(traefiktls.DefaultTLSStoreName, tlsOptionsName)
+			if err != nil {
 				routerConfig.AddError(err, true)
 				logger.Error().Err(err).Send()
+
+				logger.Debug().Msgf("Adding special TLS closing route for %q because broken TLS options %s", routerConfig.Rule, tlsOptionsName)
+
+				if err := router.muxerTCPTLS.AddRoute(routerConfig.Rule, routerConfig.Priority, &brokenTLSRouter{}); err != nil {
+					routerConfig.AddError(err, true)
+					logger.Error().Err(err).Send()
+				}
+
+				continue
 			}
 
-			continue
+			tlsConfigs[tlsOptionsName] = tlsConf
 		}
 
 		// Now that the Rule is not just about the Host, we could theoretically have a config like:
@@ -0,0 +1,187 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+const (
+	mysqlProtocolVersion = 10
+	mysqlSSLRequestLen   = 32
+	mysqlNativePassword  = "mysql_native_password"
+
+	mysqlClientSSL = 0x00000800
+	// mysqlCapabilities are the capabilities announced by Traefik,
+	// which have to be supported by the backends as well, as the client keeps on using them with the backend.
+	mysqlCapabilities = 0x00000001 | // CLIENT_LONG_PASSWORD
+		0x00000002 | // CLIENT_FOUND_ROWS
+		0x00000004 | // CLIENT_LONG_FLAG
+		0x00000008 | // CLIENT_CONNECT_WITH_DB
+		0x00000200 | // CLIENT_PROTOCOL_41
+		mysqlClientSSL |
+		0x00002000 | // CLIENT_TRANSACTIONS
+		0x00008000 | // CLIENT_SECURE_CONNECTION
+		0x00010000 | // CLIENT_MULTI_STATEMENTS
+		0x00020000 | // CLIENT_MULTI_RESULTS
+		0x00080000 | // CLIENT_PLUGIN_AUTH
+		0x00100000 | // CLIENT_CONNECT_ATTRS
+		0x00200000 // CLIENT_PLUGIN_AUTH_LENENC_CLIENT_DATA
+)
+
+// mysqlNegotiator negotiates the MySQL STARTTLS session (SSLRequest), in which the server speaks first.
+// As the client authenticates on the scramble of the greeting sent by Traefik,
+// the backend has to switch the authentication method to have the client authenticate on its own scramble,
+// which is the case when the account does not use mysql_native_password, the method announced by Traefik.
+// The backends announcing mysql_native_password as their default method are therefore rejected.
+// The TLS session can only be passed through to the backend,
+// as the client authenticates within the TLS session.
+type mysqlNegotiator struct{}
+
+func (mysqlNegotiator) serverFirst() bool {
+	return true
+}
+
+func (mysqlNegotiator) detect(*bufio.Reader) (bool, error) {
+	return false, nil
+}
+
+// negotiate greets the client, and reads the SSLRequest it sends before starting TLS.
+func (mysqlNegotiator) negotiate(conn tcp.WriteCloser, br *bufio.Reader) (*startTLSBackend, error) {
+	greeting, err := mysqlGreeting()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = conn.Write(greeting); err != nil {
+		return nil, err
+	}
+
+	sslRequest := make([]byte, 4+mysqlSSLRequestLen)
+	if _, err = io.ReadFull(br, sslRequest[:4]); err != nil {
+		return nil, err
+	}
+
+	if mysqlPacketLen(sslRequest) != mysqlSSLRequestLen {
+		return nil, errors.New("MySQL client did not request TLS")
+	}
+
+	if _, err = io.ReadFull(br, sslRequest[4:]); err != nil {
+		return nil, err
+	}
+
+	if binary.LittleEndian.Uint32(sslRequest[4:8])&mysqlClientSSL == 0 {
+		return nil, errors.New("MySQL client did not request TLS")
+	}
+
+	return &startTLSBackend{
+		passthrough: []startTLSExchange{
+			// The backend greeting has already been replaced by the one of Traefik.
+			{response: mysqlBackendGreeting},
+			// The backend starts the TLS session right after the SSLRequest.
+			{request: sslRequest},
+		},
+		noTermination: true,
+	}, nil
+}
+
+// mysqlGreeting returns the initial handshake packet (protocol version 10) sent to the clients.
+func mysqlGreeting() ([]byte, error) {
+	scramble := make([]byte, 20)
+	if _, err := rand.Read(scramble); err != nil {
+		return nil, err
+	}
+	// The scramble is sent as a null-terminated string.
+	for i := range scramble {
+		scramble[i] = scramble[i]%0x7f + 1
+	}
+
+	var payload bytes.Buffer
+	payload.WriteByte(mysqlProtocolVersion)
+	payload.WriteString("8.0.0-traefik\x00")
+	_ = binary.Write(&payload, binary.LittleEndian, uint32(1)) // connection ID
+	payload.Write(scramble[:8])
+	payload.WriteByte(0)
+	_ = binary.Write(&payload, binary.LittleEndian, uint16(mysqlCapabilities&0xffff))
+	payload.WriteByte(0x21)                                         // utf8_general_ci
+	_ = binary.Write(&payload, binary.LittleEndian, uint16(0x0002)) // SERVER_STATUS_AUTOCOMMIT
+	_ = binary.Write(&payload, binary.LittleEndian, uint16(mysqlCapabilities>>16))
+	payload.WriteByte(byte(len(scramble) + 1))
+	payload.Write(make([]byte, 10))
+	payload.Write(scramble[8:])
+	payload.WriteByte(0)
+	payload.WriteString(mysqlNativePassword + "\x00")
+
+	header := []byte{byte(payload.Len()), byte(payload.Len() >> 8), byte(payload.Len() >> 16), 0}
+	return append(header, payload.Bytes()...), nil
+}
+
+// mysqlBackendGreeting checks that the backend greeted with an initial handshake packet allowing TLS.
+func mysqlBackendGreeting(b []byte) (int, error) {
+	if len(b) < 4 || len(b) < 4+mysqlPacketLen(b) {
+		return 0, nil
+	}
+
+	n := 4 + mysqlPacketLen(b)
+	payload := b[4:n]
+
+	if len(payload) == 0 || payload[0] != mysqlProtocolVersion {
+		return 0, errors.New("unexpected greeting from MySQL server")
+	}
+
+	// Skips the server version, the connection ID, the first part of the scramble and the filler.
+	i := bytes.IndexByte(payload[1:], 0)
+	if i < 0 || len(payload) < 1+i+1+4+8+1+2 {
+		return 0, errors.New("invalid greeting from MySQL server")
+	}
+
+	capabilities := binary.LittleEndian.Uint16(payload[1+i+1+4+8+1:])
+	if capabilities&mysqlClientSSL == 0 {
+		return 0, errors.New("MySQL server does not support TLS")
+	}
+
+	if mysqlAuthPluginName(payload[1+i+1+4+8+1:]) == mysqlNativePassword {
+		return 0, errors.New("MySQL server authenticates with mysql_native_password, which is not supported with STARTTLS: " +
+			"the client authenticates on the scramble sent by Traefik, unless the server switches to another authentication method")
+	}
+
+	return n, nil
+}
+
+// mysqlAuthPluginName returns the name of the authentication method announced in a greeting,
+// from its capability flags onwards, or an empty string if none is announced.
+func mysqlAuthPluginName(b []byte) string {
+	// The capability flags (lower bytes), the character set, the status flags,
+	// the capability flags (upper bytes), the length of the scramble, and the reserved bytes.
+	if len(b) < 2+1+2+2+1+10 {
+		return ""
+	}
+
+	if binary.LittleEndian.Uint16(b[5:])&(0x00080000>>16) == 0 { // CLIENT_PLUGIN_AUTH
+		return ""
+	}
+
+	// The second part of the scramble is at least 13 bytes long.
+	scrambleLen := 13
+	if int(b[7])-8 > scrambleLen {
+		scrambleLen = int(b[7]) - 8
+	}
+
+	b = b[18:]
+	if len(b) < scrambleLen {
+		return ""
+	}
+
+	name, _, _ := bytes.Cut(b[scrambleLen:], []byte{0})
+	return string(name)
+}
+
+// mysqlPacketLen returns the payload length of the packet starting b.
+func mysqlPacketLen(b []byte) int {
+	return int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+}
@@ -0,0 +1,79 @@
+package tcp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// pop3Negotiator negotiates the POP3 STARTTLS session (STLS, RFC 2595), in which the server speaks first.
+type pop3Negotiator struct{}
+
+func (pop3Negotiator) serverFirst() bool {
+	return true
+}
+
+func (pop3Negotiator) detect(*bufio.Reader) (bool, error) {
+	return false, nil
+}
+
+// negotiate greets the client, and answers its commands until it issues the STLS command.
+func (pop3Negotiator) negotiate(conn tcp.WriteCloser, br *bufio.Reader) (*startTLSBackend, error) {
+	if _, err := conn.Write([]byte("+OK traefik ready\r\n")); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < maxStartTLSCommands; i++ {
+		line, err := readStartTLSLine(br)
+		if err != nil {
+			return nil, err
+		}
+
+		command, _, _ := strings.Cut(line, " ")
+
+		var reply string
+		switch strings.ToUpper(command) {
+		case "CAPA":
+			reply = "+OK Capability list follows\r\nSTLS\r\n.\r\n"
+		case "NOOP":
+			reply = "+OK\r\n"
+		case "STLS":
+			if _, err := conn.Write([]byte("+OK Begin TLS negotiation\r\n")); err != nil {
+				return nil, err
+			}
+
+			// The backend greeting has already been replaced by the one of Traefik.
+			greeting := startTLSExchange{response: pop3Reply}
+
+			return &startTLSBackend{
+				passthrough: []startTLSExchange{
+					greeting,
+					{request: []byte("STLS\r\n"), response: pop3Reply},
+				},
+				termination: []startTLSExchange{greeting},
+			}, nil
+		case "QUIT":
+			_, _ = conn.Write([]byte("+OK Bye\r\n"))
+			return nil, errStartTLSClosed
+		default:
+			reply = "-ERR Must issue a STLS command first\r\n"
+		}
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, errors.New("too many POP3 commands before STLS")
+}
+
+// pop3Reply checks that the backend replied with a positive status.
+var pop3Reply = lineResponse(func(line string) (bool, error) {
+	if !strings.HasPrefix(line, "+OK") {
+		return false, fmt.Errorf("unexpected reply from POP3 server: %q", line)
+	}
+	return true, nil
+})
@@ -42,6 +42,15 @@ type Router struct {
 	// hostHTTPTLSConfig contains TLS configs keyed by SNI.
 	// A nil config is the hint to set up a brokenTLSRouter.
 	hostHTTPTLSConfig map[string]*tls.Config // TLS configs keyed by SNI
+
+	// needle decides on the connections before they are routed, if the entry point has one.
+	needle tcp.Handler
+
+	// STARTTLS negotiators.
+	// startTLSServerFirst negotiates every connection, for a protocol in which the server speaks first.
+	startTLSServerFirst startTLSNegotiator
+	// startTLSClientFirst negotiate the connections they detect, for the protocols in which the client speaks first.
+	startTLSClientFirst []startTLSNegotiator
 }
 
 // NewRouter returns a new TCP router.
@@ -61,11 +70,17 @@ func NewRouter() (*Router, error) {
 		return nil, err
 	}
 
-	return &Router{
+	router := &Router{
 		muxerTCP:    *muxTCP,
 		muxerTCPTLS: *muxTCPTLS,
 		muxerHTTPS:  *muxHTTPS,
-	}, nil
+	}
+
+	if err := router.SetStartTLS(defaultStartTLSProtocols); err != nil {
+		return nil, err
+	}
+
+	return router, nil
 }
 
 // GetTLSGetClientInfo is called after a ClientHello is received from a client.
@@ -79,8 +94,28 @@ func (r *Router) GetTLSGetClientInfo() func(info *tls.ClientHelloInfo) (*tls.Con
 	}
 }
 
+// SetNeedle sets the needle deciding on the connections before any TLS handling or routing.
+func (r *Router) SetNeedle(needle tcp.Constructor) error {
+	handler, err := needle(tcp.HandlerFunc(r.route))
+	if err != nil {
+		return err
+	}
+
+	r.needle = handler
+	return nil
+}
+
 // ServeTCP forwards the connection to the right TCP/HTTP handler.
 func (r *Router) ServeTCP(conn tcp.WriteCloser) {
+	if r.needle != nil {
+		r.needle.ServeTCP(conn)
+		return
+	}
+
+	r.route(conn)
+}
+
+func (r *Router) route(conn tcp.WriteCloser) {
 	// Handling Non-TLS TCP connection early if there is neither HTTP(S) nor TLS routers on the entryPoint,
 	// and if there is at least one non-TLS TCP router.
 	// In the case of a non-TLS TCP client (that does not "send" first),
@@ -109,14 +144,14 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 
 	// TODO -- Check if ProxyProtocol changes the first bytes of the request
 	br := bufio.NewReader(conn)
-	postgres, err := isPostgres(br)
+	negotiator, err := r.startTLSNegotiator(br)
 	if err != nil {
 		conn.Close()
 		return
 	}
 
-	if postgres {
-		r.servePostgres(r.GetConn(conn, getPeeked(br)))
+	if negotiator != nil {
+		r.serveStartTLS(r.GetConn(conn, getPeeked(br)), negotiator)
 		return
 	}
 
@@ -157,6 +192,8 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 		return
 	}
 
+	connData.SetTLSVersions(hello.versions)
+
 	// For real, the handler eventually used for HTTPS is (almost) always the same:
 	// it is the httpsForwarder that is used for all HTTPS connections that match
 	// (which is also incidentally the same used in the last block below for 404s).
@@ -173,8 +210,9 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 
 	// Contains also TCP TLS passthrough routes.
 	handlerTCPTLS, catchAllTCPTLS := r.muxerTCPTLS.Match(connData)
+	handlerTCPTLS = r.clientCertRouter(handlerTCPTLS, connData)
 	if handlerTCPTLS != nil && !catchAllTCPTLS {
-		handlerTCPTLS.ServeTCP(r.GetConn(conn, hello.peeked))
+		handlerTCPTLS.ServeTCP(r.GetConn(tcp.WithServerName(conn, hello.serverName), hello.peeked))
 		return
 	}
 
@@ -188,7 +226,7 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 
 	// Fallback on TCP TLS catchAll.
 	if handlerTCPTLS != nil {
-		handlerTCPTLS.ServeTCP(r.GetConn(conn, hello.peeked))
+		handlerTCPTLS.ServeTCP(r.GetConn(tcp.WithServerName(conn, hello.serverName), hello.peeked))
 		return
 	}
 
@@ -201,6 +239,68 @@ func (r *Router) ServeTCP(conn tcp.WriteCloser) {
 	conn.Close()
 }
 
+// clientCertRouter returns, if any TCP TLS route depends on the client certificate,
+// a handler terminating the TLS connection with the TLS config of the given TLS handler,
+// and routing the connection again once the handshake tells whether the client presented a certificate.
+// The connection is closed if the route it then matches uses another TLS config.
+// Otherwise, or if the handler passes the TLS connection through, it returns the given handler.
+func (r *Router) clientCertRouter(handler tcp.Handler, connData tcpmuxer.ConnData) tcp.Handler {
+	tlsHandler, ok := handler.(*tcp.TLSHandler)
+	if !ok || !r.muxerTCPTLS.HasClientCertRoutes() {
+		return handler
+	}
+
+	return &tcp.TLSHandler{
+		Next: tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+			tlsConn, ok := findTLSConn(conn)
+			if !ok {
+				log.Error().Msg("Cannot find the TLS connection to route on the client certificate")
+				conn.Close()
+				return
+			}
+
+			if err := tlsConn.Handshake(); err != nil {
+				log.Debug().Err(err).Msg("Error while handshaking TLS connection")
+				conn.Close()
+				return
+			}
+
+			connData.SetClientCert(len(tlsConn.ConnectionState().PeerCertificates) > 0)
+
+			// The TLS connection is already terminated,
+			// and can therefore only be served by a route terminating TLS with the same TLS config,
+			// as the client authentication of another config has not been enforced.
+			handler, _ := r.muxerTCPTLS.Match(connData)
+			matched, ok := handler.(*tcp.TLSHandler)
+			if !ok || matched.Config != tlsHandler.Config {
+				log.Debug().Msg("The route matching the client certificate does not use the TLS config of the handshake, closing the connection")
+				conn.Close()
+				return
+			}
+
+			matched.Next.ServeTCP(conn)
+		}),
+		Config: tlsHandler.Config,
+	}
+}
+
+// findTLSConn returns the TLS connection among conn and the connections it wraps, if any.
+func findTLSConn(conn net.Conn) (*tls.Conn, bool) {
+	for conn != nil {
+		if tlsConn, ok := conn.(*tls.Conn); ok {
+			return tlsConn, true
+		}
+
+		unwrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			break
+		}
+		conn = unwrapper.NetConn()
+	}
+
+	return nil, false
+}
+
 // AddRoute defines a handler for the given rule.
 func (r *Router) AddRoute(rule string, priority int, target tcp.Handler) error {
 	return r.muxerTCP.AddRoute(rule, priority, target)
@@ -321,9 +421,15 @@ func (c *Conn) Read(p []byte) (n int, err error) {
 	return c.WriteCloser.Read(p)
 }
 
+// NetConn returns the underlying connection.
+func (c *Conn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
 type clientHello struct {
 	serverName string   // SNI server name
 	protos     []string // ALPN protocols list
+	versions   []uint16 // TLS versions supported by the client
 	isTLS      bool     // whether we are a TLS handshake
 	peeked     string   // the bytes peeked from the hello while getting the info
 }
@@ -385,10 +491,12 @@ func clientHelloInfo(br *bufio.Reader) (*clientHello, error) {
 
 	sni := ""
 	var protos []string
+	var versions []uint16
 	server := tls.Server(helloSniffConn{r: bytes.NewReader(helloBytes)}, &tls.Config{
 		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
 			sni = hello.ServerName
 			protos = hello.SupportedProtos
+			versions = hello.SupportedVersions
 			return nil, nil
 		},
 	})
@@ -399,6 +507,7 @@ func clientHelloInfo(br *bufio.Reader) (*clientHello, error) {
 		isTLS:      true,
 		peeked:     getPeeked(br),
 		protos:     protos,
+		versions:   versions,
 	}, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
@@ -18,10 +19,12 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	tcpmiddleware "github.com/traefik/traefik/v3/pkg/server/middleware/tcp"
 	"github.com/traefik/traefik/v3/pkg/server/service/tcp"
 	tcp2 "github.com/traefik/traefik/v3/pkg/tcp"
 	traefiktls "github.com/traefik/traefik/v3/pkg/tls"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
 )
 
 type applyRouter func(conf *runtime.Configuration)
@@ -164,7 +167,7 @@ func Test_Routing(t *testing.T) {
 
 	dialerManager := tcp2.NewDialerManager(nil)
 	dialerManager.Update(map[string]*dynamic.TCPServersTransport{"default@internal": {}})
-	serviceManager := tcp.NewManager(conf, dialerManager)
+	serviceManager := tcp.NewManager(conf, dialerManager, metrics.NewVoidRegistry())
 
 	// Creates the tlsManager and defines the TLS 1.0 and 1.2 TLSOptions.
 	tlsManager := traefiktls.NewManager()
@@ -956,6 +959,192 @@ func TestPostgres(t *testing.T) {
 	require.Equal(t, []byte("OK"), b)
 }
 
+func TestRouter_SetNeedle(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		reject   bool
+		expected bool
+	}{
+		{
+			desc:     "accepted connection is routed",
+			expected: true,
+		},
+		{
+			desc:   "rejected connection is not routed",
+			reject: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			var routed bool
+			err = router.AddRoute("HostSNI(`*`)", 0, tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+				routed = true
+			}))
+			require.NoError(t, err)
+
+			var decided bool
+			err = router.SetNeedle(func(next tcp2.Handler) (tcp2.Handler, error) {
+				return tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+					decided = true
+					if !test.reject {
+						next.ServeTCP(conn)
+					}
+				}), nil
+			})
+			require.NoError(t, err)
+
+			router.ServeTCP(NewMockConn())
+
+			assert.True(t, decided)
+			assert.Equal(t, test.expected, routed)
+		})
+	}
+}
+
+func TestRouter_ClientCertPresent(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		clientCert bool
+		expected   string
+	}{
+		{
+			desc:       "client presenting a certificate",
+			clientCert: true,
+			expected:   "with certificate",
+		},
+		{
+			desc:     "client presenting no certificate",
+			expected: "without certificate",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cert, err := generate.DefaultCertificate()
+			require.NoError(t, err)
+
+			serverTLSConfig := &tls.Config{
+				Certificates: []tls.Certificate{*cert},
+				ClientAuth:   tls.RequestClientCert,
+			}
+
+			reply := func(msg string) tcp2.Handler {
+				return &tcp2.TLSHandler{
+					Next: tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+						_, _ = conn.Write([]byte(msg))
+						_ = conn.Close()
+					}),
+					Config: serverTLSConfig,
+				}
+			}
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+			require.NoError(t, router.muxerTCPTLS.AddRoute("HostSNI(`example.com`) && ClientCertPresent(`true`)", 2, reply("with certificate")))
+			require.NoError(t, router.muxerTCPTLS.AddRoute("HostSNI(`example.com`)", 1, reply("without certificate")))
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = listener.Close() })
+
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				router.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			clientTLSConfig := &tls.Config{ServerName: "example.com", InsecureSkipVerify: true}
+			if test.clientCert {
+				clientTLSConfig.Certificates = []tls.Certificate{*cert}
+			}
+
+			conn, err := tls.Dial("tcp", listener.Addr().String(), clientTLSConfig)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+			msg, err := io.ReadAll(conn)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(msg))
+		})
+	}
+}
+
+func TestRouter_ClientCertPresent_DifferentClientCAs(t *testing.T) {
+	cert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+
+	// The route matching the connections with a client certificate requires a certificate signed by another CA,
+	// whereas the handshake is done with the TLS config of the route matching the connections without one.
+	otherCert, err := generate.DefaultCertificate()
+	require.NoError(t, err)
+	otherCA, err := x509.ParseCertificate(otherCert.Certificate[0])
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(otherCA)
+
+	reply := func(msg string, config *tls.Config) tcp2.Handler {
+		return &tcp2.TLSHandler{
+			Next: tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+				_, _ = conn.Write([]byte(msg))
+				_ = conn.Close()
+			}),
+			Config: config,
+		}
+	}
+
+	router, err := NewRouter()
+	require.NoError(t, err)
+	require.NoError(t, router.muxerTCPTLS.AddRoute("HostSNI(`example.com`) && ClientCertPresent(`false`)", 2, reply("without certificate", &tls.Config{
+		Certificates: []tls.Certificate{*cert},
+		ClientAuth:   tls.RequestClientCert,
+	})))
+	require.NoError(t, router.muxerTCPTLS.AddRoute("HostSNI(`example.com`)", 1, reply("verified certificate", &tls.Config{
+		Certificates: []tls.Certificate{*cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	})))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		router.ServeTCP(conn.(*net.TCPConn))
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{
+		ServerName:         "example.com",
+		InsecureSkipVerify: true,
+		Certificates:       []tls.Certificate{*cert},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	// The client certificate has not been verified against the CAs of the matched route, the connection is closed.
+	msg, _ := io.ReadAll(conn)
+	assert.Empty(t, msg)
+}
+
 func NewMockConn() *MockConn {
 	return &MockConn{
 		dataRead:  make(chan []byte),
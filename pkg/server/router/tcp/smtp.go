@@ -0,0 +1,88 @@
+package tcp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// smtpNegotiator negotiates the SMTP STARTTLS session (RFC 3207), in which the server speaks first.
+type smtpNegotiator struct{}
+
+func (smtpNegotiator) serverFirst() bool {
+	return true
+}
+
+func (smtpNegotiator) detect(*bufio.Reader) (bool, error) {
+	return false, nil
+}
+
+// negotiate greets the client, and answers its commands until it issues the STARTTLS command.
+func (smtpNegotiator) negotiate(conn tcp.WriteCloser, br *bufio.Reader) (*startTLSBackend, error) {
+	if _, err := conn.Write([]byte("220 traefik ESMTP ready\r\n")); err != nil {
+		return nil, err
+	}
+
+	ehlo := "EHLO traefik"
+	for i := 0; i < maxStartTLSCommands; i++ {
+		line, err := readStartTLSLine(br)
+		if err != nil {
+			return nil, err
+		}
+
+		verb, _, _ := strings.Cut(line, " ")
+
+		var reply string
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			ehlo = line
+			reply = "250-traefik\r\n250 STARTTLS\r\n"
+		case "HELO":
+			reply = "250 traefik\r\n"
+		case "NOOP", "RSET":
+			reply = "250 OK\r\n"
+		case "STARTTLS":
+			if _, err := conn.Write([]byte("220 Ready to start TLS\r\n")); err != nil {
+				return nil, err
+			}
+
+			// The backend greeting has already been replaced by the one of Traefik.
+			greeting := startTLSExchange{response: smtpReply("220")}
+
+			return &startTLSBackend{
+				passthrough: []startTLSExchange{
+					greeting,
+					{request: []byte(ehlo + "\r\n"), response: smtpReply("250")},
+					{request: []byte("STARTTLS\r\n"), response: smtpReply("220")},
+				},
+				termination: []startTLSExchange{greeting},
+			}, nil
+		case "QUIT":
+			_, _ = conn.Write([]byte("221 Bye\r\n"))
+			return nil, errStartTLSClosed
+		default:
+			reply = "530 Must issue a STARTTLS command first\r\n"
+		}
+
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, errors.New("too many SMTP commands before STARTTLS")
+}
+
+// smtpReply returns the response checking that the backend replied with the given code.
+func smtpReply(code string) func(b []byte) (int, error) {
+	return lineResponse(func(line string) (bool, error) {
+		if !strings.HasPrefix(line, code) {
+			return false, fmt.Errorf("unexpected reply from SMTP server: %q", line)
+		}
+
+		// The lines of a multiline reply have a hyphen after the code, but the last one.
+		return len(line) == len(code) || line[len(code)] != '-', nil
+	})
+}
@@ -0,0 +1,369 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	tcpmuxer "github.com/traefik/traefik/v3/pkg/muxer/tcp"
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+// errStartTLSClosed is returned by a negotiation when the client ends the session before starting TLS.
+var errStartTLSClosed = errors.New("session closed by the client before STARTTLS")
+
+// startTLSNegotiators are the STARTTLS negotiators which can be enabled on an entry point, keyed by protocol.
+var startTLSNegotiators = map[string]startTLSNegotiator{
+	"postgres": postgresNegotiator{},
+	"smtp":     smtpNegotiator{},
+	"imap":     imapNegotiator{},
+	"pop3":     pop3Negotiator{},
+	"xmpp":     xmppNegotiator{},
+	"mysql":    mysqlNegotiator{},
+}
+
+// defaultStartTLSProtocols are the protocols whose STARTTLS negotiation is handled when none are configured.
+var defaultStartTLSProtocols = []string{"postgres"}
+
+// startTLSNegotiator negotiates TLS with STARTTLS on behalf of the servers of a protocol,
+// so that the connections can then be routed on their ClientHello, like any other TLS connection.
+type startTLSNegotiator interface {
+	// serverFirst reports whether the server speaks first in the protocol,
+	// in which case every connection of the entry point starts with the negotiation.
+	serverFirst() bool
+	// detect reports whether the client starts a STARTTLS negotiation of the protocol,
+	// for the protocols in which the client speaks first.
+	// It must peek the bytes one at a time, not to block on a client sending fewer bytes.
+	detect(br *bufio.Reader) (bool, error)
+	// negotiate negotiates STARTTLS with the client, until the client is about to send its ClientHello.
+	// It returns the exchanges to replay with the backend.
+	negotiate(conn tcp.WriteCloser, br *bufio.Reader) (*startTLSBackend, error)
+}
+
+// startTLSBackend describes how a backend has to be set up, after the client negotiated STARTTLS with Traefik.
+type startTLSBackend struct {
+	// passthrough are the exchanges negotiating STARTTLS with the backend, when the TLS session is passed through.
+	passthrough []startTLSExchange
+	// termination are the exchanges getting the backend to the state expected by the client,
+	// when the TLS session is terminated by Traefik.
+	termination []startTLSExchange
+	// noTermination is set when the protocol does not allow the TLS session to be terminated by Traefik.
+	noTermination bool
+}
+
+// startTLSExchange is a request sent to the backend, and the response expected in return.
+type startTLSExchange struct {
+	// request is sent to the backend, if not empty.
+	request []byte
+	// response returns the length of the complete response at the start of b, or zero if it is not complete yet.
+	// A nil response means that no response is expected.
+	response func(b []byte) (int, error)
+}
+
+// SetStartTLS sets the protocols whose STARTTLS negotiation is handled by the router.
+func (r *Router) SetStartTLS(protocols []string) error {
+	var serverFirst startTLSNegotiator
+	var clientFirst []startTLSNegotiator
+	for _, protocol := range protocols {
+		negotiator, ok := startTLSNegotiators[protocol]
+		if !ok {
+			return fmt.Errorf("unknown STARTTLS protocol: %s", protocol)
+		}
+
+		if !negotiator.serverFirst() {
+			clientFirst = append(clientFirst, negotiator)
+			continue
+		}
+
+		if len(protocols) > 1 {
+			return fmt.Errorf("STARTTLS protocol %s cannot be combined with other protocols, as its server speaks first", protocol)
+		}
+		serverFirst = negotiator
+	}
+
+	r.startTLSServerFirst = serverFirst
+	r.startTLSClientFirst = clientFirst
+	return nil
+}
+
+// startTLSNegotiator returns the negotiator of the STARTTLS session started by the connection, if any.
+func (r *Router) startTLSNegotiator(br *bufio.Reader) (startTLSNegotiator, error) {
+	if r.startTLSServerFirst != nil {
+		return r.startTLSServerFirst, nil
+	}
+
+	for _, negotiator := range r.startTLSClientFirst {
+		ok, err := negotiator.detect(br)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return negotiator, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// serveStartTLS serves a connection with a client negotiating a STARTTLS session.
+// It handles TCP TLS routing, after accepting to start the STARTTLS session.
+func (r *Router) serveStartTLS(conn tcp.WriteCloser, negotiator startTLSNegotiator) {
+	br := bufio.NewReader(conn)
+
+	backend, err := negotiator.negotiate(conn, br)
+	if err != nil {
+		if !errors.Is(err, errStartTLSClosed) {
+			log.Debug().Err(err).Msg("Error while negotiating STARTTLS")
+		}
+		conn.Close()
+		return
+	}
+
+	hello, err := clientHelloInfo(br)
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	if !hello.isTLS {
+		conn.Close()
+		return
+	}
+
+	connData, err := tcpmuxer.NewConnData(hello.serverName, conn, hello.protos)
+	if err != nil {
+		log.Error().Err(err).Msg("Error while reading TCP connection data")
+		conn.Close()
+		return
+	}
+
+	connData.SetTLSVersions(hello.versions)
+
+	// Contains also TCP TLS passthrough routes.
+	handlerTCPTLS, _ := r.muxerTCPTLS.Match(connData)
+	handlerTCPTLS = r.clientCertRouter(handlerTCPTLS, connData)
+	if handlerTCPTLS == nil {
+		conn.Close()
+		return
+	}
+
+	proxiedConn := r.GetConn(conn, hello.peeked)
+
+	// We are in TLS mode and if the handler is not TLSHandler, we are in passthrough.
+	tlsHandler, ok := handlerTCPTLS.(*tcp.TLSHandler)
+	if !ok {
+		handlerTCPTLS.ServeTCP(newStartTLSConn(proxiedConn, backend.passthrough))
+		return
+	}
+
+	if backend.noTermination {
+		log.Error().Msg("TLS termination is not supported for this STARTTLS protocol, the TLS session must be passed through")
+		conn.Close()
+		return
+	}
+
+	if len(backend.termination) > 0 {
+		next := tlsHandler.Next
+		tlsHandler = &tcp.TLSHandler{
+			Next: tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+				next.ServeTCP(newStartTLSConn(conn, backend.termination))
+			}),
+			Config: tlsHandler.Config,
+		}
+	}
+
+	tlsHandler.ServeTCP(proxiedConn)
+}
+
+// startTLSConn is a tcp.WriteCloser that replays exchanges with the backend,
+// before exchanging any data between the client and the backend.
+// As the conn is on the client side of the proxy,
+// the requests to the backend are returned by Read, and its responses are given to Write.
+// It enforces that the exchanges with the backend are successful.
+type startTLSConn struct {
+	tcp.WriteCloser
+
+	exchanges []startTLSExchange
+	// done receives the result of the response of each exchange, once received.
+	done []chan error
+
+	// read is the index of the exchange being replayed by Read.
+	read    int
+	pending []byte // the part of the request of the read exchange not returned yet.
+	sent    bool   // whether the request of the read exchange has been returned.
+
+	// written is the index of the exchange whose response is being received by Write.
+	written  int
+	received []byte // the part of the response of the written exchange received so far.
+	err      error
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newStartTLSConn(conn tcp.WriteCloser, exchanges []startTLSExchange) tcp.WriteCloser {
+	if len(exchanges) == 0 {
+		return conn
+	}
+
+	done := make([]chan error, len(exchanges))
+	for i := range done {
+		done[i] = make(chan error, 1)
+	}
+
+	return &startTLSConn{
+		WriteCloser: conn,
+		exchanges:   exchanges,
+		done:        done,
+		closed:      make(chan struct{}),
+	}
+}
+
+// Read returns the requests of the exchanges to the backend, waiting for the responses in between,
+// and then reads bytes from the underlying connection.
+// Read does not support concurrent calls.
+func (c *startTLSConn) Read(p []byte) (int, error) {
+	for c.read < len(c.exchanges) {
+		exchange := c.exchanges[c.read]
+
+		if !c.sent {
+			c.sent = true
+			c.pending = exchange.request
+		}
+
+		if len(c.pending) > 0 {
+			n := copy(p, c.pending)
+			c.pending = c.pending[n:]
+			return n, nil
+		}
+
+		if exchange.response != nil {
+			select {
+			case err := <-c.done[c.read]:
+				if err != nil {
+					return 0, err
+				}
+			case <-c.closed:
+				return 0, errors.New("connection closed during the STARTTLS negotiation with the backend")
+			}
+		}
+
+		c.read++
+		c.sent = false
+	}
+
+	return c.WriteCloser.Read(p)
+}
+
+// Write checks that the bytes written (the ones provided by the backend) are the expected responses,
+// and drops them, as the client has already received its own responses from Traefik.
+// Any following bytes are written to the underlying connection.
+// If a response is not the expected one, the error is returned by both Write and Read.
+// Write does not support concurrent calls.
+func (c *startTLSConn) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	if c.written == len(c.exchanges) {
+		return c.WriteCloser.Write(p)
+	}
+
+	c.received = append(c.received, p...)
+
+	for c.written < len(c.exchanges) {
+		response := c.exchanges[c.written].response
+		if response == nil {
+			c.written++
+			continue
+		}
+
+		n, err := response(c.received)
+		if err != nil {
+			c.err = err
+			c.done[c.written] <- err
+			return 0, err
+		}
+
+		if n == 0 {
+			return len(p), nil
+		}
+
+		c.received = c.received[n:]
+		c.done[c.written] <- nil
+		c.written++
+	}
+
+	if len(c.received) > 0 {
+		rest := c.received
+		c.received = nil
+		if _, err := c.WriteCloser.Write(rest); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// CloseWrite closes the write side of the underlying connection,
+// which also ends the replay of the exchanges, as no more responses are coming.
+func (c *startTLSConn) CloseWrite() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return c.WriteCloser.CloseWrite()
+}
+
+// Close closes the underlying connection.
+func (c *startTLSConn) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return c.WriteCloser.Close()
+}
+
+// NetConn returns the underlying connection.
+func (c *startTLSConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// maxStartTLSCommands is the maximum number of commands a client can send to Traefik before starting TLS.
+const maxStartTLSCommands = 16
+
+// readStartTLSLine reads a line of a text protocol, and returns it without its line ending.
+func readStartTLSLine(br *bufio.Reader) (string, error) {
+	line, err := br.ReadSlice('\n')
+	if err != nil {
+		if errors.Is(err, bufio.ErrBufferFull) {
+			return "", errors.New("line too long")
+		}
+		return "", err
+	}
+
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// lineResponse returns the response of a text protocol, which is complete once check reports the last line of the response.
+func lineResponse(check func(line string) (bool, error)) func(b []byte) (int, error) {
+	return func(b []byte) (int, error) {
+		var n int
+		for {
+			i := bytes.IndexByte(b[n:], '\n')
+			if i < 0 {
+				return 0, nil
+			}
+
+			line := strings.TrimRight(string(b[n:n+i]), "\r")
+			n += i + 1
+
+			done, err := check(line)
+			if err != nil {
+				return 0, err
+			}
+			if done {
+				return n, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,483 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tcp2 "github.com/traefik/traefik/v3/pkg/tcp"
+	"github.com/traefik/traefik/v3/pkg/tls/generate"
+)
+
+func TestRouter_SetStartTLS(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		protocols     []string
+		expectedError string
+	}{
+		{
+			desc:      "client first protocols",
+			protocols: []string{"postgres", "xmpp"},
+		},
+		{
+			desc:      "server first protocol",
+			protocols: []string{"smtp"},
+		},
+		{
+			desc:      "none",
+			protocols: []string{},
+		},
+		{
+			desc:          "unknown protocol",
+			protocols:     []string{"ftp"},
+			expectedError: "unknown STARTTLS protocol: ftp",
+		},
+		{
+			desc:          "server first protocol combined with other protocols",
+			protocols:     []string{"postgres", "imap"},
+			expectedError: "STARTTLS protocol imap cannot be combined with other protocols, as its server speaks first",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+
+			err = router.SetStartTLS(test.protocols)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// startTLSStep is a message sent by a peer, followed by the message it expects in return.
+type startTLSStep struct {
+	send   string
+	expect string
+}
+
+func TestStartTLSNegotiators(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		protocol string
+		// client are the steps of the client negotiating with Traefik.
+		client []startTLSStep
+		// backend are the steps of the backend negotiating with Traefik, when the TLS session is passed through.
+		backend []startTLSStep
+		// expectedError is the error of the negotiation with the backend.
+		expectedError string
+	}{
+		{
+			desc:     "postgres",
+			protocol: "postgres",
+			client: []startTLSStep{
+				{send: string(PostgresStartTLSMsg), expect: "S"},
+			},
+			backend: []startTLSStep{
+				{expect: string(PostgresStartTLSMsg)},
+				{send: "S"},
+			},
+		},
+		{
+			desc:     "smtp",
+			protocol: "smtp",
+			client: []startTLSStep{
+				{expect: "220 traefik ESMTP ready\r\n"},
+				{send: "EHLO client\r\n", expect: "250 STARTTLS\r\n"},
+				{send: "MAIL FROM:<foo@example.com>\r\n", expect: "530 Must issue a STARTTLS command first\r\n"},
+				{send: "STARTTLS\r\n", expect: "220 Ready to start TLS\r\n"},
+			},
+			backend: []startTLSStep{
+				{send: "220 backend\r\n", expect: "EHLO client\r\n"},
+				{send: "250-backend\r\n250-PIPELINING\r\n250 STARTTLS\r\n", expect: "STARTTLS\r\n"},
+				{send: "220 go ahead\r\n"},
+			},
+		},
+		{
+			desc:     "smtp without STARTTLS on the backend",
+			protocol: "smtp",
+			client: []startTLSStep{
+				{expect: "220 traefik ESMTP ready\r\n"},
+				{send: "EHLO client\r\n", expect: "250 STARTTLS\r\n"},
+				{send: "STARTTLS\r\n", expect: "220 Ready to start TLS\r\n"},
+			},
+			backend: []startTLSStep{
+				{send: "220 backend\r\n", expect: "EHLO client\r\n"},
+				{send: "250 backend\r\n", expect: "STARTTLS\r\n"},
+				{send: "502 not implemented\r\n"},
+			},
+			expectedError: `unexpected reply from SMTP server: "502 not implemented"`,
+		},
+		{
+			desc:     "imap",
+			protocol: "imap",
+			client: []startTLSStep{
+				{expect: "traefik ready\r\n"},
+				{send: "a1 CAPABILITY\r\n", expect: "a1 OK CAPABILITY completed\r\n"},
+				{send: "a2 STARTTLS\r\n", expect: "a2 OK Begin TLS negotiation now\r\n"},
+			},
+			backend: []startTLSStep{
+				{send: "* OK backend ready\r\n", expect: "a2 STARTTLS\r\n"},
+				{send: "* untagged\r\na2 OK go ahead\r\n"},
+			},
+		},
+		{
+			desc:     "pop3",
+			protocol: "pop3",
+			client: []startTLSStep{
+				{expect: "+OK traefik ready\r\n"},
+				{send: "CAPA\r\n", expect: ".\r\n"},
+				{send: "STLS\r\n", expect: "+OK Begin TLS negotiation\r\n"},
+			},
+			backend: []startTLSStep{
+				{send: "+OK backend ready\r\n", expect: "STLS\r\n"},
+				{send: "+OK go ahead\r\n"},
+			},
+		},
+		{
+			desc:     "xmpp",
+			protocol: "xmpp",
+			client: []startTLSStep{
+				{
+					send:   "<?xml version='1.0'?><stream:stream to='example.com' xmlns='jabber:client' xmlns:stream='http://etherx.jabber.org/streams' version='1.0'>",
+					expect: "</stream:features>",
+				},
+				{send: "<starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>", expect: "<proceed xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>"},
+			},
+			backend: []startTLSStep{
+				{expect: "<?xml version='1.0'?><stream:stream to='example.com' xmlns='jabber:client' xmlns:stream='http://etherx.jabber.org/streams' version='1.0'>"},
+				{
+					send:   "<?xml version='1.0'?><stream:stream from='example.com' id='1' version='1.0'><stream:features><starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'/></stream:features>",
+					expect: "<starttls xmlns='urn:ietf:params:xml:ns:xmpp-tls'/>",
+				},
+				{send: "<proceed xmlns='urn:ietf:params:xml:ns:xmpp-tls'></proceed>"},
+			},
+		},
+		{
+			desc:     "mysql",
+			protocol: "mysql",
+			client: []startTLSStep{
+				{expect: "mysql_native_password\x00"},
+				{send: string(mysqlSSLRequest())},
+			},
+			backend: []startTLSStep{
+				{send: string(mysqlGreetingPacket(t, "caching_sha2_password")), expect: string(mysqlSSLRequest())},
+			},
+		},
+		{
+			desc:     "mysql with mysql_native_password on the backend",
+			protocol: "mysql",
+			client: []startTLSStep{
+				{expect: "mysql_native_password\x00"},
+				{send: string(mysqlSSLRequest())},
+			},
+			backend: []startTLSStep{
+				{send: string(mysqlGreetingPacket(t, "mysql_native_password"))},
+			},
+			expectedError: "MySQL server authenticates with mysql_native_password, which is not supported with STARTTLS: " +
+				"the client authenticates on the scramble sent by Traefik, unless the server switches to another authentication method",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			negotiator := startTLSNegotiators[test.protocol]
+
+			clientConn, conn := net.Pipe()
+			t.Cleanup(func() { _ = clientConn.Close() })
+
+			negotiated := make(chan *startTLSBackend, 1)
+			go func() {
+				defer close(negotiated)
+
+				br := bufio.NewReader(conn)
+				if !negotiator.serverFirst() {
+					ok, err := negotiator.detect(br)
+					if err != nil || !ok {
+						return
+					}
+				}
+
+				backend, err := negotiator.negotiate(&pipeConn{Conn: conn}, br)
+				if err != nil {
+					return
+				}
+				negotiated <- backend
+			}()
+
+			runStartTLSSteps(t, clientConn, test.client)
+
+			backend := <-negotiated
+			require.NotNil(t, backend)
+
+			// Replays the negotiation with the backend, on the client side of a proxy.
+			proxyConn, clientSideConn := net.Pipe()
+			t.Cleanup(func() { _ = clientSideConn.Close() })
+			backendConn, proxyBackendConn := net.Pipe()
+			t.Cleanup(func() { _ = backendConn.Close() })
+
+			replay := newStartTLSConn(&pipeConn{Conn: proxyConn}, backend.passthrough)
+
+			errCh := make(chan error, 2)
+			go func() {
+				_, err := io.Copy(proxyBackendConn, replay)
+				errCh <- err
+			}()
+			go func() {
+				_, err := io.Copy(replay, proxyBackendConn)
+				errCh <- err
+			}()
+
+			if test.expectedError != "" {
+				for _, step := range test.backend {
+					_, err := backendConn.Write([]byte(step.send))
+					require.NoError(t, err)
+					if step.expect != "" {
+						expectStartTLS(t, backendConn, step.expect)
+					}
+				}
+
+				assert.EqualError(t, <-errCh, test.expectedError)
+				return
+			}
+
+			runStartTLSSteps(t, backendConn, test.backend)
+
+			// Once negotiated, the data flows between the client and the backend.
+			go func() { _, _ = clientSideConn.Write([]byte("client hello")) }()
+			expectStartTLS(t, backendConn, "client hello")
+
+			go func() { _, _ = backendConn.Write([]byte("server hello")) }()
+			expectStartTLS(t, clientSideConn, "server hello")
+		})
+	}
+}
+
+func TestStartTLS_SMTP(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		passthrough bool
+	}{
+		{
+			desc:        "TLS passthrough",
+			passthrough: true,
+		},
+		{
+			desc: "TLS termination",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cert, err := generate.DefaultCertificate()
+			require.NoError(t, err)
+			tlsConfig := &tls.Config{Certificates: []tls.Certificate{*cert}}
+
+			backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = backendListener.Close() })
+
+			quit := make(chan bool, 1)
+			go func() {
+				conn, err := backendListener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				var backendTLSConfig *tls.Config
+				if test.passthrough {
+					backendTLSConfig = tlsConfig
+				}
+				fakeSMTPServer(conn, backendTLSConfig, quit)
+			}()
+
+			var handler tcp2.Handler = tcp2.HandlerFunc(func(conn tcp2.WriteCloser) {
+				proxyTo(t, conn, backendListener.Addr().String())
+			})
+			if !test.passthrough {
+				handler = &tcp2.TLSHandler{Next: handler, Config: tlsConfig}
+			}
+
+			router, err := NewRouter()
+			require.NoError(t, err)
+			require.NoError(t, router.SetStartTLS([]string{"smtp"}))
+			require.NoError(t, router.muxerTCPTLS.AddRoute("HostSNI(`example.com`)", 0, handler))
+
+			routerListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = routerListener.Close() })
+
+			go func() {
+				conn, err := routerListener.Accept()
+				if err != nil {
+					return
+				}
+				router.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			client, err := smtp.Dial(routerListener.Addr().String())
+			require.NoError(t, err)
+
+			require.NoError(t, client.Hello("client"))
+			require.NoError(t, client.StartTLS(&tls.Config{ServerName: "example.com", InsecureSkipVerify: true}))
+			require.NoError(t, client.Noop())
+			require.NoError(t, client.Quit())
+
+			select {
+			case tlsQuit := <-quit:
+				// With TLS termination, the backend receives the plaintext session.
+				assert.Equal(t, test.passthrough, tlsQuit)
+			case <-time.After(5 * time.Second):
+				t.Fatal("session not ended on the backend")
+			}
+		})
+	}
+}
+
+// fakeSMTPServer serves an SMTP session, offering STARTTLS if a TLS config is given,
+// and reports whether the session ended with TLS.
+func fakeSMTPServer(conn net.Conn, tlsConfig *tls.Config, quit chan<- bool) {
+	_, _ = conn.Write([]byte("220 backend ESMTP\r\n"))
+
+	isTLS := false
+	br := bufio.NewReader(conn)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		command, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch command {
+		case "EHLO":
+			if tlsConfig != nil && !isTLS {
+				_, _ = conn.Write([]byte("250-backend\r\n250 STARTTLS\r\n"))
+				continue
+			}
+			_, _ = conn.Write([]byte("250 backend\r\n"))
+		case "STARTTLS":
+			_, _ = conn.Write([]byte("220 go ahead\r\n"))
+			conn = tls.Server(conn, tlsConfig)
+			br = bufio.NewReader(conn)
+			isTLS = true
+		case "NOOP":
+			_, _ = conn.Write([]byte("250 OK\r\n"))
+		case "QUIT":
+			_, _ = conn.Write([]byte("221 bye\r\n"))
+			quit <- isTLS
+			return
+		default:
+			_, _ = conn.Write([]byte("500 unknown command\r\n"))
+		}
+	}
+}
+
+// proxyTo forwards the connection to the given address.
+func proxyTo(t *testing.T, conn tcp2.WriteCloser, addr string) {
+	t.Helper()
+
+	defer conn.Close()
+
+	backend, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer backend.Close()
+
+	errCh := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(backend, conn)
+		errCh <- err
+	}()
+	go func() {
+		_, err := io.Copy(conn, backend)
+		errCh <- err
+	}()
+	<-errCh
+}
+
+// runStartTLSSteps sends the messages of the steps, and checks the messages received in return.
+func runStartTLSSteps(t *testing.T, conn net.Conn, steps []startTLSStep) {
+	t.Helper()
+
+	for _, step := range steps {
+		if step.send != "" {
+			go func(send string) { _, _ = conn.Write([]byte(send)) }(step.send)
+		}
+		if step.expect != "" {
+			expectStartTLS(t, conn, step.expect)
+		}
+	}
+}
+
+// expectStartTLS reads the connection until it has received a message ending with expected.
+func expectStartTLS(t *testing.T, conn net.Conn, expected string) {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	var received []byte
+	buf := make([]byte, 1024)
+	for !strings.HasSuffix(string(received), expected) {
+		n, err := conn.Read(buf)
+		require.NoError(t, err, "received %q, expected %q", received, expected)
+		received = append(received, buf[:n]...)
+	}
+}
+
+// pipeConn is a tcp.WriteCloser over a net.Pipe connection.
+type pipeConn struct {
+	net.Conn
+}
+
+func (c *pipeConn) CloseWrite() error {
+	return c.Close()
+}
+
+// mysqlSSLRequest returns an SSLRequest packet.
+func mysqlSSLRequest() []byte {
+	request := make([]byte, 4+mysqlSSLRequestLen)
+	request[0] = mysqlSSLRequestLen
+	request[3] = 1
+	request[4] = 0x00
+	request[5] = 0x0a // CLIENT_PROTOCOL_41 | CLIENT_SSL
+	return request
+}
+
+// mysqlGreetingPacket returns the greeting packet of a MySQL server allowing TLS,
+// and announcing the given authentication method.
+func mysqlGreetingPacket(t *testing.T, authPluginName string) []byte {
+	t.Helper()
+
+	greeting, err := mysqlGreeting()
+	require.NoError(t, err)
+
+	payload := bytes.TrimSuffix(greeting[4:], []byte(mysqlNativePassword+"\x00"))
+	payload = append(payload, authPluginName+"\x00"...)
+
+	header := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), 0}
+	return append(header, payload...)
+}
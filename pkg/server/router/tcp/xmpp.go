@@ -0,0 +1,152 @@
+package tcp
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/traefik/traefik/v3/pkg/tcp"
+)
+
+const (
+	xmppTLSNamespace = "urn:ietf:params:xml:ns:xmpp-tls"
+	// xmppMaxHeaderElements is the maximum number of elements before the stream header, e.g. the XML declaration.
+	xmppMaxHeaderElements = 4
+)
+
+var (
+	xmppStreamAttr = regexp.MustCompile(`\s(to|xmlns)=['"]([^'"]*)['"]`)
+
+	xmppStartTLS = []byte("<starttls xmlns='" + xmppTLSNamespace + "'/>")
+)
+
+// xmppNegotiator negotiates the XMPP STARTTLS session (RFC 6120), in which the client speaks first.
+type xmppNegotiator struct{}
+
+func (xmppNegotiator) serverFirst() bool {
+	return false
+}
+
+// detect determines whether the buffer starts with an XML declaration or an XMPP stream header.
+func (xmppNegotiator) detect(br *bufio.Reader) (bool, error) {
+	for _, prefix := range []string{"<?xml", "<stream:stream"} {
+		ok, err := peekPrefix(br, []byte(prefix))
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// negotiate opens the stream, offering STARTTLS as the only feature, and proceeds once the client asks for it.
+func (xmppNegotiator) negotiate(conn tcp.WriteCloser, br *bufio.Reader) (*startTLSBackend, error) {
+	var header []byte
+	for i := 0; !bytes.Contains(header, []byte("<stream:stream")); i++ {
+		if i == xmppMaxHeaderElements {
+			return nil, errors.New("XMPP stream header not found")
+		}
+
+		element, err := br.ReadSlice('>')
+		if err != nil {
+			return nil, err
+		}
+		header = append(header, element...)
+	}
+
+	attrs := map[string]string{"xmlns": "jabber:client"}
+	for _, match := range xmppStreamAttr.FindAllStringSubmatch(string(header), -1) {
+		attrs[match[1]] = match[2]
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	reply := fmt.Sprintf("<?xml version='1.0'?>"+
+		"<stream:stream xmlns='%s' xmlns:stream='http://etherx.jabber.org/streams' id='%s' from='%s' version='1.0'>"+
+		"<stream:features><starttls xmlns='%s'><required/></starttls></stream:features>",
+		attrs["xmlns"], hex.EncodeToString(id), attrs["to"], xmppTLSNamespace)
+	if _, err := conn.Write([]byte(reply)); err != nil {
+		return nil, err
+	}
+
+	element, err := br.ReadSlice('>')
+	if err != nil {
+		return nil, err
+	}
+
+	request := strings.TrimSpace(string(element))
+	switch {
+	case request == "</stream:stream>":
+		_, _ = conn.Write([]byte("</stream:stream>"))
+		return nil, errStartTLSClosed
+	case !strings.HasPrefix(request, "<starttls") || !strings.Contains(request, xmppTLSNamespace):
+		_, _ = conn.Write([]byte("<failure xmlns='" + xmppTLSNamespace + "'/></stream:stream>"))
+		return nil, fmt.Errorf("unexpected XMPP element before STARTTLS: %q", request)
+	case !strings.HasSuffix(request, "/>"):
+		// The element is not self-closing.
+		if _, err := br.ReadSlice('>'); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := conn.Write([]byte("<proceed xmlns='" + xmppTLSNamespace + "'/>")); err != nil {
+		return nil, err
+	}
+
+	return &startTLSBackend{
+		passthrough: []startTLSExchange{
+			{request: header, response: xmppFeatures},
+			{request: xmppStartTLS, response: xmppProceed},
+		},
+	}, nil
+}
+
+// xmppFeatures checks that the backend opened the stream, and returns once it has announced its features.
+func xmppFeatures(b []byte) (int, error) {
+	if bytes.Contains(b, []byte("<stream:error")) {
+		return 0, errors.New("stream error from XMPP server")
+	}
+
+	end := []byte("</stream:features>")
+	i := bytes.Index(b, end)
+	if i < 0 {
+		return 0, nil
+	}
+	return i + len(end), nil
+}
+
+// xmppProceed checks that the backend accepted to start the STARTTLS session.
+func xmppProceed(b []byte) (int, error) {
+	if bytes.Contains(b, []byte("<failure")) {
+		return 0, errors.New("STARTTLS refused by XMPP server")
+	}
+
+	i := bytes.Index(b, []byte("<proceed"))
+	if i < 0 {
+		return 0, nil
+	}
+
+	j := bytes.IndexByte(b[i:], '>')
+	if j < 0 {
+		return 0, nil
+	}
+	n := i + j + 1
+
+	if b[n-2] == '/' {
+		return n, nil
+	}
+
+	end := []byte("</proceed>")
+	k := bytes.Index(b[n:], end)
+	if k < 0 {
+		return 0, nil
+	}
+	return n + k + len(end), nil
+}
@@ -2,14 +2,20 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"github.com/traefik/traefik/v3/pkg/needleware"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/config/static"
+	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/metrics"
+	"github.com/traefik/traefik/v3/pkg/middlewares/tcp/tcpneedle"
+	"github.com/traefik/traefik/v3/pkg/needleware/client"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
 	tcpmiddleware "github.com/traefik/traefik/v3/pkg/server/middleware/tcp"
+	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/server/router"
 	tcprouter "github.com/traefik/traefik/v3/pkg/server/router/tcp"
 	udprouter "github.com/traefik/traefik/v3/pkg/server/router/udp"
@@ -26,6 +32,10 @@ type RouterFactory struct {
 	entryPointsTCP []string
 	entryPointsUDP []string
 
+	entryPointNeedles map[string]*static.EntryPointNeedle
+	// entryPointStartTLS are the STARTTLS protocols of the entry points which configure them.
+	entryPointStartTLS map[string][]string
+
 	managerFactory  *service.ManagerFactory
 	metricsRegistry metrics.Registry
 
@@ -36,6 +46,9 @@ type RouterFactory struct {
 
 	dialerManager *tcp.DialerManager
 
+	// needlewareManager is kept across configurations for the close events of the needles to survive reloads.
+	needlewareManager *needleware.Manager
+
 	cancelPrevState func()
 }
 
@@ -44,7 +57,17 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 	chainBuilder *middleware.ChainBuilder, pluginBuilder middleware.PluginsBuilder, metricsRegistry metrics.Registry, dialerManager *tcp.DialerManager,
 ) *RouterFactory {
 	var entryPointsTCP, entryPointsUDP []string
+	entryPointNeedles := map[string]*static.EntryPointNeedle{}
+	entryPointStartTLS := map[string][]string{}
 	for name, cfg := range staticConfiguration.EntryPoints {
+		if cfg.Needle != nil {
+			entryPointNeedles[name] = cfg.Needle
+		}
+
+		if cfg.StartTLS != nil {
+			entryPointStartTLS[name] = cfg.StartTLS
+		}
+
 		protocol, err := cfg.GetProtocol()
 		if err != nil {
 			// Should never happen because Traefik should not start if protocol is invalid.
@@ -59,17 +82,25 @@ func NewRouterFactory(staticConfiguration static.Configuration, managerFactory *
 	}
 
 	return &RouterFactory{
-		entryPointsTCP:  entryPointsTCP,
-		entryPointsUDP:  entryPointsUDP,
-		managerFactory:  managerFactory,
-		metricsRegistry: metricsRegistry,
-		tlsManager:      tlsManager,
-		chainBuilder:    chainBuilder,
-		pluginBuilder:   pluginBuilder,
-		dialerManager:   dialerManager,
+		entryPointsTCP:     entryPointsTCP,
+		entryPointsUDP:     entryPointsUDP,
+		entryPointNeedles:  entryPointNeedles,
+		entryPointStartTLS: entryPointStartTLS,
+		managerFactory:     managerFactory,
+		metricsRegistry:    metricsRegistry,
+		tlsManager:         tlsManager,
+		chainBuilder:       chainBuilder,
+		pluginBuilder:      pluginBuilder,
+		dialerManager:      dialerManager,
+		needlewareManager:  needleware.NewManager(metricsRegistry),
 	}
 }
 
+// NeedlewareManager returns the manager of the needles, which outlives the configurations.
+func (f *RouterFactory) NeedlewareManager() *needleware.Manager {
+	return f.needlewareManager
+}
+
 // CreateRouters creates new TCPRouters and UDPRouters.
 func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string]*tcprouter.Router, map[string]udp.Handler) {
 	if f.cancelPrevState != nil {
@@ -92,23 +123,124 @@ func (f *RouterFactory) CreateRouters(rtConf *runtime.Configuration) (map[string
 	serviceManager.LaunchHealthCheck(ctx)
 
 	// Needles
-	needlewareManager := needleware.NewManager()
-	needlewareManager.BuildNeedles(ctx, rtConf)
+	qualifyFallbackNeedles(ctx, rtConf)
+	f.needlewareManager.BuildNeedles(ctx, rtConf)
 
 	// TCP
-	svcTCPManager := tcpsvc.NewManager(rtConf, f.dialerManager)
+	svcTCPManager := tcpsvc.NewManager(rtConf, f.dialerManager, f.metricsRegistry)
 
-	middlewaresTCPBuilder := tcpmiddleware.NewBuilder(rtConf.TCPMiddlewares, needlewareManager)
+	middlewaresTCPBuilder := tcpmiddleware.NewBuilder(rtConf.TCPMiddlewares, f.needlewareManager)
 
 	rtTCPManager := tcprouter.NewManager(rtConf, svcTCPManager, middlewaresTCPBuilder, handlersNonTLS, handlersTLS, f.tlsManager)
 	routersTCP := rtTCPManager.BuildHandlers(ctx, f.entryPointsTCP)
+	f.applyEntryPointStartTLS(ctx, routersTCP)
+
+	svcTCPManager.LaunchHealthCheck(ctx)
 
 	// UDP
-	svcUDPManager := udpsvc.NewManager(rtConf, needlewareManager)
+	svcUDPManager := udpsvc.NewManager(rtConf, f.needlewareManager)
 	rtUDPManager := udprouter.NewManager(rtConf, svcUDPManager)
 	routersUDP := rtUDPManager.BuildHandlers(ctx, f.entryPointsUDP)
 
+	f.applyEntryPointNeedles(ctx, rtConf, routersTCP, routersUDP)
+
 	rtConf.PopulateUsedBy()
 
 	return routersTCP, routersUDP
 }
+
+// qualifyFallbackNeedles qualifies the fallback needle names with the provider of the needles referring to them.
+func qualifyFallbackNeedles(ctx context.Context, rtConf *runtime.Configuration) {
+	for name, conf := range rtConf.Needles {
+		if conf.Needle == nil || conf.Decision == nil || conf.Decision.FallbackNeedle == "" {
+			continue
+		}
+
+		conf.Decision.FallbackNeedle = provider.GetQualifiedName(provider.AddInContext(ctx, name), conf.Decision.FallbackNeedle)
+	}
+}
+
+// applyEntryPointStartTLS sets the STARTTLS protocols handled by the routers of the entry points.
+func (f *RouterFactory) applyEntryPointStartTLS(ctx context.Context, routersTCP map[string]*tcprouter.Router) {
+	for epName, protocols := range f.entryPointStartTLS {
+		rt, ok := routersTCP[epName]
+		if !ok {
+			continue
+		}
+
+		if err := rt.SetStartTLS(protocols); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str(logs.EntryPointName, epName).Msg("Cannot set the STARTTLS protocols of the entry point")
+		}
+	}
+}
+
+// applyEntryPointNeedles puts the needles of the entry points in front of their routers.
+// The connections of an entry point whose needle cannot be set are rejected,
+// and the error is reported on the routers of the entry point.
+func (f *RouterFactory) applyEntryPointNeedles(ctx context.Context, rtConf *runtime.Configuration, routersTCP map[string]*tcprouter.Router, routersUDP map[string]udp.Handler) {
+	for epName, cfg := range f.entryPointNeedles {
+		logger := log.Ctx(ctx).With().Str(logs.EntryPointName, epName).Str(logs.NeedleName, cfg.ID).Logger()
+
+		needle, err := f.needlewareManager.GetNeedle(cfg.ID, cfg.Metadata)
+		if err != nil {
+			logger.Error().Err(err).Msg("Cannot get the needle of the entry point, connections are rejected")
+			rejectEntryPointConnections(rtConf, epName, fmt.Errorf("cannot get the needle %s of the entry point: %w", cfg.ID, err), routersTCP, routersUDP)
+			continue
+		}
+
+		if rt, ok := routersTCP[epName]; ok {
+			epCtx := needleware.AddRouteInContext(logger.WithContext(ctx), client.Route{EntryPoint: epName})
+			err := rt.SetNeedle(func(next tcp.Handler) (tcp.Handler, error) {
+				return tcpneedle.New(epCtx, next, dynamic.TCPNeedle{Id: cfg.ID, Metadata: cfg.Metadata}, needle, epName)
+			})
+			if err != nil {
+				logger.Error().Err(err).Msg("Cannot set the needle of the entry point, connections are rejected")
+				rejectEntryPointConnections(rtConf, epName, fmt.Errorf("cannot set the needle %s of the entry point: %w", cfg.ID, err), routersTCP, routersUDP)
+				continue
+			}
+		}
+
+		if handler, ok := routersUDP[epName]; ok {
+			routersUDP[epName] = udp.NewNeedleHandler(handler, needle)
+		}
+	}
+}
+
+// rejectEntryPointConnections makes the routers of the entry point reject all the connections,
+// and reports the error on the routers of the entry point.
+func rejectEntryPointConnections(rtConf *runtime.Configuration, epName string, err error, routersTCP map[string]*tcprouter.Router, routersUDP map[string]udp.Handler) {
+	if rt, ok := routersTCP[epName]; ok {
+		_ = rt.SetNeedle(func(tcp.Handler) (tcp.Handler, error) {
+			return tcp.HandlerFunc(func(conn tcp.WriteCloser) { _ = conn.Close() }), nil
+		})
+	}
+
+	if _, ok := routersUDP[epName]; ok {
+		routersUDP[epName] = udp.HandlerFunc(func(conn *udp.Conn) { _ = conn.Close() })
+	}
+
+	for _, rt := range rtConf.Routers {
+		if hasEntryPoint(rt.EntryPoints, epName) {
+			rt.AddError(err, true)
+		}
+	}
+	for _, rt := range rtConf.TCPRouters {
+		if hasEntryPoint(rt.EntryPoints, epName) {
+			rt.AddError(err, true)
+		}
+	}
+	for _, rt := range rtConf.UDPRouters {
+		if hasEntryPoint(rt.EntryPoints, epName) {
+			rt.AddError(err, true)
+		}
+	}
+}
+
+func hasEntryPoint(entryPoints []string, epName string) bool {
+	for _, name := range entryPoints {
+		if name == epName {
+			return true
+		}
+	}
+	return false
+}
@@ -1,11 +1,15 @@
 package server
 
 import (
+	"context"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/config/static"
@@ -250,3 +254,77 @@ func TestInternalServices(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, responseRecorderOk.Result().StatusCode, "status code")
 }
+
+func TestCreateRouters_MissingEntryPointNeedle(t *testing.T) {
+	staticConfig := static.Configuration{
+		EntryPoints: map[string]*static.EntryPoint{
+			"web": {Needle: &static.EntryPointNeedle{ID: "missing@file"}},
+		},
+	}
+
+	dynamicConfigs := th.BuildConfiguration(
+		th.WithRouters(
+			th.WithRouter("foo",
+				th.WithEntryPoints("web"),
+				th.WithServiceName("bar"),
+				th.WithRule("Path(`/ok`)")),
+		),
+		th.WithLoadBalancerServices(th.WithService("bar",
+			th.WithServers(th.WithServer("http://127.0.0.1:80"))),
+		),
+	)
+
+	roundTripperManager := service.NewRoundTripperManager(nil)
+	roundTripperManager.Update(map[string]*dynamic.ServersTransport{"default@internal": {}})
+	managerFactory := service.NewManagerFactory(staticConfig, nil, metrics.NewVoidRegistry(), roundTripperManager, nil)
+
+	dialerManager := tcp.NewDialerManager(nil)
+	dialerManager.Update(map[string]*dynamic.TCPServersTransport{"default@internal": {}})
+	factory := NewRouterFactory(staticConfig, managerFactory, tls.NewManager(), middleware.NewChainBuilder(nil, nil, nil), nil, metrics.NewVoidRegistry(), dialerManager)
+
+	rtConf := runtime.NewConfig(dynamic.Configuration{HTTP: dynamicConfigs})
+	entryPointsHandlers, _ := factory.CreateRouters(rtConf)
+
+	// The error is reported on the routers of the entry point.
+	assert.Equal(t, runtime.StatusDisabled, rtConf.Routers["foo"].Status)
+	assert.NotEmpty(t, rtConf.Routers["foo"].Err)
+
+	// The connections of the entry point are rejected.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = clientConn.Close() })
+
+	serverConn, err := listener.Accept()
+	require.NoError(t, err)
+
+	entryPointsHandlers["web"].ServeTCP(serverConn.(*net.TCPConn))
+
+	_, err = clientConn.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestQualifyFallbackNeedles(t *testing.T) {
+	needle := func(fallback string) *runtime.NeedleInfo {
+		return &runtime.NeedleInfo{Needle: &dynamic.Needle{
+			Decision: &dynamic.NeedleDecision{FallbackNeedle: fallback},
+		}}
+	}
+
+	rtConf := &runtime.Configuration{Needles: map[string]*runtime.NeedleInfo{
+		"foo@file":   needle("bar"),
+		"bar@file":   needle(""),
+		"baz@docker": needle("bar@file"),
+		"qux@docker": {Needle: &dynamic.Needle{}},
+	}}
+
+	qualifyFallbackNeedles(context.Background(), rtConf)
+
+	assert.Equal(t, "bar@file", rtConf.Needles["foo@file"].Decision.FallbackNeedle)
+	assert.Empty(t, rtConf.Needles["bar@file"].Decision.FallbackNeedle)
+	assert.Equal(t, "bar@file", rtConf.Needles["baz@docker"].Decision.FallbackNeedle)
+	assert.Nil(t, rtConf.Needles["qux@docker"].Decision)
+}
@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/static"
 	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/middlewares/accesslog"
+	"github.com/traefik/traefik/v3/pkg/needleware"
 	"github.com/traefik/traefik/v3/pkg/safe"
 	"github.com/traefik/traefik/v3/pkg/server/middleware"
 )
@@ -20,6 +22,7 @@ type Server struct {
 	tcpEntryPoints TCPEntryPoints
 	udpEntryPoints UDPEntryPoints
 	chainBuilder   *middleware.ChainBuilder
+	needles        *needleware.Manager
 
 	accessLoggerMiddleware *accesslog.Handler
 
@@ -31,12 +34,13 @@ type Server struct {
 
 // NewServer returns an initialized Server.
 func NewServer(routinesPool *safe.Pool, entryPoints TCPEntryPoints, entryPointsUDP UDPEntryPoints, watcher *ConfigurationWatcher,
-	chainBuilder *middleware.ChainBuilder, accessLoggerMiddleware *accesslog.Handler,
+	chainBuilder *middleware.ChainBuilder, accessLoggerMiddleware *accesslog.Handler, needles *needleware.Manager,
 ) *Server {
 	srv := &Server{
 		watcher:                watcher,
 		tcpEntryPoints:         entryPoints,
 		chainBuilder:           chainBuilder,
+		needles:                needles,
 		accessLoggerMiddleware: accessLoggerMiddleware,
 		signals:                make(chan os.Signal, 1),
 		stopChan:               make(chan bool, 1),
@@ -76,12 +80,53 @@ func (s *Server) Stop() {
 	//nolint:zerologlint // false-positive https://github.com/ykadowak/zerologlint/issues/3
 	defer log.Info().Msg("Server stopped")
 
+	requestAcceptGraceTimeout, graceTimeOut := s.entryPointsLifeCycle()
+
+	// The entry points and the needles share a single shutdown deadline,
+	// so that stopping the server never takes longer than the entry points life cycle.
+	ctx, cancel := context.WithTimeout(context.Background(), requestAcceptGraceTimeout+graceTimeOut)
+	defer cancel()
+
+	// The pending decisions are canceled once the entry points stop accepting connections.
+	cancelDecisions := time.AfterFunc(requestAcceptGraceTimeout, s.needles.CancelDecisions)
+
 	s.tcpEntryPoints.Stop()
 	s.udpEntryPoints.Stop()
 
+	cancelDecisions.Stop()
+
+	// The connections are closed by now, their close events are given what is left of the deadline to be delivered.
+	s.needles.Shutdown(ctx)
+
 	s.stopChan <- true
 }
 
+// entryPointsLifeCycle returns the longest request accept grace timeout and grace timeout of the entry points.
+func (s *Server) entryPointsLifeCycle() (time.Duration, time.Duration) {
+	var requestAcceptGraceTimeout, graceTimeOut time.Duration
+
+	update := func(transport *static.EntryPointsTransport) {
+		if transport == nil || transport.LifeCycle == nil {
+			return
+		}
+		if d := time.Duration(transport.LifeCycle.RequestAcceptGraceTimeout); d > requestAcceptGraceTimeout {
+			requestAcceptGraceTimeout = d
+		}
+		if d := time.Duration(transport.LifeCycle.GraceTimeOut); d > graceTimeOut {
+			graceTimeOut = d
+		}
+	}
+
+	for _, ep := range s.tcpEntryPoints {
+		update(ep.transportConfiguration)
+	}
+	for _, ep := range s.udpEntryPoints {
+		update(ep.transportConfiguration)
+	}
+
+	return requestAcceptGraceTimeout, graceTimeOut
+}
+
 // Close destroys the server.
 func (s *Server) Close() {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -11,6 +11,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -224,24 +225,26 @@ func (e *TCPEntryPoint) Start(ctx context.Context) {
 		}
 
 		safe.Go(func() {
+			conn := newTrackedConnection(writeCloser, e.tracker)
+
 			// Enforce read/write deadlines at the connection level,
 			// because when we're peeking the first byte to determine whether we are doing TLS,
 			// the deadlines at the server level are not taken into account.
 			if e.transportConfiguration.RespondingTimeouts.ReadTimeout > 0 {
-				err := writeCloser.SetReadDeadline(time.Now().Add(time.Duration(e.transportConfiguration.RespondingTimeouts.ReadTimeout)))
+				err := conn.SetReadDeadline(time.Now().Add(time.Duration(e.transportConfiguration.RespondingTimeouts.ReadTimeout)))
 				if err != nil {
 					logger.Error().Err(err).Msg("Error while setting read deadline")
 				}
 			}
 
 			if e.transportConfiguration.RespondingTimeouts.WriteTimeout > 0 {
-				err = writeCloser.SetWriteDeadline(time.Now().Add(time.Duration(e.transportConfiguration.RespondingTimeouts.WriteTimeout)))
+				err := conn.SetWriteDeadline(time.Now().Add(time.Duration(e.transportConfiguration.RespondingTimeouts.WriteTimeout)))
 				if err != nil {
 					logger.Error().Err(err).Msg("Error while setting write deadline")
 				}
 			}
 
-			e.switcher.ServeTCP(newTrackedConnection(writeCloser, e.tracker))
+			e.switcher.ServeTCP(conn)
 		})
 	}
 }
@@ -355,6 +358,11 @@ func (c *writeCloserWrapper) CloseWrite() error {
 	return c.writeCloser.CloseWrite()
 }
 
+// NetConn returns the wrapped connection.
+func (c *writeCloserWrapper) NetConn() net.Conn {
+	return c.Conn
+}
+
 // writeCloser returns the given connection, augmented with the WriteCloser
 // implementation, if any was found within the underlying conn.
 func writeCloser(conn net.Conn) (tcp.WriteCloser, error) {
@@ -430,22 +438,131 @@ func buildProxyProtocolListener(ctx context.Context, entryPoint *static.EntryPoi
 }
 
 func buildListener(ctx context.Context, entryPoint *static.EntryPoint) (net.Listener, error) {
-	listener, err := net.Listen("tcp", entryPoint.GetAddress())
+	addresses, err := entryPoint.GetAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := make([]net.Listener, 0, len(addresses))
+	for _, address := range addresses {
+		listener, err := buildAddressListener(ctx, entryPoint, address)
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			return nil, err
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	if len(listeners) == 1 {
+		return listeners[0], nil
+	}
+
+	return newMultiListener(listeners), nil
+}
+
+func buildAddressListener(ctx context.Context, entryPoint *static.EntryPoint, address string) (net.Listener, error) {
+	tcpListener, err := net.Listen("tcp", address)
 	if err != nil {
 		return nil, fmt.Errorf("error opening listener: %w", err)
 	}
 
-	listener = tcpKeepAliveListener{listener.(*net.TCPListener)}
+	var listener net.Listener = tcpKeepAliveListener{tcpListener.(*net.TCPListener)}
 
 	if entryPoint.ProxyProtocol != nil {
 		listener, err = buildProxyProtocolListener(ctx, entryPoint, listener)
 		if err != nil {
+			_ = tcpListener.Close()
 			return nil, fmt.Errorf("error creating proxy protocol listener: %w", err)
 		}
 	}
 	return listener, nil
 }
 
+// multiListener is a listener accepting the connections of several listeners,
+// for an entry point listening on several addresses.
+type multiListener struct {
+	listeners []net.Listener
+	accepted  chan acceptedConn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// acceptedConn is the result of an Accept call on one of the listeners of a multiListener.
+type acceptedConn struct {
+	conn net.Conn
+	err  error
+}
+
+func newMultiListener(listeners []net.Listener) *multiListener {
+	l := &multiListener{
+		listeners: listeners,
+		accepted:  make(chan acceptedConn),
+		closed:    make(chan struct{}),
+	}
+
+	for _, listener := range listeners {
+		go l.acceptLoop(listener)
+	}
+
+	return l
+}
+
+// acceptLoop accepts the connections of the given listener, until it fails permanently or the multiListener is closed.
+func (l *multiListener) acceptLoop(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+
+		select {
+		case l.accepted <- acceptedConn{conn: conn, err: err}:
+		case <-l.closed:
+			if conn != nil {
+				_ = conn.Close()
+			}
+			return
+		}
+
+		var opErr *net.OpError
+		if err != nil && (!errors.As(err, &opErr) || !opErr.Temporary()) {
+			return
+		}
+	}
+}
+
+// Accept waits for and returns the next connection accepted by any of the listeners.
+func (l *multiListener) Accept() (net.Conn, error) {
+	select {
+	case accepted := <-l.accepted:
+		return accepted.conn, accepted.err
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close closes all the listeners.
+func (l *multiListener) Close() error {
+	var errs []error
+	l.closeOnce.Do(func() {
+		close(l.closed)
+
+		for _, listener := range l.listeners {
+			if err := listener.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+// Addr returns the address of the first listener.
+func (l *multiListener) Addr() net.Addr {
+	return l.listeners[0].Addr()
+}
+
 func newConnectionTracker(openConnectionsGauge gokitmetrics.Gauge) *connectionTracker {
 	return &connectionTracker{
 		conns:                make(map[net.Conn]struct{}),
@@ -614,6 +731,27 @@ func newTrackedConnection(conn tcp.WriteCloser, tracker *connectionTracker) *tra
 type trackedConnection struct {
 	tracker *connectionTracker
 	tcp.WriteCloser
+
+	// readDeadline is the read deadline last set on the connection, as a time.Time.
+	readDeadline atomic.Value
+}
+
+// SetDeadline sets the read and write deadlines of the connection.
+func (t *trackedConnection) SetDeadline(deadline time.Time) error {
+	t.readDeadline.Store(deadline)
+	return t.WriteCloser.SetDeadline(deadline)
+}
+
+// SetReadDeadline sets the read deadline of the connection.
+func (t *trackedConnection) SetReadDeadline(deadline time.Time) error {
+	t.readDeadline.Store(deadline)
+	return t.WriteCloser.SetReadDeadline(deadline)
+}
+
+// ReadDeadline returns the read deadline last set on the connection, the zero value meaning no deadline.
+func (t *trackedConnection) ReadDeadline() time.Time {
+	deadline, _ := t.readDeadline.Load().(time.Time)
+	return deadline
 }
 
 func (t *trackedConnection) Close() error {
@@ -621,6 +759,11 @@ func (t *trackedConnection) Close() error {
 	return t.WriteCloser.Close()
 }
 
+// NetConn returns the tracked connection.
+func (t *trackedConnection) NetConn() net.Conn {
+	return t.WriteCloser
+}
+
 // This function is inspired by http.AllowQuerySemicolons.
 func encodeQuerySemicolons(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
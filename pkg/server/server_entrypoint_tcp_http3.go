@@ -33,7 +33,16 @@ func newHTTP3Server(ctx context.Context, configuration *static.EntryPoint, https
 		return nil, errors.New("advertised port must be greater than or equal to zero")
 	}
 
-	conn, err := net.ListenPacket("udp", configuration.GetAddress())
+	addresses, err := configuration.GetAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addresses) > 1 {
+		return nil, errors.New("HTTP/3 is not supported on an entry point listening on several addresses")
+	}
+
+	conn, err := net.ListenPacket("udp", addresses[0])
 	if err != nil {
 		return nil, fmt.Errorf("starting listener: %w", err)
 	}
@@ -46,7 +55,7 @@ func newHTTP3Server(ctx context.Context, configuration *static.EntryPoint, https
 	}
 
 	h3.Server = &http3.Server{
-		Addr:      configuration.GetAddress(),
+		Addr:      addresses[0],
 		Port:      configuration.HTTP3.AdvertisedPort,
 		Handler:   httpsServer.Server.(*http.Server).Handler,
 		TLSConfig: &tls.Config{GetConfigForClient: h3.getGetConfigForClient},
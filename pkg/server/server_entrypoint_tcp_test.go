@@ -154,6 +154,50 @@ func startEntrypoint(entryPoint *TCPEntryPoint, router *tcprouter.Router) (net.C
 	return nil, errors.New("entry point never started")
 }
 
+func TestTCPEntryPoint_severalAddresses(t *testing.T) {
+	epConfig := &static.EntryPointsTransport{}
+	epConfig.SetDefaults()
+
+	entryPoint, err := NewTCPEntryPoint(context.Background(), &static.EntryPoint{
+		Address:          "127.0.0.1:0,127.0.0.1:0",
+		Transport:        epConfig,
+		ForwardedHeaders: &static.ForwardedHeaders{},
+		HTTP2:            &static.HTTP2Config{},
+	}, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { entryPoint.Shutdown(context.Background()) })
+
+	listener, ok := entryPoint.listener.(*multiListener)
+	require.True(t, ok)
+	require.Len(t, listener.listeners, 2)
+
+	router, err := tcprouter.NewRouter()
+	require.NoError(t, err)
+
+	// Replies with the port on which the connection was accepted.
+	err = router.AddRoute("HostSNI(`*`)", 0, tcp.HandlerFunc(func(conn tcp.WriteCloser) {
+		_, _ = conn.Write([]byte(conn.LocalAddr().String()))
+		_ = conn.Close()
+	}))
+	require.NoError(t, err)
+
+	go entryPoint.Start(context.Background())
+	entryPoint.SwitchRouter(router)
+
+	for _, l := range listener.listeners {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		require.NoError(t, err)
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+		localAddr, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		assert.Equal(t, l.Addr().String(), string(localAddr))
+
+		_ = conn.Close()
+	}
+}
+
 func TestReadTimeoutWithoutFirstByte(t *testing.T) {
 	epConfig := &static.EntryPointsTransport{}
 	epConfig.SetDefaults()
@@ -80,41 +80,71 @@ func (eps UDPEntryPoints) Switch(handlers map[string]udp.Handler) {
 
 // UDPEntryPoint is an entry point where we listen for UDP packets.
 type UDPEntryPoint struct {
-	listener               *udp.Listener
+	// listeners has one listener per address of the entry point.
+	listeners              []*udp.Listener
 	switcher               *udp.HandlerSwitcher
 	transportConfiguration *static.EntryPointsTransport
 }
 
 // NewUDPEntryPoint returns a UDP entry point.
 func NewUDPEntryPoint(cfg *static.EntryPoint) (*UDPEntryPoint, error) {
-	addr, err := net.ResolveUDPAddr("udp", cfg.GetAddress())
+	addresses, err := cfg.GetAddresses()
 	if err != nil {
 		return nil, err
 	}
 
-	listener, err := udp.Listen("udp", addr, time.Duration(cfg.UDP.Timeout))
+	listeners := make([]*udp.Listener, 0, len(addresses))
+	for _, address := range addresses {
+		listener, err := listenUDP(address, time.Duration(cfg.UDP.Timeout))
+		if err != nil {
+			for _, l := range listeners {
+				_ = l.Close()
+			}
+			return nil, err
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	return &UDPEntryPoint{listeners: listeners, switcher: &udp.HandlerSwitcher{}, transportConfiguration: cfg.Transport}, nil
+}
+
+func listenUDP(address string, timeout time.Duration) (*udp.Listener, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
 	if err != nil {
 		return nil, err
 	}
 
-	return &UDPEntryPoint{listener: listener, switcher: &udp.HandlerSwitcher{}, transportConfiguration: cfg.Transport}, nil
+	return udp.Listen("udp", addr, timeout)
 }
 
 // Start commences the listening for ep.
 func (ep *UDPEntryPoint) Start(ctx context.Context) {
 	log.Ctx(ctx).Debug().Msg("Start UDP Server")
-	for {
-		conn, err := ep.listener.Accept()
-		if err != nil {
-			// Only errClosedListener can happen that's why we return
-			return
-		}
 
-		go ep.switcher.ServeUDP(conn)
+	var wg sync.WaitGroup
+	for _, listener := range ep.listeners {
+		wg.Add(1)
+
+		go func(listener *udp.Listener) {
+			defer wg.Done()
+
+			for {
+				conn, err := listener.Accept()
+				if err != nil {
+					// Only errClosedListener can happen that's why we return
+					return
+				}
+
+				go ep.switcher.ServeUDP(conn)
+			}
+		}(listener)
 	}
+
+	wg.Wait()
 }
 
-// Shutdown closes ep's listener. It eventually closes all "sessions" and
+// Shutdown closes ep's listeners. It eventually closes all "sessions" and
 // releases associated resources, but only after it has waited for a graceTimeout,
 // if any was configured.
 func (ep *UDPEntryPoint) Shutdown(ctx context.Context) {
@@ -127,9 +157,21 @@ func (ep *UDPEntryPoint) Shutdown(ctx context.Context) {
 	}
 
 	graceTimeOut := time.Duration(ep.transportConfiguration.LifeCycle.GraceTimeOut)
-	if err := ep.listener.Shutdown(graceTimeOut); err != nil {
-		logger.Error().Err(err).Send()
+
+	var wg sync.WaitGroup
+	for _, listener := range ep.listeners {
+		wg.Add(1)
+
+		go func(listener *udp.Listener) {
+			defer wg.Done()
+
+			if err := listener.Shutdown(graceTimeOut); err != nil {
+				logger.Error().Err(err).Send()
+			}
+		}(listener)
 	}
+
+	wg.Wait()
 }
 
 // Switch replaces ep's handler with the one given as argument.
@@ -48,7 +48,7 @@ func TestShutdownUDPConn(t *testing.T) {
 		}
 	}))
 
-	conn, err := net.Dial("udp", entryPoint.listener.Addr().String())
+	conn, err := net.Dial("udp", entryPoint.listeners[0].Addr().String())
 	require.NoError(t, err)
 
 	// Start sending packets, to create a "session" with the server.
@@ -64,7 +64,7 @@ func TestShutdownUDPConn(t *testing.T) {
 	requireEcho(t, "TEST2", conn, time.Second)
 
 	// And make sure that on the other hand, opening new sessions is not possible anymore.
-	conn2, err := net.Dial("udp", entryPoint.listener.Addr().String())
+	conn2, err := net.Dial("udp", entryPoint.listeners[0].Addr().String())
 	require.NoError(t, err)
 
 	_, err = conn2.Write([]byte("TEST"))
@@ -104,6 +104,47 @@ func TestShutdownUDPConn(t *testing.T) {
 	}
 }
 
+func TestUDPEntryPoint_severalAddresses(t *testing.T) {
+	ep := static.EntryPoint{
+		Address: "127.0.0.1:0,127.0.0.1:0/udp",
+	}
+	ep.SetDefaults()
+
+	entryPoint, err := NewUDPEntryPoint(&ep)
+	require.NoError(t, err)
+	require.Len(t, entryPoint.listeners, 2)
+	t.Cleanup(func() { entryPoint.Shutdown(context.Background()) })
+
+	go entryPoint.Start(context.Background())
+	// Replies with the address of the listener which received the packet.
+	entryPoint.Switch(udp.HandlerFunc(func(conn *udp.Conn) {
+		defer conn.Close()
+
+		b := make([]byte, 1024)
+		if _, err := conn.Read(b); err != nil {
+			return
+		}
+		_, _ = conn.Write([]byte(conn.LocalAddr().String()))
+	}))
+
+	for _, listener := range entryPoint.listeners {
+		conn, err := net.Dial("udp", listener.Addr().String())
+		require.NoError(t, err)
+
+		_, err = conn.Write([]byte("TEST"))
+		require.NoError(t, err)
+
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+		b := make([]byte, 1024)
+		n, err := conn.Read(b)
+		require.NoError(t, err)
+		require.Equal(t, listener.Addr().String(), string(b[:n]))
+
+		_ = conn.Close()
+	}
+}
+
 // requireEcho tests that conn session is live and functional,
 // by writing data through it,
 // and expecting the same data as a response when reading on it.
@@ -6,28 +6,38 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/balancer"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/healthcheck"
 	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 )
 
 // Manager is the TCPHandlers factory.
 type Manager struct {
-	dialerManager *tcp.DialerManager
-	configs       map[string]*runtime.TCPServiceInfo
-	rand          *rand.Rand // For the initial shuffling of load-balancers.
+	dialerManager   *tcp.DialerManager
+	metricsRegistry metrics.Registry
+	configs         map[string]*runtime.TCPServiceInfo
+	healthCheckers  map[string]*healthcheck.ServiceTCPHealthChecker
+	rand            *rand.Rand // For the initial shuffling of load-balancers.
 }
 
 // NewManager creates a new manager.
-func NewManager(conf *runtime.Configuration, dialerManager *tcp.DialerManager) *Manager {
+func NewManager(conf *runtime.Configuration, dialerManager *tcp.DialerManager, metricsRegistry metrics.Registry) *Manager {
 	return &Manager{
-		dialerManager: dialerManager,
-		configs:       conf.TCPServices,
-		rand:          rand.New(rand.NewSource(time.Now().UnixNano())),
+		dialerManager:   dialerManager,
+		metricsRegistry: metricsRegistry,
+		configs:         conf.TCPServices,
+		healthCheckers:  make(map[string]*healthcheck.ServiceTCPHealthChecker),
+		rand:            rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
@@ -43,7 +53,14 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 		return nil, fmt.Errorf("the service %q does not exist", serviceQualifiedName)
 	}
 
-	if conf.LoadBalancer != nil && conf.Weighted != nil {
+	value := reflect.ValueOf(*conf.TCPService)
+	var count int
+	for i := 0; i < value.NumField(); i++ {
+		if !value.Field(i).IsNil() {
+			count++
+		}
+	}
+	if count > 1 {
 		err := errors.New("cannot create service: multi-types service not supported, consider declaring two different pieces of service instead")
 		conf.AddError(err, true)
 		return nil, err
@@ -53,10 +70,26 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 	case conf.LoadBalancer != nil:
 		loadBalancer := tcp.NewWRRLoadBalancer()
 
+		strategy, err := balancer.ParseStrategy(conf.LoadBalancer.Strategy)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+		if err := loadBalancer.SetStrategy(strategy, tcp.HashKey(strings.ToLower(conf.LoadBalancer.HashKey))); err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+
 		if len(conf.LoadBalancer.ServersTransport) > 0 {
 			conf.LoadBalancer.ServersTransport = provider.GetQualifiedName(ctx, conf.LoadBalancer.ServersTransport)
 		}
 
+		var passiveHealthChecker *healthcheck.TCPPassiveHealthChecker
+		if conf.LoadBalancer.PassiveHealthCheck != nil {
+			passiveHealthChecker = healthcheck.NewTCPPassiveHealthChecker(logger.WithContext(ctx), m.metricsRegistry, conf.LoadBalancer.PassiveHealthCheck, loadBalancer, conf, serviceQualifiedName)
+		}
+		healthCheckTargets := make(map[healthcheck.TCPTarget]tcp.Dialer)
+
 		for index, server := range shuffle(conf.LoadBalancer.Servers, m.rand) {
 			srvLogger := logger.With().
 				Int(logs.ServerIndex, index).
@@ -67,25 +100,96 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 				continue
 			}
 
-			dialer, err := m.dialerManager.Get(conf.LoadBalancer.ServersTransport, server.TLS)
+			if server.Weight != nil && *server.Weight < 0 {
+				err := fmt.Errorf("invalid weight %d for server %s: weight must be positive or zero", *server.Weight, server.Address)
+				conf.AddError(err, true)
+				return nil, err
+			}
+			if server.Weight != nil && *server.Weight == 0 {
+				srvLogger.Debug().Msg("Server has a weight of 0, it will not receive any connection")
+			}
+
+			if !server.TLS && len(server.TLSServerName) > 0 {
+				err := fmt.Errorf("invalid configuration for server %s: tlsServerName requires tls to be enabled", server.Address)
+				conf.AddError(err, true)
+				return nil, err
+			}
+
+			serversTransport := conf.LoadBalancer.ServersTransport
+			if len(server.ServersTransport) > 0 {
+				serversTransport = provider.GetQualifiedName(ctx, server.ServersTransport)
+			}
+
+			var dialer tcp.Dialer
+			if server.TLS && len(server.TLSServerName) > 0 {
+				dialer, err = m.dialerManager.GetWithServerName(serversTransport, server.TLSServerName)
+			} else {
+				dialer, err = m.dialerManager.Get(serversTransport, server.TLS)
+			}
 			if err != nil {
 				return nil, err
 			}
 
-			handler, err := tcp.NewProxy(server.Address, conf.LoadBalancer.ProxyProtocol, dialer)
+			proxyProtocol := conf.LoadBalancer.ProxyProtocol
+			if server.ProxyProtocol != nil {
+				proxyProtocol = server.ProxyProtocol
+				if proxyProtocol.Version == 0 {
+					proxyProtocol = nil
+				}
+			}
+
+			handler, err := tcp.NewProxy(server.Address, proxyProtocol, dialer)
 			if err != nil {
 				srvLogger.Error().Err(err).Msg("Failed to create server")
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			if passiveHealthChecker != nil {
+				handler.SetDialObserver(passiveHealthChecker.DialObserver(server.Address))
+			}
+
+			loadBalancer.AddWeightServerWithAddress(server.Address, handler, server.Weight)
 			logger.Debug().Msg("Creating TCP server")
+
+			// servers are considered UP by default.
+			conf.UpdateServerStatus(server.Address, runtime.StatusUp)
+
+			target := healthcheck.TCPTarget{
+				Address:          server.Address,
+				ServersTransport: serversTransport,
+				TLS:              server.TLS,
+				TLSServerName:    server.TLSServerName,
+			}
+			healthCheckTargets[target] = dialer
+		}
+
+		if conf.LoadBalancer.Retry != nil && conf.LoadBalancer.Retry.Attempts > 1 {
+			loadBalancer.SetRetry(conf.LoadBalancer.Retry.Attempts, m.metricsRegistry.ServiceRetriesCounter().With("service", serviceQualifiedName))
+		}
+
+		if conf.LoadBalancer.HealthCheck != nil || conf.LoadBalancer.PassiveHealthCheck != nil {
+			loadBalancer.EnableHealthCheck()
+		}
+
+		if conf.LoadBalancer.HealthCheck != nil {
+			m.healthCheckers[serviceQualifiedName] = healthcheck.NewServiceTCPHealthChecker(
+				ctx,
+				m.metricsRegistry,
+				conf.LoadBalancer.HealthCheck,
+				loadBalancer,
+				conf,
+				serviceQualifiedName,
+				healthCheckTargets,
+			)
 		}
 
 		return loadBalancer, nil
 
 	case conf.Weighted != nil:
 		loadBalancer := tcp.NewWRRLoadBalancer()
+		if conf.Weighted.HealthCheck != nil {
+			loadBalancer.EnableHealthCheck()
+		}
 
 		for _, service := range shuffle(conf.Weighted.Services, m.rand) {
 			handler, err := m.BuildTCP(ctx, service.Name)
@@ -94,11 +198,45 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 				return nil, err
 			}
 
-			loadBalancer.AddWeightServer(handler, service.Weight)
+			childName := provider.GetQualifiedName(ctx, service.Name)
+			loadBalancer.AddWeightServerWithName(childName, handler, service.Weight)
+
+			if conf.Weighted.HealthCheck == nil {
+				continue
+			}
+
+			if err := registerStatusUpdater(handler, func(up bool) {
+				loadBalancer.SetStatus(ctx, childName, up)
+			}); err != nil {
+				err = fmt.Errorf("child service %v of %v: %w", childName, serviceQualifiedName, err)
+				conf.AddError(err, true)
+				return nil, err
+			}
+
+			logger.Debug().Str("parent", serviceQualifiedName).Str("child", childName).
+				Msg("Child service will update parent on status change")
 		}
 
 		return loadBalancer, nil
 
+	case conf.Failover != nil:
+		handler, err := m.getFailoverServiceHandler(ctx, serviceQualifiedName, conf.Failover)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		return handler, nil
+
+	case conf.Mirroring != nil:
+		handler, err := m.getMirrorServiceHandler(ctx, conf.Mirroring)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+
+		return handler, nil
+
 	default:
 		err := fmt.Errorf("the service %q does not have any type defined", serviceQualifiedName)
 		conf.AddError(err, true)
@@ -106,6 +244,86 @@ func (m *Manager) BuildTCP(rootCtx context.Context, serviceName string) (tcp.Han
 	}
 }
 
+func (m *Manager) getFailoverServiceHandler(ctx context.Context, serviceName string, config *dynamic.TCPFailover) (tcp.Handler, error) {
+	f := tcp.NewFailover(config.HealthCheck)
+
+	serviceHandler, err := m.BuildTCP(ctx, config.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	f.SetHandler(serviceHandler)
+
+	if err := registerStatusUpdater(serviceHandler, func(up bool) {
+		f.SetHandlerStatus(ctx, up)
+	}); err != nil {
+		return nil, fmt.Errorf("child service %v of %v: %w", config.Service, serviceName, err)
+	}
+
+	fallbackHandler, err := m.BuildTCP(ctx, config.Fallback)
+	if err != nil {
+		return nil, err
+	}
+
+	f.SetFallbackHandler(fallbackHandler)
+
+	// Do not report the health of the fallback handler.
+	if config.HealthCheck == nil {
+		return f, nil
+	}
+
+	if err := registerStatusUpdater(fallbackHandler, func(up bool) {
+		f.SetFallbackHandlerStatus(ctx, up)
+	}); err != nil {
+		return nil, fmt.Errorf("child service %v of %v: %w", config.Fallback, serviceName, err)
+	}
+
+	return f, nil
+}
+
+func (m *Manager) getMirrorServiceHandler(ctx context.Context, config *dynamic.TCPMirroring) (tcp.Handler, error) {
+	serviceHandler, err := m.BuildTCP(ctx, config.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	handler := tcp.NewMirroring(serviceHandler, config.HealthCheck)
+	for _, mirrorConfig := range config.Mirrors {
+		mirrorHandler, err := m.BuildTCP(ctx, mirrorConfig.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := handler.AddMirror(mirrorHandler, mirrorConfig.Percent); err != nil {
+			return nil, err
+		}
+	}
+
+	return handler, nil
+}
+
+// registerStatusUpdater registers fn to be run when the status of the given child service handler changes.
+func registerStatusUpdater(handler tcp.Handler, fn func(up bool)) error {
+	updater, ok := handler.(healthcheck.StatusUpdater)
+	if !ok {
+		return fmt.Errorf("not a healthcheck.StatusUpdater (%T)", handler)
+	}
+
+	if err := updater.RegisterStatusUpdater(fn); err != nil {
+		return fmt.Errorf("cannot register as updater: %w", err)
+	}
+
+	return nil
+}
+
+// LaunchHealthCheck launches the health checks.
+func (m *Manager) LaunchHealthCheck(ctx context.Context) {
+	for serviceName, hc := range m.healthCheckers {
+		logger := log.Ctx(ctx).With().Str(logs.ServiceName, serviceName).Logger()
+		go hc.Launch(logger.WithContext(ctx))
+	}
+}
+
 func shuffle[T any](values []T, r *rand.Rand) []T {
 	shuffled := make([]T, len(values))
 	copy(shuffled, values)
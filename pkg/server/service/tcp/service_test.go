@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
+	"github.com/traefik/traefik/v3/pkg/metrics"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
 	"github.com/traefik/traefik/v3/pkg/tcp"
 )
@@ -234,6 +235,280 @@ func TestManager_BuildTCP(t *testing.T) {
 			providerName:  "provider-1",
 			expectedError: "TCP dialer not found myServersTransport@provider-1",
 		},
+		{
+			desc:        "server serversTransport reference",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"myServersTransport@provider-1": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address:          "192.168.0.12:80",
+									ServersTransport: "myServersTransport",
+								},
+							},
+							ServersTransport: "unknown",
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "server serversTransport reference not found",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address:          "192.168.0.12:80",
+									ServersTransport: "myServersTransport",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "TCP dialer not found myServersTransport@provider-1",
+		},
+		{
+			desc:        "servers with weight, PROXY protocol and TLS server name",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address:       "192.168.0.12:80",
+									Weight:        func(i int) *int { return &i }(3),
+									ProxyProtocol: &dynamic.ProxyProtocol{Version: 1},
+									TLS:           true,
+									TLSServerName: "example.com",
+								},
+								{
+									Address:       "192.168.0.13:80",
+									ProxyProtocol: &dynamic.ProxyProtocol{},
+								},
+							},
+							ProxyProtocol: &dynamic.ProxyProtocol{Version: 2},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "server with a weight of 0",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+									Weight:  func(i int) *int { return &i }(0),
+								},
+								{
+									Address: "192.168.0.13:80",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "server with a negative weight",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address: "192.168.0.12:80",
+									Weight:  func(i int) *int { return &i }(-1),
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "invalid weight -1 for server 192.168.0.12:80: weight must be positive or zero",
+		},
+		{
+			desc:        "server with a TLS server name but without TLS",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address:       "192.168.0.12:80",
+									TLSServerName: "example.com",
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName:  "provider-1",
+			expectedError: "invalid configuration for server 192.168.0.12:80: tlsServerName requires tls to be enabled",
+		},
+		{
+			desc:        "server with an invalid PROXY protocol version, server is skipped, error is logged",
+			serviceName: "serviceName",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"serviceName@provider-1": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{
+								{
+									Address:       "192.168.0.12:80",
+									ProxyProtocol: &dynamic.ProxyProtocol{Version: 42},
+								},
+							},
+						},
+					},
+				},
+			},
+			providerName: "provider-1",
+		},
+		{
+			desc:        "multi-types service",
+			serviceName: "test",
+			configs: map[string]*runtime.TCPServiceInfo{
+				"test": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{},
+						Failover:     &dynamic.TCPFailover{},
+					},
+				},
+			},
+			expectedError: "cannot create service: multi-types service not supported, consider declaring two different pieces of service instead",
+		},
+		{
+			desc:        "failover with health checked services",
+			serviceName: "failover",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"failover": {
+					TCPService: &dynamic.TCPService{
+						Failover: &dynamic.TCPFailover{Service: "main", Fallback: "fallback"},
+					},
+				},
+				"main": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers:            []dynamic.TCPServer{{Address: "192.168.0.12:80"}},
+							PassiveHealthCheck: &dynamic.TCPPassiveHealthCheck{MaxDialFailures: 1},
+						},
+					},
+				},
+				"fallback": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "192.168.0.13:80"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "failover with a main service without health check",
+			serviceName: "failover",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"failover": {
+					TCPService: &dynamic.TCPService{
+						Failover: &dynamic.TCPFailover{Service: "main", Fallback: "fallback"},
+					},
+				},
+				"main": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "192.168.0.12:80"}},
+						},
+					},
+				},
+				"fallback": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "192.168.0.13:80"}},
+						},
+					},
+				},
+			},
+			expectedError: "child service main of failover: cannot register as updater: healthCheck not enabled in config for this service",
+		},
+		{
+			desc:        "mirroring",
+			serviceName: "mirroring",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"mirroring": {
+					TCPService: &dynamic.TCPService{
+						Mirroring: &dynamic.TCPMirroring{
+							Service: "main",
+							Mirrors: []dynamic.TCPMirrorService{{Name: "mirror", Percent: 50}},
+						},
+					},
+				},
+				"main": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "192.168.0.12:80"}},
+						},
+					},
+				},
+				"mirror": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "192.168.0.13:80"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc:        "mirroring with an invalid percent",
+			serviceName: "mirroring",
+			stConfigs:   map[string]*dynamic.TCPServersTransport{"default@internal": {}},
+			configs: map[string]*runtime.TCPServiceInfo{
+				"mirroring": {
+					TCPService: &dynamic.TCPService{
+						Mirroring: &dynamic.TCPMirroring{
+							Service: "main",
+							Mirrors: []dynamic.TCPMirrorService{{Name: "main", Percent: 101}},
+						},
+					},
+				},
+				"main": {
+					TCPService: &dynamic.TCPService{
+						LoadBalancer: &dynamic.TCPServersLoadBalancer{
+							Servers: []dynamic.TCPServer{{Address: "192.168.0.12:80"}},
+						},
+					},
+				},
+			},
+			expectedError: "percent must be between 0 and 100",
+		},
 	}
 
 	for _, test := range testCases {
@@ -248,7 +523,7 @@ func TestManager_BuildTCP(t *testing.T) {
 
 			manager := NewManager(&runtime.Configuration{
 				TCPServices: test.configs,
-			}, dialerManager)
+			}, dialerManager, metrics.NewVoidRegistry())
 
 			ctx := context.Background()
 			if len(test.providerName) > 0 {
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/balancer"
 	"github.com/traefik/traefik/v3/pkg/config/runtime"
 	"github.com/traefik/traefik/v3/pkg/logs"
 	"github.com/traefik/traefik/v3/pkg/server/provider"
@@ -54,6 +55,13 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 	case conf.LoadBalancer != nil:
 		loadBalancer := udp.NewWRRLoadBalancer()
 
+		strategy, err := balancer.ParseStrategy(conf.LoadBalancer.Strategy)
+		if err != nil {
+			conf.AddError(err, true)
+			return nil, err
+		}
+		loadBalancer.SetStrategy(strategy)
+
 		for index, server := range shuffle(conf.LoadBalancer.Servers, m.rand) {
 			srvLogger := logger.With().
 				Int(logs.ServerIndex, index).
@@ -64,25 +72,17 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 				continue
 			}
 
-			var needle needleware.Needle = nil
-			if conf.Needle != nil {
-				needle = m.needles.GetNeedle(conf.Needle.Id, conf.Needle.Metadata)
-				if needle == nil {
-					srvLogger.Error().Msgf("Failed to find needle %q", conf.Needle)
-					continue
-				}
-			}
-			handler, err := udp.NewProxy(server.Address, needle)
+			handler, err := udp.NewProxy(server.Address)
 			if err != nil {
 				srvLogger.Error().Err(err).Msg("Failed to create server")
 				continue
 			}
 
-			loadBalancer.AddServer(handler)
+			loadBalancer.AddServerWithAddress(server.Address, handler)
 			srvLogger.Debug().Msg("Creating UDP server")
 		}
 
-		return loadBalancer, nil
+		return m.withNeedle(conf, loadBalancer)
 
 	case conf.Weighted != nil:
 		loadBalancer := udp.NewWRRLoadBalancer()
@@ -94,10 +94,10 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 				return nil, err
 			}
 
-			loadBalancer.AddWeightedServer(handler, service.Weight)
+			loadBalancer.AddWeightedServerWithName(provider.GetQualifiedName(ctx, service.Name), handler, service.Weight)
 		}
 
-		return loadBalancer, nil
+		return m.withNeedle(conf, loadBalancer)
 
 	default:
 		err := fmt.Errorf("the UDP service %q does not have any type defined", serviceQualifiedName)
@@ -106,6 +106,22 @@ func (m *Manager) BuildUDP(rootCtx context.Context, serviceName string) (udp.Han
 	}
 }
 
+// withNeedle puts the needle of the service, if any, in front of its load-balancer,
+// so that the decision can select the server or the service to forward to.
+func (m *Manager) withNeedle(conf *runtime.UDPServiceInfo, handler udp.Handler) (udp.Handler, error) {
+	if conf.Needle == nil {
+		return handler, nil
+	}
+
+	needle, err := m.needles.GetNeedle(conf.Needle.Id, conf.Needle.Metadata)
+	if err != nil {
+		conf.AddError(err, true)
+		return nil, err
+	}
+
+	return udp.NewNeedleHandler(handler, needle), nil
+}
+
 func shuffle[T any](values []T, r *rand.Rand) []T {
 	shuffled := make([]T, len(values))
 	copy(shuffled, values)
@@ -0,0 +1,189 @@
+// Package shaping enforces the bandwidth and lifetime limits of a proxied connection.
+package shaping
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// CloseReason tells why a connection has been closed.
+type CloseReason int32
+
+const (
+	// CloseReasonNone means that the connection ended on its own.
+	CloseReasonNone CloseReason = iota
+	// CloseReasonMaxTotalBytes means that the connection exceeded its maximum total bytes.
+	CloseReasonMaxTotalBytes
+	// CloseReasonMaxDuration means that the connection exceeded its maximum duration.
+	CloseReasonMaxDuration
+)
+
+// String returns the name of the close reason.
+func (r CloseReason) String() string {
+	switch r {
+	case CloseReasonMaxTotalBytes:
+		return "max total bytes exceeded"
+	case CloseReasonMaxDuration:
+		return "max duration exceeded"
+	default:
+		return "closed"
+	}
+}
+
+// Direction is the direction in which data flows through a proxied connection.
+type Direction int
+
+const (
+	// Upload is the direction from the client to the server.
+	Upload Direction = iota
+	// Download is the direction from the server to the client.
+	Download
+)
+
+// ErrLimitExceeded is returned by the readers of a Session once a limit has been exceeded.
+var ErrLimitExceeded = errors.New("connection limit exceeded")
+
+// Limits restricts the bandwidth and lifetime of a single connection.
+// Zero values mean unlimited.
+type Limits struct {
+	UploadBytesPerSecond   int64
+	DownloadBytesPerSecond int64
+	MaxTotalBytes          int64
+	MaxDuration            time.Duration
+
+	closeReason atomic.Int32
+}
+
+// IsZero reports whether the limits do not restrict anything.
+func (l *Limits) IsZero() bool {
+	return l.UploadBytesPerSecond <= 0 && l.DownloadBytesPerSecond <= 0 && l.MaxTotalBytes <= 0 && l.MaxDuration <= 0
+}
+
+// CloseReason returns why the connection has been closed by a Session enforcing the limits.
+func (l *Limits) CloseReason() CloseReason {
+	return CloseReason(l.closeReason.Load())
+}
+
+// Session enforces Limits on both directions of a proxied connection.
+type Session struct {
+	limits    *Limits
+	upload    *rate.Limiter
+	download  *rate.Limiter
+	total     atomic.Int64
+	timer     *time.Timer
+	closeOnce sync.Once
+	closeFn   func()
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewSession starts enforcing limits, calling closeFn once a cap is exceeded.
+// minBurst is the minimum number of bytes a single read must be allowed to return,
+// which is the maximum datagram size for packet-oriented connections.
+func NewSession(limits *Limits, minBurst int, closeFn func()) *Session {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Session{
+		limits:   limits,
+		upload:   newLimiter(limits.UploadBytesPerSecond, minBurst),
+		download: newLimiter(limits.DownloadBytesPerSecond, minBurst),
+		closeFn:  closeFn,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	if limits.MaxDuration > 0 {
+		s.timer = time.AfterFunc(limits.MaxDuration, func() {
+			s.close(CloseReasonMaxDuration)
+		})
+	}
+
+	return s
+}
+
+func newLimiter(bytesPerSecond int64, minBurst int) *rate.Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	burst := int(bytesPerSecond)
+	if burst < minBurst {
+		burst = minBurst
+	}
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), burst)
+}
+
+// Reader returns a reader shaping the data read from r in the given direction.
+func (s *Session) Reader(r io.Reader, direction Direction) io.Reader {
+	limiter := s.upload
+	if direction == Download {
+		limiter = s.download
+	}
+	return &reader{session: s, limiter: limiter, reader: r}
+}
+
+// Stop releases the resources of the session, without closing the connection.
+func (s *Session) Stop() {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.cancel()
+}
+
+// CloseReason returns why the session closed the connection.
+func (s *Session) CloseReason() CloseReason {
+	return s.limits.CloseReason()
+}
+
+func (s *Session) close(reason CloseReason) {
+	s.closeOnce.Do(func() {
+		s.limits.closeReason.Store(int32(reason))
+		s.cancel()
+		s.closeFn()
+	})
+}
+
+type reader struct {
+	session *Session
+	limiter *rate.Limiter
+	reader  io.Reader
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if r.session.CloseReason() != CloseReasonNone {
+		return 0, ErrLimitExceeded
+	}
+
+	// A single read cannot consume more tokens than the bucket can hold.
+	if r.limiter != nil && len(p) > r.limiter.Burst() {
+		p = p[:r.limiter.Burst()]
+	}
+
+	n, err := r.reader.Read(p)
+	if n == 0 {
+		return n, err
+	}
+
+	if maxTotal := r.session.limits.MaxTotalBytes; maxTotal > 0 && r.session.total.Add(int64(n)) > maxTotal {
+		r.session.close(CloseReasonMaxTotalBytes)
+		return 0, ErrLimitExceeded
+	}
+
+	if r.limiter != nil {
+		if errWait := r.limiter.WaitN(r.session.ctx, n); errWait != nil {
+			if r.session.CloseReason() != CloseReasonNone {
+				return 0, ErrLimitExceeded
+			}
+			return 0, errWait
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,73 @@
+package shaping
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession_Rate(t *testing.T) {
+	limits := &Limits{UploadBytesPerSecond: 1000}
+	session := NewSession(limits, 0, func() {})
+	defer session.Stop()
+
+	start := time.Now()
+	n, err := io.Copy(io.Discard, session.Reader(bytes.NewReader(make([]byte, 1500)), Upload))
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1500), n)
+	// The bucket starts full with 1000 tokens, the 500 remaining bytes take half a second.
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	assert.Equal(t, CloseReasonNone, limits.CloseReason())
+}
+
+func TestSession_Unshaped(t *testing.T) {
+	limits := &Limits{UploadBytesPerSecond: 1}
+	session := NewSession(limits, 0, func() {})
+	defer session.Stop()
+
+	n, err := io.Copy(io.Discard, session.Reader(bytes.NewReader(make([]byte, 64*1024)), Download))
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(64*1024), n)
+}
+
+func TestSession_MaxTotalBytes(t *testing.T) {
+	var closed atomic.Bool
+	limits := &Limits{MaxTotalBytes: 100}
+	session := NewSession(limits, 0, func() { closed.Store(true) })
+	defer session.Stop()
+
+	n, err := io.Copy(io.Discard, session.Reader(bytes.NewReader(make([]byte, 60)), Upload))
+	require.NoError(t, err)
+	assert.Equal(t, int64(60), n)
+
+	_, err = io.Copy(io.Discard, session.Reader(bytes.NewReader(make([]byte, 60)), Download))
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	assert.True(t, closed.Load())
+	assert.Equal(t, CloseReasonMaxTotalBytes, limits.CloseReason())
+}
+
+func TestSession_MaxDuration(t *testing.T) {
+	closed := make(chan struct{})
+	limits := &Limits{MaxDuration: 50 * time.Millisecond}
+	session := NewSession(limits, 0, func() { close(closed) })
+	defer session.Stop()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for the session to close the connection")
+	}
+
+	assert.Equal(t, CloseReasonMaxDuration, limits.CloseReason())
+
+	_, err := session.Reader(bytes.NewReader([]byte("foo")), Upload).Read(make([]byte, 3))
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+}
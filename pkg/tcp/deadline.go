@@ -0,0 +1,23 @@
+package tcp
+
+import (
+	"net"
+	"time"
+)
+
+// ReadDeadlineConn is a connection reporting its read deadline.
+type ReadDeadlineConn interface {
+	net.Conn
+
+	// ReadDeadline returns the read deadline last set on the connection, the zero value meaning no deadline.
+	ReadDeadline() time.Time
+}
+
+// GetReadDeadline returns the read deadline of conn or of one of the connections it wraps,
+// and whether it is known.
+func GetReadDeadline(conn net.Conn) (time.Time, bool) {
+	if c, ok := findConn[ReadDeadlineConn](conn); ok {
+		return c.ReadDeadline(), true
+	}
+	return time.Time{}, false
+}
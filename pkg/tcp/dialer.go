@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -23,17 +24,39 @@ type Dialer interface {
 	proxy.Dialer
 
 	TerminationDelay() time.Duration
+	// IdleTimeout returns the maximum duration the connections can stay without any data transferred, zero meaning no timeout.
+	IdleTimeout() time.Duration
+	// MaxLifetime returns the maximum duration of the connections, zero meaning no maximum.
+	MaxLifetime() time.Duration
 }
 
 type tcpDialer struct {
 	proxy.Dialer
 	terminationDelay time.Duration
+	idleTimeout      time.Duration
+	maxLifetime      time.Duration
 }
 
 func (d tcpDialer) TerminationDelay() time.Duration {
 	return d.terminationDelay
 }
 
+func (d tcpDialer) IdleTimeout() time.Duration {
+	return d.idleTimeout
+}
+
+func (d tcpDialer) MaxLifetime() time.Duration {
+	return d.maxLifetime
+}
+
+// DialContext dials with the given context, if the underlying dialer supports it.
+func (d tcpDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if contextDialer, ok := d.Dialer.(proxy.ContextDialer); ok {
+		return contextDialer.DialContext(ctx, network, address)
+	}
+	return d.Dial(network, address)
+}
+
 // SpiffeX509Source allows to retrieve a x509 SVID and bundle.
 type SpiffeX509Source interface {
 	x509svid.Source
@@ -98,6 +121,38 @@ func (d *DialerManager) Get(name string, tls bool) (Dialer, error) {
 	return nil, fmt.Errorf("TCP dialer not found %s", name)
 }
 
+// GetWithServerName gets a TLS dialer by name, which overrides the server name
+// sent to the servers, and against which their certificates are verified.
+func (d *DialerManager) GetWithServerName(name, serverName string) (Dialer, error) {
+	dialer, err := d.Get(name, true)
+	if err != nil {
+		return nil, err
+	}
+
+	rt, ok := dialer.(tcpDialer)
+	if !ok {
+		return nil, fmt.Errorf("TCP dialer %s does not support a server name", name)
+	}
+
+	tlsDialer, ok := rt.Dialer.(*tls.Dialer)
+	if !ok {
+		return nil, fmt.Errorf("TCP dialer %s does not support a server name", name)
+	}
+
+	tlsConfig := &tls.Config{}
+	if tlsDialer.Config != nil {
+		tlsConfig = tlsDialer.Config.Clone()
+	}
+	tlsConfig.ServerName = serverName
+
+	rt.Dialer = &tls.Dialer{
+		NetDialer: tlsDialer.NetDialer,
+		Config:    tlsConfig,
+	}
+
+	return rt, nil
+}
+
 // createDialers creates the dialers according to the TCPServersTransport configuration.
 func (d *DialerManager) createDialers(name string, cfg *dynamic.TCPServersTransport) error {
 	if cfg == nil {
@@ -150,8 +205,18 @@ func (d *DialerManager) createDialers(name string, cfg *dynamic.TCPServersTransp
 		Config:    tlsConfig,
 	}
 
-	d.dialers[name] = tcpDialer{dialer, time.Duration(cfg.TerminationDelay)}
-	d.dialersTLS[name] = tcpDialer{tlsDialer, time.Duration(cfg.TerminationDelay)}
+	d.dialers[name] = tcpDialer{
+		Dialer:           dialer,
+		terminationDelay: time.Duration(cfg.TerminationDelay),
+		idleTimeout:      time.Duration(cfg.IdleTimeout),
+		maxLifetime:      time.Duration(cfg.MaxLifetime),
+	}
+	d.dialersTLS[name] = tcpDialer{
+		Dialer:           tlsDialer,
+		terminationDelay: time.Duration(cfg.TerminationDelay),
+		idleTimeout:      time.Duration(cfg.IdleTimeout),
+		maxLifetime:      time.Duration(cfg.MaxLifetime),
+	}
 
 	return nil
 }
@@ -275,6 +275,61 @@ func TestTLSWithInsecureSkipVerify(t *testing.T) {
 	assert.Equal(t, "PONG", buffer.String())
 }
 
+func TestTLSWithServerName(t *testing.T) {
+	cert, err := tls.X509KeyPair(LocalhostCert, LocalhostKey)
+	require.NoError(t, err)
+
+	backendListener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer backendListener.Close()
+
+	tlsListener := tls.NewListener(backendListener, &tls.Config{Certificates: []tls.Certificate{cert}})
+	defer tlsListener.Close()
+
+	go fakeRedis(t, tlsListener)
+
+	_, port, err := net.SplitHostPort(tlsListener.Addr().String())
+	require.NoError(t, err)
+
+	dialerManager := NewDialerManager(nil)
+
+	dynamicConf := map[string]*dynamic.TCPServersTransport{
+		"test": {
+			TLS: &dynamic.TLSClientConfig{
+				ServerName: "bad-domain.com",
+				RootCAs:    []traefiktls.FileOrContent{traefiktls.FileOrContent(LocalhostCert)},
+			},
+		},
+	}
+
+	dialerManager.Update(dynamicConf)
+
+	dialer, err := dialerManager.GetWithServerName("test", "example.com")
+	require.NoError(t, err)
+
+	conn, err := dialer.Dial("tcp", ":"+port)
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("ping\n"))
+	require.NoError(t, err)
+
+	err = conn.(*tls.Conn).CloseWrite()
+	require.NoError(t, err)
+
+	var buf []byte
+	buffer := bytes.NewBuffer(buf)
+	n, err := io.Copy(buffer, conn)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(4), n)
+	assert.Equal(t, "PONG", buffer.String())
+
+	// The server name of the servers transport is left unchanged.
+	dialer, err = dialerManager.Get("test", true)
+	require.NoError(t, err)
+	assert.Equal(t, "bad-domain.com", dialer.(tcpDialer).Dialer.(*tls.Dialer).Config.ServerName)
+}
+
 func TestMTLS(t *testing.T) {
 	cert, err := tls.X509KeyPair(LocalhostCert, LocalhostKey)
 	require.NoError(t, err)
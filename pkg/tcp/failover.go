@@ -0,0 +1,118 @@
+package tcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// Failover is a Handler that forwards the connections to the fallback handler
+// when the main handler status is down.
+type Failover struct {
+	wantsHealthCheck bool
+	handler          Handler
+	fallbackHandler  Handler
+	// updaters is the list of hooks that are run (to update the Failover
+	// parent(s)), whenever the Failover status changes.
+	updaters []func(bool)
+
+	statusMu       sync.RWMutex
+	handlerStatus  bool
+	fallbackStatus bool
+}
+
+// NewFailover creates a new Failover handler.
+func NewFailover(hc *dynamic.HealthCheck) *Failover {
+	return &Failover{
+		wantsHealthCheck: hc != nil,
+	}
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the Failover changes.
+// Not thread safe.
+func (f *Failover) RegisterStatusUpdater(fn func(up bool)) error {
+	if !f.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this failover service")
+	}
+
+	f.updaters = append(f.updaters, fn)
+
+	return nil
+}
+
+// ServeTCP forwards the connection to the main handler, or to the fallback handler while the main one is down.
+func (f *Failover) ServeTCP(conn WriteCloser) {
+	f.statusMu.RLock()
+	handlerStatus, fallbackStatus := f.handlerStatus, f.fallbackStatus
+	f.statusMu.RUnlock()
+
+	if handlerStatus {
+		f.handler.ServeTCP(conn)
+		return
+	}
+
+	if fallbackStatus {
+		f.fallbackHandler.ServeTCP(conn)
+		return
+	}
+
+	log.Error().Msg("Error during failover: main and fallback services are down")
+	conn.Close()
+}
+
+// SetHandler sets the main Handler.
+func (f *Failover) SetHandler(handler Handler) {
+	f.statusMu.Lock()
+	defer f.statusMu.Unlock()
+
+	f.handler = handler
+	f.handlerStatus = true
+}
+
+// SetHandlerStatus sets the main handler status.
+func (f *Failover) SetHandlerStatus(ctx context.Context, up bool) {
+	f.setStatus(ctx, &f.handlerStatus, up)
+}
+
+// SetFallbackHandler sets the fallback Handler.
+func (f *Failover) SetFallbackHandler(handler Handler) {
+	f.statusMu.Lock()
+	defer f.statusMu.Unlock()
+
+	f.fallbackHandler = handler
+	f.fallbackStatus = true
+}
+
+// SetFallbackHandlerStatus sets the fallback handler status.
+func (f *Failover) SetFallbackHandlerStatus(ctx context.Context, up bool) {
+	f.setStatus(ctx, &f.fallbackStatus, up)
+}
+
+func (f *Failover) setStatus(ctx context.Context, handlerStatus *bool, up bool) {
+	f.statusMu.Lock()
+	defer f.statusMu.Unlock()
+
+	status := "DOWN"
+	if up {
+		status = "UP"
+	}
+
+	if up == *handlerStatus {
+		// We're still with the same status, no need to propagate.
+		log.Ctx(ctx).Debug().Msgf("Still %s, no need to propagate", status)
+		return
+	}
+
+	log.Ctx(ctx).Debug().Msgf("Propagating new %s status", status)
+	*handlerStatus = up
+
+	for _, fn := range f.updaters {
+		// Failover service status is set to DOWN
+		// when main and fallback handlers have a DOWN status.
+		fn(f.handlerStatus || f.fallbackStatus)
+	}
+}
@@ -0,0 +1,95 @@
+package tcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+func TestFailover(t *testing.T) {
+	failover := NewFailover(&dynamic.HealthCheck{})
+
+	status := true
+	require.NoError(t, failover.RegisterStatusUpdater(func(up bool) {
+		status = up
+	}))
+
+	failover.SetHandler(HandlerFunc(func(conn WriteCloser) {
+		_, _ = conn.Write([]byte("handler"))
+	}))
+	failover.SetFallbackHandler(HandlerFunc(func(conn WriteCloser) {
+		_, _ = conn.Write([]byte("fallback"))
+	}))
+
+	conn := &fakeConn{writeCall: make(map[string]int)}
+	failover.ServeTCP(conn)
+	assert.Equal(t, map[string]int{"handler": 1}, conn.writeCall)
+	assert.True(t, status)
+
+	failover.SetHandlerStatus(context.Background(), false)
+
+	conn = &fakeConn{writeCall: make(map[string]int)}
+	failover.ServeTCP(conn)
+	assert.Equal(t, map[string]int{"fallback": 1}, conn.writeCall)
+	assert.True(t, status)
+
+	failover.SetFallbackHandlerStatus(context.Background(), false)
+
+	conn = &fakeConn{writeCall: make(map[string]int)}
+	failover.ServeTCP(conn)
+	assert.Empty(t, conn.writeCall)
+	assert.Equal(t, 1, conn.closeCall)
+	assert.False(t, status)
+
+	failover.SetHandlerStatus(context.Background(), true)
+
+	conn = &fakeConn{writeCall: make(map[string]int)}
+	failover.ServeTCP(conn)
+	assert.Equal(t, map[string]int{"handler": 1}, conn.writeCall)
+	assert.True(t, status)
+}
+
+func TestFailover_RegisterStatusUpdater_WithoutHealthCheck(t *testing.T) {
+	failover := NewFailover(nil)
+
+	assert.Error(t, failover.RegisterStatusUpdater(func(bool) {}))
+}
+
+func TestFailover_LoadBalancerStatus(t *testing.T) {
+	loadBalancer := NewWRRLoadBalancer()
+	loadBalancer.EnableHealthCheck()
+	for _, address := range []string{"h1", "h2"} {
+		address := address
+		loadBalancer.AddServerWithAddress(address, HandlerFunc(func(conn WriteCloser) {
+			_, _ = conn.Write([]byte(address))
+		}))
+	}
+
+	failover := NewFailover(nil)
+	failover.SetHandler(loadBalancer)
+	failover.SetFallbackHandler(HandlerFunc(func(conn WriteCloser) {
+		_, _ = conn.Write([]byte("fallback"))
+	}))
+	require.NoError(t, loadBalancer.RegisterStatusUpdater(func(up bool) {
+		failover.SetHandlerStatus(context.Background(), up)
+	}))
+
+	// The failover switches to the fallback once all the servers are down.
+	loadBalancer.SetStatus(context.Background(), "h1", false)
+	conn := &fakeConn{writeCall: make(map[string]int)}
+	failover.ServeTCP(conn)
+	assert.Equal(t, map[string]int{"h2": 1}, conn.writeCall)
+
+	loadBalancer.SetStatus(context.Background(), "h2", false)
+	conn = &fakeConn{writeCall: make(map[string]int)}
+	failover.ServeTCP(conn)
+	assert.Equal(t, map[string]int{"fallback": 1}, conn.writeCall)
+
+	loadBalancer.SetStatus(context.Background(), "h1", true)
+	conn = &fakeConn{writeCall: make(map[string]int)}
+	failover.ServeTCP(conn)
+	assert.Equal(t, map[string]int{"h1": 1}, conn.writeCall)
+}
@@ -0,0 +1,30 @@
+package tcp
+
+import (
+	"net"
+
+	"github.com/traefik/traefik/v3/pkg/shaping"
+)
+
+type limitedConn struct {
+	WriteCloser
+	limits *shaping.Limits
+}
+
+// NetConn returns the underlying connection.
+func (c *limitedConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// WithLimits returns a connection wrapping conn and carrying the limits the proxy should enforce.
+func WithLimits(conn WriteCloser, limits *shaping.Limits) WriteCloser {
+	return &limitedConn{WriteCloser: conn, limits: limits}
+}
+
+// GetLimits returns the limits carried by conn or by one of the connections it wraps, if any.
+func GetLimits(conn net.Conn) *shaping.Limits {
+	if c, ok := findConn[*limitedConn](conn); ok {
+		return c.limits
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package tcp
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+)
+
+// mirrorBufferSize is the number of reads from the client buffered for each mirror,
+// a mirror which does not keep up with the client being dropped,
+// for the mirrors to never slow down the connections.
+const mirrorBufferSize = 64
+
+// Mirroring is a Handler that copies the bytes sent by the clients to mirror handlers,
+// the bytes sent back by the mirrors being discarded.
+type Mirroring struct {
+	handler          Handler
+	mirrorHandlers   []*mirrorHandler
+	wantsHealthCheck bool
+
+	lock  sync.Mutex
+	total uint64
+}
+
+type mirrorHandler struct {
+	Handler
+	percent int
+	count   uint64
+}
+
+// NewMirroring creates a new Mirroring handler forwarding the connections to the given handler.
+func NewMirroring(handler Handler, hc *dynamic.HealthCheck) *Mirroring {
+	return &Mirroring{
+		handler:          handler,
+		wantsHealthCheck: hc != nil,
+	}
+}
+
+// AddMirror adds a handler the given percentage of the connections is mirrored to.
+func (m *Mirroring) AddMirror(handler Handler, percent int) error {
+	if percent < 0 || percent > 100 {
+		return errors.New("percent must be between 0 and 100")
+	}
+	m.mirrorHandlers = append(m.mirrorHandlers, &mirrorHandler{Handler: handler, percent: percent})
+	return nil
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of handler of the Mirroring changes.
+// Not thread safe.
+func (m *Mirroring) RegisterStatusUpdater(fn func(up bool)) error {
+	if !m.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this mirroring service")
+	}
+
+	updater, ok := m.handler.(interface{ RegisterStatusUpdater(fn func(up bool)) error })
+	if !ok {
+		return errors.New("service of mirroring does not report its status")
+	}
+
+	return updater.RegisterStatusUpdater(fn)
+}
+
+// ServeTCP forwards the connection to the handler, and copies the bytes sent by the client to the active mirrors.
+func (m *Mirroring) ServeTCP(conn WriteCloser) {
+	mirrors := m.getActiveMirrors()
+	if len(mirrors) == 0 {
+		m.handler.ServeTCP(conn)
+		return
+	}
+
+	tee := &teeConn{WriteCloser: conn}
+	for _, handler := range mirrors {
+		tee.mirrors = append(tee.mirrors, startMirror(conn, handler))
+	}
+	defer tee.closeMirrors()
+
+	m.handler.ServeTCP(tee)
+}
+
+func (m *Mirroring) getActiveMirrors() []Handler {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.total++
+
+	var mirrors []Handler
+	for _, handler := range m.mirrorHandlers {
+		if handler.count*100 < m.total*uint64(handler.percent) {
+			handler.count++
+			mirrors = append(mirrors, handler)
+		}
+	}
+	return mirrors
+}
+
+// teeConn copies the bytes read from the client to the mirrors.
+type teeConn struct {
+	WriteCloser
+	mirrors []*mirror
+}
+
+// NetConn returns the underlying connection.
+func (c *teeConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+func (c *teeConn) Read(p []byte) (int, error) {
+	n, err := c.WriteCloser.Read(p)
+	if n > 0 {
+		for _, m := range c.mirrors {
+			m.send(p[:n])
+		}
+	}
+	if err != nil {
+		// The client is done sending, so are the mirrors.
+		c.closeMirrors()
+	}
+	return n, err
+}
+
+func (c *teeConn) closeMirrors() {
+	for _, m := range c.mirrors {
+		m.close()
+	}
+}
+
+// mirror is a connection to a mirror handler, fed with the bytes sent by the client.
+type mirror struct {
+	mu     sync.Mutex
+	closed bool
+	chunks chan []byte
+}
+
+// startMirror connects the mirror handler to a pipe, which is fed with the bytes sent to the mirror,
+// the bytes sent back by the handler being discarded.
+func startMirror(client net.Conn, handler Handler) *mirror {
+	m := &mirror{chunks: make(chan []byte, mirrorBufferSize)}
+
+	clientEnd, serverEnd := net.Pipe()
+
+	go func() {
+		defer clientEnd.Close()
+
+		// Once the mirror is gone, the remaining chunks are discarded.
+		var gone bool
+		for chunk := range m.chunks {
+			if gone {
+				continue
+			}
+			if _, err := clientEnd.Write(chunk); err != nil {
+				gone = true
+			}
+		}
+	}()
+
+	go func() {
+		_, _ = io.Copy(io.Discard, clientEnd)
+	}()
+
+	go handler.ServeTCP(&mirrorConn{Conn: serverEnd, client: client})
+
+	return m
+}
+
+// send copies the given bytes to the mirror, which is dropped if it does not keep up.
+func (m *mirror) send(p []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return
+	}
+
+	chunk := make([]byte, len(p))
+	copy(chunk, p)
+
+	select {
+	case m.chunks <- chunk:
+	default:
+		log.Debug().Msg("Dropping mirrored TCP connection not keeping up with the client")
+		m.closed = true
+		close(m.chunks)
+	}
+}
+
+// close ends the connection to the mirror, once the buffered bytes are sent.
+func (m *mirror) close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return
+	}
+
+	m.closed = true
+	close(m.chunks)
+}
+
+// mirrorConn is the connection of a mirror handler,
+// which has the addresses of the client connection.
+type mirrorConn struct {
+	net.Conn
+	client net.Conn
+}
+
+func (c *mirrorConn) LocalAddr() net.Addr {
+	return c.client.LocalAddr()
+}
+
+func (c *mirrorConn) RemoteAddr() net.Addr {
+	return c.client.RemoteAddr()
+}
+
+// CloseWrite does nothing, as the bytes sent by the mirror are discarded.
+func (c *mirrorConn) CloseWrite() error {
+	return nil
+}
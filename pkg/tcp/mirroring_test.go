@@ -0,0 +1,113 @@
+package tcp
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirroring(t *testing.T) {
+	mirrored := make(chan string, 1)
+
+	handler := HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+
+		received, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		_, _ = conn.Write(append([]byte("main:"), received...))
+	})
+
+	mirroring := NewMirroring(handler, nil)
+	err := mirroring.AddMirror(HandlerFunc(func(conn WriteCloser) {
+		defer conn.Close()
+
+		received, _ := io.ReadAll(conn)
+		mirrored <- string(received)
+
+		// The response of the mirror is discarded.
+		_, _ = conn.Write([]byte("mirror"))
+	}), 100)
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		mirroring.ServeTCP(conn.(*net.TCPConn))
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	_, err = conn.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, conn.(*net.TCPConn).CloseWrite())
+
+	received, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, "main:hello", string(received))
+
+	select {
+	case data := <-mirrored:
+		assert.Equal(t, "hello", data)
+	case <-time.After(time.Second):
+		t.Fatal("connection not mirrored")
+	}
+}
+
+func TestMirroring_Percent(t *testing.T) {
+	mirroring := NewMirroring(HandlerFunc(func(WriteCloser) {}), nil)
+	require.NoError(t, mirroring.AddMirror(HandlerFunc(func(WriteCloser) {}), 50))
+	require.NoError(t, mirroring.AddMirror(HandlerFunc(func(WriteCloser) {}), 0))
+	assert.Error(t, mirroring.AddMirror(HandlerFunc(func(WriteCloser) {}), 101))
+
+	var mirrored int
+	for i := 0; i < 10; i++ {
+		mirrored += len(mirroring.getActiveMirrors())
+	}
+	assert.Equal(t, 5, mirrored)
+}
+
+// readerConn is a connection from which the client sends the same bytes indefinitely.
+type readerConn struct {
+	*fakeConn
+}
+
+func (c readerConn) Read(p []byte) (int, error) {
+	return copy(p, "data"), nil
+}
+
+func (c readerConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234}
+}
+
+func TestMirroring_SlowMirror(t *testing.T) {
+	blocked := make(chan struct{})
+	t.Cleanup(func() { close(blocked) })
+
+	conn := readerConn{&fakeConn{writeCall: make(map[string]int)}}
+
+	// The mirror never reads the connection.
+	m := startMirror(conn, HandlerFunc(func(WriteCloser) { <-blocked }))
+	tee := &teeConn{WriteCloser: conn, mirrors: []*mirror{m}}
+
+	buf := make([]byte, 4)
+	for i := 0; i < 2*mirrorBufferSize; i++ {
+		_, err := tee.Read(buf)
+		require.NoError(t, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.True(t, m.closed)
+}
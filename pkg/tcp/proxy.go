@@ -5,19 +5,26 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/pires/go-proxyproto"
 	"github.com/rs/zerolog/log"
 	"github.com/traefik/traefik/v3/pkg/config/dynamic"
+	"github.com/traefik/traefik/v3/pkg/shaping"
 )
 
 // Proxy forwards a TCP request to a TCP service.
 type Proxy struct {
-	address       string
+	address string
+	// samePort is set when the port of the address is 0,
+	// in which case the connection is forwarded to its own destination port.
+	samePort      bool
 	proxyProtocol *dynamic.ProxyProtocol
 	dialer        Dialer
+	// dialObserver is notified of the result of each dial to the server, if set.
+	dialObserver func(err error)
 }
 
 // NewProxy creates a new Proxy.
@@ -26,13 +33,22 @@ func NewProxy(address string, proxyProtocol *dynamic.ProxyProtocol, dialer Diale
 		return nil, fmt.Errorf("unknown proxyProtocol version: %d", proxyProtocol.Version)
 	}
 
+	_, port, err := net.SplitHostPort(address)
+
 	return &Proxy{
 		address:       address,
+		samePort:      err == nil && port == "0",
 		proxyProtocol: proxyProtocol,
 		dialer:        dialer,
 	}, nil
 }
 
+// SetDialObserver sets the function notified of the result of each dial to the server,
+// which is nil on success, e.g. for passive health checking.
+func (p *Proxy) SetDialObserver(observer func(err error)) {
+	p.dialObserver = observer
+}
+
 // ServeTCP forwards the connection to a service.
 func (p *Proxy) ServeTCP(conn WriteCloser) {
 	log.Debug().
@@ -40,32 +56,72 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 		Str("remoteAddr", conn.RemoteAddr().String()).
 		Msg("Handling TCP connection")
 
-	// needed because of e.g. server.trackedConnection
-	defer conn.Close()
-
-	connBackend, err := p.dialBackend()
+	connBackend, err := p.dial(conn)
 	if err != nil {
 		log.Error().Err(err).Msg("Error while dialing backend")
+		conn.Close()
 		return
 	}
 
+	p.serve(conn, connBackend)
+}
+
+// dial dials the backend for the connection, notifying the dial observer of the result.
+func (p *Proxy) dial(conn WriteCloser) (WriteCloser, error) {
+	connBackend, err := p.dialBackend(conn)
+	if p.dialObserver != nil {
+		p.dialObserver(err)
+	}
+	return connBackend, err
+}
+
+// serve forwards the connection to the dialed backend, and closes both connections once done.
+func (p *Proxy) serve(conn, connBackend WriteCloser) {
+	// needed because of e.g. server.trackedConnection
+	defer conn.Close()
+
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 	errChan := make(chan error)
 
-	if p.proxyProtocol != nil && p.proxyProtocol.Version > 0 && p.proxyProtocol.Version < 3 {
-		header := proxyproto.HeaderProxyFromAddrs(byte(p.proxyProtocol.Version), conn.RemoteAddr(), conn.LocalAddr())
+	if header := p.proxyProtocolHeader(conn); header != nil {
 		if _, err := header.WriteTo(connBackend); err != nil {
 			log.Error().Err(err).Msg("Error while writing TCP proxy protocol headers to backend connection")
 			return
 		}
 	}
 
-	go p.connCopy(conn, connBackend, errChan)
-	go p.connCopy(connBackend, conn, errChan)
+	var session *shaping.Session
+	if limits := GetLimits(conn); limits != nil && !limits.IsZero() {
+		session = shaping.NewSession(limits, 0, func() {
+			conn.Close()
+			connBackend.Close()
+		})
+		defer session.Stop()
+	}
 
-	err = <-errChan
-	if err != nil {
+	timeouts := newConnTimeouts(p.dialer.IdleTimeout(), p.dialer.MaxLifetime(), conn, connBackend)
+	if timeouts != nil {
+		defer timeouts.Stop()
+	}
+
+	go p.connCopy(conn, connBackend, session, timeouts, shaping.Download, errChan)
+	go p.connCopy(connBackend, conn, session, timeouts, shaping.Upload, errChan)
+
+	err := <-errChan
+	if timeouts != nil && timeouts.CloseReason() != timeoutReasonNone {
+		log.Debug().
+			Str("address", p.address).
+			Str("remoteAddr", conn.RemoteAddr().String()).
+			Str("closeReason", timeouts.CloseReason().String()).
+			Msg("Closing TCP connection exceeding its timeouts")
+	} else if session != nil && session.CloseReason() != shaping.CloseReasonNone {
+		log.Debug().
+			Str("address", p.address).
+			Str("remoteAddr", conn.RemoteAddr().String()).
+			Str("closeReason", session.CloseReason().String()).
+			Msg("Closing TCP connection exceeding its limits")
+	} else if err != nil {
 		// Treat connection reset error during a read operation with a lower log level.
 		// This allows to not report an RST packet sent by the peer as an error,
 		// as it is an abrupt but possible end for the TCP session
@@ -79,17 +135,68 @@ func (p *Proxy) ServeTCP(conn WriteCloser) {
 	<-errChan
 }
 
-func (p Proxy) dialBackend() (WriteCloser, error) {
-	conn, err := p.dialer.Dial("tcp", p.address)
+// proxyProtocolHeader returns the PROXY protocol header to send to the backend, if any.
+// The configured header can be overridden by the target carried by the connection.
+func (p Proxy) proxyProtocolHeader(conn WriteCloser) *proxyproto.Header {
+	version := 0
+	if p.proxyProtocol != nil {
+		version = p.proxyProtocol.Version
+	}
+
+	sourceAddr, destinationAddr := conn.RemoteAddr(), conn.LocalAddr()
+
+	if target := GetTarget(conn); target != nil && target.ProxyProtocol != nil {
+		version = target.ProxyProtocol.Version
+		if target.ProxyProtocol.SourceAddr != nil {
+			sourceAddr = target.ProxyProtocol.SourceAddr
+		}
+		if target.ProxyProtocol.DestinationAddr != nil {
+			destinationAddr = target.ProxyProtocol.DestinationAddr
+		}
+	}
+
+	if version < 1 || version > 2 {
+		return nil
+	}
+
+	return proxyproto.HeaderProxyFromAddrs(byte(version), sourceAddr, destinationAddr)
+}
+
+func (p Proxy) dialBackend(conn WriteCloser) (WriteCloser, error) {
+	connBackend, err := p.dialer.Dial("tcp", p.backendAddress(conn))
 	if err != nil {
 		return nil, err
 	}
 
-	return conn.(WriteCloser), nil
+	return connBackend.(WriteCloser), nil
 }
 
-func (p Proxy) connCopy(dst, src WriteCloser, errCh chan error) {
-	_, err := io.Copy(dst, src)
+// backendAddress returns the address of the backend to dial for the connection,
+// which has the destination port of the connection if the address has the port 0.
+func (p Proxy) backendAddress(conn WriteCloser) string {
+	if !p.samePort {
+		return p.address
+	}
+
+	localAddr, ok := conn.LocalAddr().(*net.TCPAddr)
+	if !ok {
+		return p.address
+	}
+
+	host, _, _ := net.SplitHostPort(p.address)
+	return net.JoinHostPort(host, strconv.Itoa(localAddr.Port))
+}
+
+func (p Proxy) connCopy(dst, src WriteCloser, session *shaping.Session, timeouts *connTimeouts, direction shaping.Direction, errCh chan error) {
+	var reader io.Reader = src
+	if timeouts != nil {
+		reader = timeouts.Reader(reader)
+	}
+	if session != nil {
+		reader = session.Reader(reader, direction)
+	}
+
+	_, err := io.Copy(dst, reader)
 	errCh <- err
 
 	// Ends the connection with the dst connection peer.
@@ -52,7 +52,7 @@ func TestCloseWrite(t *testing.T) {
 	_, port, err := net.SplitHostPort(backendListener.Addr().String())
 	require.NoError(t, err)
 
-	dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
+	dialer := tcpDialer{Dialer: &net.Dialer{}, terminationDelay: 10 * time.Millisecond}
 
 	proxy, err := NewProxy(":"+port, nil, dialer)
 	require.NoError(t, err)
@@ -88,6 +88,170 @@ func TestCloseWrite(t *testing.T) {
 	require.Equal(t, "PONG", buffer.String())
 }
 
+func TestProxy_Timeouts(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		idleTimeout time.Duration
+		maxLifetime time.Duration
+		activity    bool
+		expectedMin time.Duration
+		expectedMax time.Duration
+	}{
+		{
+			desc:        "idle connection closed",
+			idleTimeout: 100 * time.Millisecond,
+			expectedMin: 100 * time.Millisecond,
+			expectedMax: time.Second,
+		},
+		{
+			desc:        "active connection kept open",
+			idleTimeout: 100 * time.Millisecond,
+			maxLifetime: 500 * time.Millisecond,
+			activity:    true,
+			expectedMin: 500 * time.Millisecond,
+			expectedMax: 1500 * time.Millisecond,
+		},
+		{
+			desc:        "connection exceeding its max lifetime closed",
+			maxLifetime: 200 * time.Millisecond,
+			activity:    true,
+			expectedMin: 200 * time.Millisecond,
+			expectedMax: time.Second,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = backendListener.Close() })
+
+			go func() {
+				conn, err := backendListener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				_, _ = io.Copy(conn, conn)
+			}()
+
+			dialer := tcpDialer{
+				Dialer:           &net.Dialer{},
+				terminationDelay: 10 * time.Millisecond,
+				idleTimeout:      test.idleTimeout,
+				maxLifetime:      test.maxLifetime,
+			}
+
+			proxy, err := NewProxy(backendListener.Addr().String(), nil, dialer)
+			require.NoError(t, err)
+
+			proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = proxyListener.Close() })
+
+			go func() {
+				conn, err := proxyListener.Accept()
+				if err != nil {
+					return
+				}
+				proxy.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			conn, err := net.Dial("tcp", proxyListener.Addr().String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			start := time.Now()
+
+			if test.activity {
+				go func() {
+					for {
+						if _, err := conn.Write([]byte("ping")); err != nil {
+							return
+						}
+						time.Sleep(20 * time.Millisecond)
+					}
+				}()
+			}
+
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+			_, err = io.Copy(io.Discard, conn)
+			require.NoError(t, err)
+
+			elapsed := time.Since(start)
+			assert.GreaterOrEqual(t, elapsed, test.expectedMin)
+			assert.Less(t, elapsed, test.expectedMax)
+		})
+	}
+}
+
+func TestProxy_Timeouts_PeerNotReading(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	backendDone := make(chan struct{})
+	t.Cleanup(func() { close(backendDone) })
+
+	go func() {
+		conn, err := backendListener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// The backend never reads, so that the proxy ends up blocked writing to it.
+		<-backendDone
+	}()
+
+	dialer := tcpDialer{
+		Dialer:           &net.Dialer{},
+		terminationDelay: 10 * time.Millisecond,
+		maxLifetime:      200 * time.Millisecond,
+	}
+
+	proxy, err := NewProxy(backendListener.Addr().String(), nil, dialer)
+	require.NoError(t, err)
+
+	proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = proxyListener.Close() })
+
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+
+		conn, err := proxyListener.Accept()
+		if err != nil {
+			return
+		}
+		proxy.ServeTCP(conn.(*net.TCPConn))
+	}()
+
+	conn, err := net.Dial("tcp", proxyListener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	go func() {
+		payload := make([]byte, 64*1024)
+		for {
+			if _, err := conn.Write(payload); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-served:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the connection blocked writing to the backend was not closed")
+	}
+}
+
 func TestProxyProtocol(t *testing.T) {
 	testCases := []struct {
 		desc    string
@@ -133,7 +297,7 @@ func TestProxyProtocol(t *testing.T) {
 			_, port, err := net.SplitHostPort(proxyBackendListener.Addr().String())
 			require.NoError(t, err)
 
-			dialer := tcpDialer{&net.Dialer{}, 10 * time.Millisecond}
+			dialer := tcpDialer{Dialer: &net.Dialer{}, terminationDelay: 10 * time.Millisecond}
 
 			proxy, err := NewProxy(":"+port, &dynamic.ProxyProtocol{Version: test.version}, dialer)
 			require.NoError(t, err)
@@ -173,3 +337,120 @@ func TestProxyProtocol(t *testing.T) {
 		})
 	}
 }
+
+type addrConn struct {
+	WriteCloser
+	remoteAddr net.Addr
+	localAddr  net.Addr
+}
+
+func (c addrConn) RemoteAddr() net.Addr { return c.remoteAddr }
+
+func (c addrConn) LocalAddr() net.Addr { return c.localAddr }
+
+func TestProxy_proxyProtocolHeader(t *testing.T) {
+	remoteAddr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}
+	localAddr := &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 80}
+	overrideAddr := &net.TCPAddr{IP: net.ParseIP("192.168.0.1"), Port: 4321}
+
+	testCases := []struct {
+		desc            string
+		proxyProtocol   *dynamic.ProxyProtocol
+		target          *Target
+		expectedVersion byte
+		expectedSource  net.Addr
+	}{
+		{
+			desc: "no PROXY protocol",
+		},
+		{
+			desc:            "configured PROXY protocol",
+			proxyProtocol:   &dynamic.ProxyProtocol{Version: 2},
+			expectedVersion: 2,
+			expectedSource:  remoteAddr,
+		},
+		{
+			desc:            "target enables PROXY protocol",
+			target:          &Target{ProxyProtocol: &TargetProxyProtocol{Version: 1, SourceAddr: overrideAddr}},
+			expectedVersion: 1,
+			expectedSource:  overrideAddr,
+		},
+		{
+			desc:          "target disables PROXY protocol",
+			proxyProtocol: &dynamic.ProxyProtocol{Version: 2},
+			target:        &Target{ProxyProtocol: &TargetProxyProtocol{Version: 0}},
+		},
+		{
+			desc:            "target without PROXY protocol override",
+			proxyProtocol:   &dynamic.ProxyProtocol{Version: 1},
+			target:          &Target{ServerAddress: "10.0.0.3:80"},
+			expectedVersion: 1,
+			expectedSource:  remoteAddr,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			proxy, err := NewProxy("10.0.0.3:80", test.proxyProtocol, nil)
+			require.NoError(t, err)
+
+			var conn WriteCloser = addrConn{remoteAddr: remoteAddr, localAddr: localAddr}
+			if test.target != nil {
+				conn = WithTarget(conn, test.target)
+			}
+
+			header := proxy.proxyProtocolHeader(conn)
+			if test.expectedVersion == 0 {
+				assert.Nil(t, header)
+				return
+			}
+
+			require.NotNil(t, header)
+			assert.Equal(t, test.expectedVersion, header.Version)
+			assert.Equal(t, test.expectedSource, header.SourceAddr)
+			assert.Equal(t, localAddr, header.DestinationAddr)
+		})
+	}
+}
+
+func TestProxy_backendAddress(t *testing.T) {
+	localAddr := &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 8000}
+
+	testCases := []struct {
+		desc            string
+		address         string
+		expectedAddress string
+	}{
+		{
+			desc:            "address with a port",
+			address:         "10.0.0.5:80",
+			expectedAddress: "10.0.0.5:80",
+		},
+		{
+			desc:            "address with the port 0",
+			address:         "10.0.0.5:0",
+			expectedAddress: "10.0.0.5:8000",
+		},
+		{
+			desc:            "IPv6 address with the port 0",
+			address:         "[::1]:0",
+			expectedAddress: "[::1]:8000",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			proxy, err := NewProxy(test.address, nil, nil)
+			require.NoError(t, err)
+
+			conn := addrConn{localAddr: localAddr}
+			assert.Equal(t, test.expectedAddress, proxy.backendAddress(conn))
+		})
+	}
+}
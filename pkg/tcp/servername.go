@@ -0,0 +1,34 @@
+package tcp
+
+import (
+	"crypto/tls"
+	"net"
+)
+
+type serverNameConn struct {
+	WriteCloser
+	serverName string
+}
+
+// NetConn returns the underlying connection.
+func (c *serverNameConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// WithServerName returns a connection wrapping conn and carrying the server name sent by the client,
+// for the TLS connections which are not terminated by Traefik.
+func WithServerName(conn WriteCloser, serverName string) WriteCloser {
+	return &serverNameConn{WriteCloser: conn, serverName: serverName}
+}
+
+// GetServerName returns the server name carried by conn or by one of the connections it wraps,
+// or the one of the TLS connection it wraps, if any.
+func GetServerName(conn net.Conn) string {
+	if c, ok := findConn[*serverNameConn](conn); ok {
+		return c.serverName
+	}
+	if c, ok := findConn[*tls.Conn](conn); ok {
+		return c.ConnectionState().ServerName
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package tcp
+
+import "net"
+
+// Target describes where a connection should be forwarded to,
+// as decided before reaching the service (e.g. by a needle).
+// It overrides the choices of the load-balancers and proxies the connection goes through.
+type Target struct {
+	// ServerAddress selects the server with this address among the servers of a load-balancer.
+	ServerAddress string
+	// Service selects the service with this name among the services of a weighted load-balancer.
+	Service string
+	// ProxyProtocol overrides the PROXY protocol header sent to the server.
+	ProxyProtocol *TargetProxyProtocol
+
+	// serviceMatched is set once a weighted load-balancer has honored Service,
+	// so that a nested load-balancer does not report it as unknown.
+	serviceMatched bool
+}
+
+// TargetProxyProtocol describes the PROXY protocol header to send to the server.
+type TargetProxyProtocol struct {
+	// Version of the header, 0 disables it.
+	Version int
+	// SourceAddr replaces the remote address of the connection in the header, if not nil.
+	SourceAddr net.Addr
+	// DestinationAddr replaces the local address of the connection in the header, if not nil.
+	DestinationAddr net.Addr
+}
+
+type targetConn struct {
+	WriteCloser
+	target *Target
+}
+
+// NetConn returns the underlying connection.
+func (c *targetConn) NetConn() net.Conn {
+	return c.WriteCloser
+}
+
+// WithTarget returns a connection wrapping conn and carrying the given target.
+func WithTarget(conn WriteCloser, target *Target) WriteCloser {
+	return &targetConn{WriteCloser: conn, target: target}
+}
+
+// GetTarget returns the target carried by conn or by one of the connections it wraps, if any.
+func GetTarget(conn net.Conn) *Target {
+	if c, ok := findConn[*targetConn](conn); ok {
+		return c.target
+	}
+	return nil
+}
+
+// findConn returns the first connection of type T among conn and the connections it wraps.
+func findConn[T net.Conn](conn net.Conn) (T, bool) {
+	for conn != nil {
+		if c, ok := conn.(T); ok {
+			return c, true
+		}
+
+		unwrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			break
+		}
+		conn = unwrapper.NetConn()
+	}
+
+	var zero T
+	return zero, false
+}
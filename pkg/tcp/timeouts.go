@@ -0,0 +1,152 @@
+package tcp
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// timeoutReason tells why a connection has been closed by its timeouts.
+type timeoutReason int32
+
+const (
+	timeoutReasonNone timeoutReason = iota
+	timeoutReasonIdle
+	timeoutReasonMaxLifetime
+)
+
+// String returns the name of the timeout reason.
+func (r timeoutReason) String() string {
+	switch r {
+	case timeoutReasonIdle:
+		return "idle timeout exceeded"
+	case timeoutReasonMaxLifetime:
+		return "max lifetime exceeded"
+	default:
+		return "closed"
+	}
+}
+
+// connTimeouts enforces the idle timeout and the maximum lifetime of a proxied connection.
+// Once a timeout is exceeded, the reads and writes on both connections are interrupted,
+// so that the copy loops end the connections, even when blocked by a peer which does not read.
+type connTimeouts struct {
+	idleTimeout time.Duration
+	// deadline is the end of the connection lifetime, zero meaning no maximum lifetime.
+	deadline time.Time
+	conns    []WriteCloser
+
+	lastActivity atomic.Int64 // unix nano.
+	reason       atomic.Int32
+
+	mu      sync.Mutex
+	timer   *time.Timer
+	stopped bool
+}
+
+// newConnTimeouts starts enforcing the given timeouts on the connections,
+// and returns nil if there are none.
+func newConnTimeouts(idleTimeout, maxLifetime time.Duration, conns ...WriteCloser) *connTimeouts {
+	if idleTimeout <= 0 && maxLifetime <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	t := &connTimeouts{conns: conns}
+	if idleTimeout > 0 {
+		t.idleTimeout = idleTimeout
+	}
+	if maxLifetime > 0 {
+		t.deadline = now.Add(maxLifetime)
+	}
+	t.lastActivity.Store(now.UnixNano())
+
+	t.mu.Lock()
+	t.timer = time.AfterFunc(t.nextCheck(now), t.check)
+	t.mu.Unlock()
+
+	return t
+}
+
+// Reader returns a reader recording the activity of the connection when data is read from r.
+func (t *connTimeouts) Reader(r io.Reader) io.Reader {
+	return &activityReader{timeouts: t, reader: r}
+}
+
+// CloseReason returns why the timeouts closed the connection.
+func (t *connTimeouts) CloseReason() timeoutReason {
+	return timeoutReason(t.reason.Load())
+}
+
+// Stop releases the resources of the timeouts, without closing the connection.
+func (t *connTimeouts) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stopped = true
+	t.timer.Stop()
+}
+
+// nextCheck returns the duration until the earliest of the timeouts expires.
+func (t *connTimeouts) nextCheck(now time.Time) time.Duration {
+	next := t.deadline
+	if t.idleTimeout > 0 {
+		idleAt := time.Unix(0, t.lastActivity.Load()).Add(t.idleTimeout)
+		if next.IsZero() || idleAt.Before(next) {
+			next = idleAt
+		}
+	}
+	return next.Sub(now)
+}
+
+func (t *connTimeouts) check() {
+	now := time.Now()
+
+	if !t.deadline.IsZero() && !now.Before(t.deadline) {
+		t.expire(timeoutReasonMaxLifetime)
+		return
+	}
+
+	if t.idleTimeout > 0 && now.Sub(time.Unix(0, t.lastActivity.Load())) >= t.idleTimeout {
+		t.expire(timeoutReasonIdle)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.stopped {
+		t.timer.Reset(t.nextCheck(now))
+	}
+}
+
+func (t *connTimeouts) expire(reason timeoutReason) {
+	if !t.reason.CompareAndSwap(int32(timeoutReasonNone), int32(reason)) {
+		return
+	}
+
+	// Interrupting the reads and the writes ends the copy loops,
+	// which then terminate the connections with their peers.
+	for _, conn := range t.conns {
+		if err := conn.SetDeadline(time.Now()); err != nil {
+			log.Debug().Err(err).Msg("Error while setting TCP connection deadline")
+		}
+	}
+}
+
+type activityReader struct {
+	timeouts *connTimeouts
+	reader   io.Reader
+}
+
+func (r *activityReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.timeouts.lastActivity.Store(time.Now().UnixNano())
+	}
+	return n, err
+}
@@ -1,36 +1,147 @@
 package tcp
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	gokitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/balancer"
+)
+
+var (
+	errNoServerToRetry    = errors.New("no other server to retry")
+	errNoAvailableServer  = errors.New("no available server")
+	errUnknownHashKey     = errors.New("unknown hash key")
+	errHashKeyWithoutHash = errors.New("hash key set without the hash strategy")
+)
+
+// HashKey is the key of the connections the hash strategy selects the server from.
+type HashKey string
+
+// Hash keys.
+const (
+	HashKeyClientIP HashKey = "clientip"
+	HashKeySNI      HashKey = "sni"
 )
 
 type server struct {
 	Handler
 	weight int
+
+	// address is the address of the server, when the handler forwards to a single server.
+	address string
+	// service is the name of the service, when the handler is a child service of a weighted service.
+	service string
+	// down is set by the health checks, in which case the server does not get connections from the load-balancing.
+	down bool
+	// active is the number of connections the server is handling.
+	active *atomic.Int64
+}
+
+// handle forwards the connection to the server, counting it as active until it is done.
+func (s server) handle(conn WriteCloser) {
+	s.active.Add(1)
+	defer s.active.Add(-1)
+
+	s.ServeTCP(conn)
+}
+
+// name returns the name identifying the server in the health checks.
+func (s server) name() string {
+	if s.address != "" {
+		return s.address
+	}
+	return s.service
 }
 
-// WRRLoadBalancer is a naive RoundRobin load balancer for TCP services.
+// serverList is the view of the servers the balancing strategies select from.
+type serverList []server
+
+func (l serverList) Len() int             { return len(l) }
+func (l serverList) Name(i int) string    { return l[i].name() }
+func (l serverList) Weight(i int) int     { return l[i].weight }
+func (l serverList) Available(i int) bool { return !l[i].down && l[i].weight > 0 }
+func (l serverList) Active(i int) int64   { return l[i].active.Load() }
+func (l serverList) Address(i int) string { return l[i].address }
+func (l serverList) Service(i int) string { return l[i].service }
+
+// WRRLoadBalancer is a load balancer for TCP services,
+// which uses a naive weighted RoundRobin unless another balancing strategy is set.
 type WRRLoadBalancer struct {
 	servers       []server
 	lock          sync.Mutex
 	currentWeight int
 	index         int
+
+	strategy balancer.Strategy
+	hashKey  HashKey
+	// ring is built from the servers on the first connection, when using the hash strategy.
+	ring *balancer.Ring
+	rand *rand.Rand
+
+	// retryAttempts is the maximum number of servers dialed for a connection.
+	retryAttempts  int
+	retriesCounter gokitmetrics.Counter
+
+	wantsHealthCheck bool
+	// updaters is the list of hooks that are run (to update the parent(s) of the load-balancer),
+	// whenever its status changes.
+	updaters []func(bool)
 }
 
 // NewWRRLoadBalancer creates a new WRRLoadBalancer.
 func NewWRRLoadBalancer() *WRRLoadBalancer {
 	return &WRRLoadBalancer{
-		index: -1,
+		index:    -1,
+		strategy: balancer.StrategyWRR,
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// SetStrategy sets the balancing strategy, and the key of the connections for the hash strategy,
+// which defaults to the client IP.
+func (b *WRRLoadBalancer) SetStrategy(strategy balancer.Strategy, hashKey HashKey) error {
+	switch {
+	case hashKey != "" && strategy != balancer.StrategyHash:
+		return errHashKeyWithoutHash
+	case hashKey == "":
+		hashKey = HashKeyClientIP
+	case hashKey != HashKeyClientIP && hashKey != HashKeySNI:
+		return fmt.Errorf("%w: %s", errUnknownHashKey, hashKey)
 	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.strategy = strategy
+	b.hashKey = hashKey
+	b.ring = nil
+	return nil
 }
 
 // ServeTCP forwards the connection to the right service.
 func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
+	if target := GetTarget(conn); target != nil {
+		if next, ok := b.targeted(target); ok {
+			next.handle(conn)
+			return
+		}
+	}
+
+	if b.retryAttempts > 1 {
+		b.serveWithRetry(conn)
+		return
+	}
+
 	b.lock.Lock()
-	next, err := b.next()
+	next, err := b.next(conn)
 	b.lock.Unlock()
 
 	if err != nil {
@@ -39,7 +150,112 @@ func (b *WRRLoadBalancer) ServeTCP(conn WriteCloser) {
 		return
 	}
 
-	next.ServeTCP(conn)
+	next.handle(conn)
+}
+
+// EnableHealthCheck makes the load-balancer report its status to its parents,
+// the load-balancer being down when all its servers are down.
+func (b *WRRLoadBalancer) EnableHealthCheck() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.wantsHealthCheck = true
+}
+
+// RegisterStatusUpdater adds fn to the list of hooks that are run when the
+// status of the load-balancer changes.
+// Not thread safe.
+func (b *WRRLoadBalancer) RegisterStatusUpdater(fn func(up bool)) error {
+	if !b.wantsHealthCheck {
+		return errors.New("healthCheck not enabled in config for this service")
+	}
+
+	b.updaters = append(b.updaters, fn)
+
+	return nil
+}
+
+// SetRetry makes the load-balancer dial up to the given number of servers for each connection,
+// trying the next server as long as the dial fails, each retry being counted by the given counter.
+// Only the servers forwarding to a single address, added with AddServerWithAddress, are retried.
+func (b *WRRLoadBalancer) SetRetry(attempts int, retriesCounter gokitmetrics.Counter) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.retryAttempts = attempts
+	b.retriesCounter = retriesCounter
+}
+
+// serveWithRetry forwards the connection to the first server which can be dialed,
+// before any byte is forwarded to it.
+func (b *WRRLoadBalancer) serveWithRetry(conn WriteCloser) {
+	tried := make(map[int]struct{})
+
+	for attempt := 1; attempt <= b.retryAttempts; attempt++ {
+		b.lock.Lock()
+		var srv server
+		var err error
+		if attempt == 1 {
+			srv, err = b.next(conn)
+		} else {
+			srv, err = b.nextUntried(tried)
+		}
+		index := b.index
+		b.lock.Unlock()
+
+		if err != nil {
+			log.Error().Err(err).Int("attempt", attempt).Msg("Error during load balancing")
+			conn.Close()
+			return
+		}
+
+		proxy, ok := srv.Handler.(*Proxy)
+		if !ok {
+			srv.handle(conn)
+			return
+		}
+
+		connBackend, err := proxy.dial(conn)
+		if err == nil {
+			srv.active.Add(1)
+			defer srv.active.Add(-1)
+
+			proxy.serve(conn, connBackend)
+			return
+		}
+
+		tried[index] = struct{}{}
+
+		log.Warn().Err(err).
+			Str("serverAddress", srv.address).
+			Int("attempt", attempt).
+			Msg("Error while dialing backend")
+
+		if attempt < b.retryAttempts {
+			b.retriesCounter.Add(1)
+		}
+	}
+
+	log.Error().Msgf("Error while dialing backends, giving up after %d attempts", b.retryAttempts)
+	conn.Close()
+}
+
+// nextUntried returns the first server available after the current one, which has not been tried yet.
+func (b *WRRLoadBalancer) nextUntried(tried map[int]struct{}) (server, error) {
+	for i := 1; i <= len(b.servers); i++ {
+		index := (b.index + i) % len(b.servers)
+		if _, ok := tried[index]; ok {
+			continue
+		}
+
+		srv := b.servers[index]
+		if !srv.down && srv.weight > 0 {
+			b.index = index
+			return srv, nil
+		}
+	}
+
+	return server{}, errNoServerToRetry
 }
 
 // AddServer appends a server to the existing list.
@@ -50,20 +266,105 @@ func (b *WRRLoadBalancer) AddServer(serverHandler Handler) {
 
 // AddWeightServer appends a server to the existing list with a weight.
 func (b *WRRLoadBalancer) AddWeightServer(serverHandler Handler, weight *int) {
+	b.addServer(server{Handler: serverHandler}, weight)
+}
+
+// AddServerWithAddress appends a server to the existing list,
+// which can be selected by a Target with the given address.
+func (b *WRRLoadBalancer) AddServerWithAddress(address string, serverHandler Handler) {
+	w := 1
+	b.AddWeightServerWithAddress(address, serverHandler, &w)
+}
+
+// AddWeightServerWithAddress appends a server to the existing list with a weight,
+// which can be selected by a Target with the given address.
+func (b *WRRLoadBalancer) AddWeightServerWithAddress(address string, serverHandler Handler, weight *int) {
+	b.addServer(server{Handler: serverHandler, address: address}, weight)
+}
+
+// AddWeightServerWithName appends a service to the existing list with a weight,
+// which can be selected by a Target with the given service name.
+func (b *WRRLoadBalancer) AddWeightServerWithName(name string, serverHandler Handler, weight *int) {
+	b.addServer(server{Handler: serverHandler, service: name}, weight)
+}
+
+// SetStatus sets the status of the server with the given address, or of the child service with the given name.
+// The servers are up until they are set down.
+func (b *WRRLoadBalancer) SetStatus(ctx context.Context, childName string, up bool) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	w := 1
+	status := "DOWN"
+	if up {
+		status = "UP"
+	}
+	log.Ctx(ctx).Debug().Msgf("Setting status of %s to %v", childName, status)
+
+	upBefore := b.up()
+
+	for i := range b.servers {
+		if b.servers[i].name() == childName {
+			b.servers[i].down = !up
+		}
+	}
+
+	upAfter := b.up()
+	status = "DOWN"
+	if upAfter {
+		status = "UP"
+	}
+
+	if upBefore == upAfter {
+		// We're still with the same status, no need to propagate.
+		log.Ctx(ctx).Debug().Msgf("Still %s, no need to propagate", status)
+		return
+	}
+
+	log.Ctx(ctx).Debug().Msgf("Propagating new %s status", status)
+	for _, fn := range b.updaters {
+		fn(upAfter)
+	}
+}
+
+// up reports whether one of the servers is up.
+func (b *WRRLoadBalancer) up() bool {
+	for _, srv := range b.servers {
+		if !srv.down {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *WRRLoadBalancer) addServer(srv server, weight *int) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	srv.weight = 1
 	if weight != nil {
-		w = *weight
+		srv.weight = *weight
+	}
+	srv.active = &atomic.Int64{}
+	b.servers = append(b.servers, srv)
+	b.ring = nil
+}
+
+// targeted returns the server selected by the target, or false to fall back to the load-balancing.
+func (b *WRRLoadBalancer) targeted(target *Target) (server, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	i, ok := balancer.Targeted(serverList(b.servers), target.Service, target.ServerAddress, &target.serviceMatched)
+	if !ok {
+		return server{}, false
 	}
-	b.servers = append(b.servers, server{Handler: serverHandler, weight: w})
+	return b.servers[i], true
 }
 
 func (b *WRRLoadBalancer) maxWeight() int {
 	max := -1
 	for _, s := range b.servers {
-		if s.weight > max {
+		if !s.down && s.weight > max {
 			max = s.weight
 		}
 	}
@@ -73,6 +374,9 @@ func (b *WRRLoadBalancer) maxWeight() int {
 func (b *WRRLoadBalancer) weightGcd() int {
 	divisor := -1
 	for _, s := range b.servers {
+		if s.down {
+			continue
+		}
 		if divisor == -1 {
 			divisor = s.weight
 		} else {
@@ -89,19 +393,63 @@ func gcd(a, b int) int {
 	return a
 }
 
-func (b *WRRLoadBalancer) next() (Handler, error) {
+// next returns the server of the connection, selected with the balancing strategy.
+func (b *WRRLoadBalancer) next(conn WriteCloser) (server, error) {
 	if len(b.servers) == 0 {
-		return nil, fmt.Errorf("no servers in the pool")
+		return server{}, fmt.Errorf("no servers in the pool")
+	}
+
+	var index int
+	var ok bool
+	switch b.strategy {
+	case balancer.StrategyLeastConn:
+		index, ok = balancer.LeastConn(serverList(b.servers), b.index+1)
+	case balancer.StrategyP2C:
+		index, ok = balancer.P2C(serverList(b.servers), b.rand)
+	case balancer.StrategyHash:
+		if b.ring == nil {
+			b.ring = balancer.NewRing(serverList(b.servers))
+		}
+		index, ok = b.ring.Pick(serverList(b.servers), b.connKey(conn))
+	default:
+		return b.nextWRR()
 	}
 
+	if !ok {
+		return server{}, errNoAvailableServer
+	}
+	b.index = index
+	return b.servers[index], nil
+}
+
+// connKey returns the key of the connection for the hash strategy,
+// which falls back to the client IP when there is no SNI.
+func (b *WRRLoadBalancer) connKey(conn WriteCloser) string {
+	if b.hashKey == HashKeySNI {
+		if serverName := GetServerName(conn); serverName != "" {
+			return serverName
+		}
+	}
+
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return conn.RemoteAddr().String()
+	}
+	return host
+}
+
+func (b *WRRLoadBalancer) nextWRR() (server, error) {
 	// The algo below may look messy, but is actually very simple
 	// it calculates the GCD  and subtracts it on every iteration, what interleaves servers
 	// and allows us not to build an iterator every time we readjust weights
 
 	// Maximum weight across all enabled servers
 	max := b.maxWeight()
+	if max == -1 {
+		return server{}, fmt.Errorf("all servers are down")
+	}
 	if max == 0 {
-		return nil, fmt.Errorf("all servers have 0 weight")
+		return server{}, fmt.Errorf("all servers have 0 weight")
 	}
 
 	// GCD across all enabled servers
@@ -116,7 +464,7 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 			}
 		}
 		srv := b.servers[b.index]
-		if srv.weight >= b.currentWeight {
+		if !srv.down && srv.weight >= b.currentWeight {
 			return srv, nil
 		}
 	}
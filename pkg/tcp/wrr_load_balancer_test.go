@@ -1,17 +1,22 @@
 package tcp
 
 import (
+	"context"
+	"io"
 	"net"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/traefik/v3/pkg/balancer"
 )
 
 type fakeConn struct {
-	writeCall map[string]int
-	closeCall int
+	writeCall  map[string]int
+	closeCall  int
+	remoteAddr net.Addr
 }
 
 func (f *fakeConn) Read(b []byte) (n int, err error) {
@@ -33,6 +38,9 @@ func (f *fakeConn) LocalAddr() net.Addr {
 }
 
 func (f *fakeConn) RemoteAddr() net.Addr {
+	if f.remoteAddr != nil {
+		return f.remoteAddr
+	}
 	panic("implement me")
 }
 
@@ -144,3 +152,328 @@ func TestLoadBalancing(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadBalancing_Target(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		target        *Target
+		totalCall     int
+		expectedWrite map[string]int
+	}{
+		{
+			desc:      "no target",
+			totalCall: 4,
+			expectedWrite: map[string]int{
+				"svc1/h1": 1,
+				"svc1/h2": 1,
+				"svc2/h1": 1,
+				"svc2/h2": 1,
+			},
+		},
+		{
+			desc:      "target service",
+			target:    &Target{Service: "svc2"},
+			totalCall: 4,
+			expectedWrite: map[string]int{
+				"svc2/h1": 2,
+				"svc2/h2": 2,
+			},
+		},
+		{
+			desc:      "target service and server address",
+			target:    &Target{Service: "svc2", ServerAddress: "svc2/h2"},
+			totalCall: 4,
+			expectedWrite: map[string]int{
+				"svc2/h2": 4,
+			},
+		},
+		{
+			desc:      "unknown service falls back to load balancing",
+			target:    &Target{Service: "svc3"},
+			totalCall: 4,
+			expectedWrite: map[string]int{
+				"svc1/h1": 1,
+				"svc1/h2": 1,
+				"svc2/h1": 1,
+				"svc2/h2": 1,
+			},
+		},
+		{
+			desc:      "unknown server address falls back to load balancing",
+			target:    &Target{Service: "svc1", ServerAddress: "svc2/h1"},
+			totalCall: 4,
+			expectedWrite: map[string]int{
+				"svc1/h1": 2,
+				"svc1/h2": 2,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			balancer := NewWRRLoadBalancer()
+			for _, service := range []string{"svc1", "svc2"} {
+				serviceBalancer := NewWRRLoadBalancer()
+				for _, server := range []string{"h1", "h2"} {
+					address := service + "/" + server
+					serviceBalancer.AddServerWithAddress(address, HandlerFunc(func(conn WriteCloser) {
+						_, err := conn.Write([]byte(address))
+						require.NoError(t, err)
+					}))
+				}
+				balancer.AddWeightServerWithName(service, serviceBalancer, nil)
+			}
+
+			fake := &fakeConn{writeCall: make(map[string]int)}
+			for i := 0; i < test.totalCall; i++ {
+				var conn WriteCloser = fake
+				if test.target != nil {
+					target := *test.target
+					conn = WithTarget(fake, &target)
+				}
+				balancer.ServeTCP(conn)
+			}
+
+			assert.Equal(t, test.expectedWrite, fake.writeCall)
+		})
+	}
+}
+
+func TestLoadBalancing_SetStatus(t *testing.T) {
+	balancer := NewWRRLoadBalancer()
+	for _, address := range []string{"h1", "h2"} {
+		address := address
+		balancer.AddServerWithAddress(address, HandlerFunc(func(conn WriteCloser) {
+			_, err := conn.Write([]byte(address))
+			require.NoError(t, err)
+		}))
+	}
+
+	conn := &fakeConn{writeCall: make(map[string]int)}
+
+	balancer.SetStatus(context.Background(), "h1", false)
+	for i := 0; i < 4; i++ {
+		balancer.ServeTCP(conn)
+	}
+	assert.Equal(t, map[string]int{"h2": 4}, conn.writeCall)
+
+	// The servers which are down can still be targeted.
+	targetedConn := &fakeConn{writeCall: make(map[string]int)}
+	balancer.ServeTCP(WithTarget(targetedConn, &Target{ServerAddress: "h1"}))
+	assert.Equal(t, map[string]int{"h1": 1}, targetedConn.writeCall)
+
+	balancer.SetStatus(context.Background(), "h2", false)
+	balancer.ServeTCP(conn)
+	assert.Equal(t, 1, conn.closeCall)
+
+	balancer.SetStatus(context.Background(), "h1", true)
+	balancer.SetStatus(context.Background(), "h2", true)
+	conn = &fakeConn{writeCall: make(map[string]int)}
+	for i := 0; i < 4; i++ {
+		balancer.ServeTCP(conn)
+	}
+	assert.Equal(t, map[string]int{"h1": 2, "h2": 2}, conn.writeCall)
+}
+
+func TestLoadBalancing_Retry(t *testing.T) {
+	backendListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = backendListener.Close() })
+
+	go func() {
+		for {
+			conn, err := backendListener.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("hello"))
+			_ = conn.Close()
+		}
+	}()
+
+	// The servers are tried in order, the first ones being closed.
+	var addresses []string
+	for i := 0; i < 2; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addresses = append(addresses, listener.Addr().String())
+		require.NoError(t, listener.Close())
+	}
+	addresses = append(addresses, backendListener.Addr().String())
+
+	testCases := []struct {
+		desc            string
+		attempts        int
+		expected        string
+		expectedRetries float64
+	}{
+		{
+			desc:     "no retry",
+			attempts: 1,
+		},
+		{
+			desc:            "not enough attempts",
+			attempts:        2,
+			expectedRetries: 1,
+		},
+		{
+			desc:            "next servers",
+			attempts:        3,
+			expected:        "hello",
+			expectedRetries: 2,
+		},
+		{
+			desc:            "more attempts than servers",
+			attempts:        5,
+			expected:        "hello",
+			expectedRetries: 2,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dialer := tcpDialer{Dialer: &net.Dialer{}, terminationDelay: 10 * time.Millisecond}
+
+			balancer := NewWRRLoadBalancer()
+			for _, address := range addresses {
+				proxy, err := NewProxy(address, nil, dialer)
+				require.NoError(t, err)
+				balancer.AddServerWithAddress(address, proxy)
+			}
+
+			retries := generic.NewCounter("retries")
+			balancer.SetRetry(test.attempts, retries)
+
+			proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = proxyListener.Close() })
+
+			go func() {
+				conn, err := proxyListener.Accept()
+				if err != nil {
+					return
+				}
+				balancer.ServeTCP(conn.(*net.TCPConn))
+			}()
+
+			conn, err := net.Dial("tcp", proxyListener.Addr().String())
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			received, err := io.ReadAll(conn)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(received))
+			assert.Equal(t, test.expectedRetries, retries.Value())
+		})
+	}
+}
+
+func TestLoadBalancing_LeastConn(t *testing.T) {
+	loadBalancer := NewWRRLoadBalancer()
+	require.NoError(t, loadBalancer.SetStrategy(balancer.StrategyLeastConn, ""))
+
+	// The connections to h1 are held until released.
+	release := make(chan struct{})
+	loadBalancer.AddServerWithAddress("h1", HandlerFunc(func(conn WriteCloser) {
+		_, _ = conn.Write([]byte("h1"))
+		<-release
+	}))
+	loadBalancer.AddServerWithAddress("h2", HandlerFunc(func(conn WriteCloser) {
+		_, _ = conn.Write([]byte("h2"))
+	}))
+
+	heldConn := &fakeConn{writeCall: make(map[string]int)}
+	held := make(chan struct{})
+	go func() {
+		defer close(held)
+		loadBalancer.ServeTCP(heldConn)
+	}()
+	require.Eventually(t, func() bool { return loadBalancer.servers[0].active.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	// h1 has an active connection, so the new connections go to h2.
+	conn := &fakeConn{writeCall: make(map[string]int)}
+	for i := 0; i < 4; i++ {
+		loadBalancer.ServeTCP(conn)
+	}
+	assert.Equal(t, map[string]int{"h2": 4}, conn.writeCall)
+
+	close(release)
+	<-held
+	assert.Equal(t, map[string]int{"h1": 1}, heldConn.writeCall)
+}
+
+func TestLoadBalancing_Hash(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		hashKey   HashKey
+		expectErr bool
+	}{
+		{
+			desc: "client IP",
+		},
+		{
+			desc:    "SNI",
+			hashKey: HashKeySNI,
+		},
+		{
+			desc:      "unknown hash key",
+			hashKey:   "port",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			loadBalancer := NewWRRLoadBalancer()
+			err := loadBalancer.SetStrategy(balancer.StrategyHash, test.hashKey)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			for _, address := range []string{"h1", "h2", "h3"} {
+				address := address
+				loadBalancer.AddServerWithAddress(address, HandlerFunc(func(conn WriteCloser) {
+					_, _ = conn.Write([]byte(address))
+				}))
+			}
+
+			// The connections with the same key, from different ports, go to the same server.
+			servers := make(map[string]struct{})
+			for i := 0; i < 20; i++ {
+				// With the SNI, the connections come from different clients.
+				remoteAddr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1000 + i}
+				if test.hashKey == HashKeySNI {
+					remoteAddr = &net.TCPAddr{IP: net.IPv4(10, 0, 0, byte(i)), Port: 1000}
+				}
+
+				fake := &fakeConn{writeCall: make(map[string]int), remoteAddr: remoteAddr}
+				var conn WriteCloser = fake
+				if test.hashKey == HashKeySNI {
+					conn = WithServerName(conn, "foo.localhost")
+				}
+
+				loadBalancer.ServeTCP(conn)
+
+				for server := range fake.writeCall {
+					servers[server] = struct{}{}
+				}
+			}
+			assert.Len(t, servers, 1)
+		})
+	}
+
+	loadBalancer := NewWRRLoadBalancer()
+	assert.Error(t, loadBalancer.SetStrategy(balancer.StrategyP2C, HashKeySNI))
+}
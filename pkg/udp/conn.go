@@ -6,6 +6,9 @@ import (
 	"net"
 	"sync"
 	"time"
+
+	"github.com/traefik/traefik/v3/pkg/needleware"
+	"github.com/traefik/traefik/v3/pkg/shaping"
 )
 
 // maxDatagramSize is the maximum size of a UDP datagram.
@@ -193,6 +196,9 @@ func (l *Listener) newConn(rAddr net.Addr) *Conn {
 type Conn struct {
 	listener *Listener
 	rAddr    net.Addr
+	target   *Target             // where the session should be forwarded to, if decided before the load-balancing
+	limits   *shaping.Limits     // enforced by the proxy forwarding the session
+	traffic  *needleware.Traffic // counts the bytes of the session, if set by a needle
 
 	receiveCh chan []byte // to receive the data from the listener's readLoop
 	readCh    chan []byte // to receive the buffer into which we should Read
@@ -262,6 +268,9 @@ func (c *Conn) Read(p []byte) (int, error) {
 		c.muActivity.Lock()
 		c.lastActivity = time.Now()
 		c.muActivity.Unlock()
+		if c.traffic != nil {
+			c.traffic.AddUploaded(n)
+		}
 		return n, nil
 
 	case <-c.doneCh:
@@ -277,7 +286,11 @@ func (c *Conn) Write(p []byte) (n int, err error) {
 	c.lastActivity = time.Now()
 	c.muActivity.Unlock()
 
-	return c.listener.pConn.WriteTo(p, c.rAddr)
+	n, err = c.listener.pConn.WriteTo(p, c.rAddr)
+	if c.traffic != nil {
+		c.traffic.AddDownloaded(n)
+	}
+	return n, err
 }
 
 func (c *Conn) close() {
@@ -295,3 +308,8 @@ func (c *Conn) Close() error {
 	delete(c.listener.conns, c.rAddr.String())
 	return nil
 }
+
+// LocalAddr returns the address of the listener which accepted the Conn.
+func (c *Conn) LocalAddr() net.Addr {
+	return c.listener.Addr()
+}
@@ -0,0 +1,13 @@
+package udp
+
+import "github.com/traefik/traefik/v3/pkg/shaping"
+
+// SetLimits sets the limits the proxy should enforce on the session.
+func (c *Conn) SetLimits(limits *shaping.Limits) {
+	c.limits = limits
+}
+
+// Limits returns the limits the proxy should enforce on the session, if any.
+func (c *Conn) Limits() *shaping.Limits {
+	return c.limits
+}
@@ -0,0 +1,62 @@
+package udp
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/logs"
+	"github.com/traefik/traefik/v3/pkg/needleware"
+)
+
+type needleHandler struct {
+	next   Handler
+	needle needleware.Needle
+}
+
+// NewNeedleHandler creates a handler asking the needle whether a session should be forwarded to next.
+// It runs before the load-balancing so that the decision can select the server or service to use.
+func NewNeedleHandler(next Handler, needle needleware.Needle) Handler {
+	return &needleHandler{next: next, needle: needle}
+}
+
+// ServeUDP implements the Handler interface.
+func (h *needleHandler) ServeUDP(conn *Conn) {
+	remoteAddr := conn.rAddr.String()
+	localAddr := conn.listener.Addr().String()
+
+	criteria, err := h.needle.NewUDPCriteria(remoteAddr, localAddr)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to create criteria when serving UDP connection from %s to %s", remoteAddr, localAddr)
+		h.next.ServeUDP(conn)
+		return
+	}
+
+	// wait until the decision is made
+	decision, err := h.needle.Decide(criteria)
+	if err != nil {
+		log.Error().Err(err).Str(logs.ConnID, criteria.ConnUID).Msgf("Failed to decide on UDP connection from %s to %s", remoteAddr, localAddr)
+		conn.Close()
+		return
+	}
+	defer h.needle.OnConnClose(decision)
+	if decision.ConnRejected() {
+		conn.Close()
+		return
+	}
+	decision.OnLateReject(func() { conn.Close() })
+	conn.traffic = &decision.Traffic
+
+	if limits := decision.NewShapingLimits(); limits != nil {
+		conn.SetLimits(limits)
+		defer func() { decision.SetShapingCloseReason(limits.CloseReason()) }()
+	}
+
+	if routing := decision.Target; routing != nil {
+		if routing.ProxyProtocol != nil {
+			log.Debug().Str(logs.ConnID, criteria.ConnUID).Msgf("Ignoring PROXY protocol override for UDP connection from %s", remoteAddr)
+		}
+		if routing.ServerAddress != "" || routing.Service != "" {
+			conn.SetTarget(&Target{ServerAddress: routing.ServerAddress, Service: routing.Service})
+		}
+	}
+
+	h.next.ServeUDP(conn)
+}
@@ -1,23 +1,28 @@
 package udp
 
 import (
-	"github.com/traefik/traefik/v3/pkg/needleware"
 	"io"
 	"net"
+	"strconv"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/shaping"
 )
 
 // Proxy is a reverse-proxy implementation of the Handler interface.
 type Proxy struct {
 	// TODO: maybe optimize by pre-resolving it at proxy creation time
 	target string
-	needle needleware.Needle
+	// samePort is set when the port of the target is 0,
+	// in which case the stream is forwarded to the port on which it was received.
+	samePort bool
 }
 
 // NewProxy creates a new Proxy.
-func NewProxy(address string, needle needleware.Needle) (*Proxy, error) {
-	return &Proxy{target: address, needle: needle}, nil
+func NewProxy(address string) (*Proxy, error) {
+	_, port, err := net.SplitHostPort(address)
+
+	return &Proxy{target: address, samePort: err == nil && port == "0"}, nil
 }
 
 // ServeUDP implements the Handler interface.
@@ -27,25 +32,7 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	// needed because of e.g. server.trackedConnection
 	defer conn.Close()
 
-	if p.needle != nil {
-		remoteAddr := conn.rAddr.String()
-		localAddr := p.target
-
-		criteria, err := p.needle.NewUDPCriteria(remoteAddr, localAddr)
-		if err == nil {
-			// wait until the decision is made
-			decision, _ := p.needle.Decide(criteria)
-			defer p.needle.OnConnClose(decision)
-			if decision.ConnRejected() {
-				conn.Close()
-				return
-			}
-		} else {
-			log.Error().Err(err).Msgf("Failed to create criteria when serving UDP connection from %s to %s", remoteAddr, localAddr)
-		}
-	}
-
-	connBackend, err := net.Dial("udp", p.target)
+	connBackend, err := net.Dial("udp", p.backendAddress(conn))
 	if err != nil {
 		log.Error().Err(err).Msg("Error while dialing backend")
 		return
@@ -54,23 +41,54 @@ func (p *Proxy) ServeUDP(conn *Conn) {
 	// maybe not needed, but just in case
 	defer connBackend.Close()
 
+	var session *shaping.Session
+	if limits := conn.Limits(); limits != nil && !limits.IsZero() {
+		session = shaping.NewSession(limits, maxDatagramSize, func() {
+			conn.Close()
+			connBackend.Close()
+		})
+		defer session.Stop()
+	}
+
 	errChan := make(chan error)
-	go connCopy(conn, connBackend, errChan)
-	go connCopy(connBackend, conn, errChan)
+	go connCopy(conn, connBackend, session, shaping.Download, errChan)
+	go connCopy(connBackend, conn, session, shaping.Upload, errChan)
 
 	err = <-errChan
-	if err != nil {
+	if session != nil && session.CloseReason() != shaping.CloseReasonNone {
+		log.Debug().Msgf("Closing UDP stream from %s to %s: %s", conn.rAddr, p.target, session.CloseReason())
+	} else if err != nil {
 		log.Error().Err(err).Msg("Error while handling UDP stream")
 	}
 
 	<-errChan
 }
 
-func connCopy(dst io.WriteCloser, src io.Reader, errCh chan error) {
+// backendAddress returns the address of the backend to forward the stream to,
+// which has the port on which the stream was received if the target has the port 0.
+func (p *Proxy) backendAddress(conn *Conn) string {
+	if !p.samePort {
+		return p.target
+	}
+
+	localAddr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return p.target
+	}
+
+	host, _, _ := net.SplitHostPort(p.target)
+	return net.JoinHostPort(host, strconv.Itoa(localAddr.Port))
+}
+
+func connCopy(dst io.WriteCloser, src io.Reader, session *shaping.Session, direction shaping.Direction, errCh chan error) {
 	// The buffer is initialized to the maximum UDP datagram size,
 	// to make sure that the whole UDP datagram is read or written atomically (no data is discarded).
 	buffer := make([]byte, maxDatagramSize)
 
+	if session != nil {
+		src = session.Reader(src, direction)
+	}
+
 	_, err := io.CopyBuffer(dst, src, buffer)
 	errCh <- err
 
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"net"
 	"runtime"
+	"strconv"
 	"testing"
 	"time"
 
@@ -109,3 +110,44 @@ func newServer(t *testing.T, addr string, handler Handler) {
 		go handler.ServeUDP(conn)
 	}
 }
+
+func TestProxy_backendAddress(t *testing.T) {
+	addr, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	listener, err := Listen("udp", addr, 3*time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	port := listener.Addr().(*net.UDPAddr).Port
+	conn := &Conn{listener: listener}
+
+	testCases := []struct {
+		desc            string
+		address         string
+		expectedAddress string
+	}{
+		{
+			desc:            "address with a port",
+			address:         "10.0.0.5:80",
+			expectedAddress: "10.0.0.5:80",
+		},
+		{
+			desc:            "address with the port 0",
+			address:         "10.0.0.5:0",
+			expectedAddress: net.JoinHostPort("10.0.0.5", strconv.Itoa(port)),
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			proxy, err := NewProxy(test.address)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedAddress, proxy.backendAddress(conn))
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package udp
+
+// Target describes where a session should be forwarded to,
+// as decided before reaching the load-balancers (e.g. by a needle).
+type Target struct {
+	// ServerAddress selects the server with this address among the servers of a load-balancer.
+	ServerAddress string
+	// Service selects the service with this name among the services of a weighted load-balancer.
+	Service string
+
+	// serviceMatched is set once a weighted load-balancer has honored Service,
+	// so that a nested load-balancer does not report it as unknown.
+	serviceMatched bool
+}
+
+// SetTarget sets the target the session should be forwarded to.
+func (c *Conn) SetTarget(target *Target) {
+	c.target = target
+}
+
+// Target returns the target the session should be forwarded to, if any.
+func (c *Conn) Target() *Target {
+	return c.target
+}
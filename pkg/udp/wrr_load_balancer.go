@@ -1,36 +1,99 @@
 package udp
 
 import (
+	"errors"
 	"fmt"
+	"math/rand"
+	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/traefik/v3/pkg/balancer"
 )
 
+var errNoAvailableServer = errors.New("no available server")
+
 type server struct {
 	Handler
 	weight int
+
+	// address is the address of the server, when the handler forwards to a single server.
+	address string
+	// service is the name of the service, when the handler is a child service of a weighted service.
+	service string
+	// active is the number of sessions the server is handling.
+	active *atomic.Int64
+}
+
+// handle forwards the session to the server, counting it as active until it is done.
+func (s server) handle(conn *Conn) {
+	s.active.Add(1)
+	defer s.active.Add(-1)
+
+	s.ServeUDP(conn)
 }
 
-// WRRLoadBalancer is a naive RoundRobin load balancer for UDP services.
+// serverList is the view of the servers the balancing strategies select from.
+type serverList []server
+
+func (l serverList) Len() int { return len(l) }
+func (l serverList) Name(i int) string {
+	if l[i].address != "" {
+		return l[i].address
+	}
+	return l[i].service
+}
+func (l serverList) Weight(i int) int     { return l[i].weight }
+func (l serverList) Available(i int) bool { return l[i].weight > 0 }
+func (l serverList) Active(i int) int64   { return l[i].active.Load() }
+func (l serverList) Address(i int) string { return l[i].address }
+func (l serverList) Service(i int) string { return l[i].service }
+
+// WRRLoadBalancer is a load balancer for UDP services,
+// which uses a naive weighted RoundRobin unless another balancing strategy is set.
 type WRRLoadBalancer struct {
 	servers       []server
 	lock          sync.Mutex
 	currentWeight int
 	index         int
+
+	strategy balancer.Strategy
+	// ring is built from the servers on the first session, when using the hash strategy.
+	ring *balancer.Ring
+	rand *rand.Rand
 }
 
 // NewWRRLoadBalancer creates a new WRRLoadBalancer.
 func NewWRRLoadBalancer() *WRRLoadBalancer {
 	return &WRRLoadBalancer{
-		index: -1,
+		index:    -1,
+		strategy: balancer.StrategyWRR,
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
+// SetStrategy sets the balancing strategy, the hash strategy hashing the client IP of the sessions.
+func (b *WRRLoadBalancer) SetStrategy(strategy balancer.Strategy) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.strategy = strategy
+	b.ring = nil
+}
+
 // ServeUDP forwards the connection to the right service.
 func (b *WRRLoadBalancer) ServeUDP(conn *Conn) {
+	if target := conn.Target(); target != nil {
+		if next, ok := b.targeted(target); ok {
+			next.handle(conn)
+			return
+		}
+	}
+
 	b.lock.Lock()
-	next, err := b.next()
+	next, err := b.next(conn)
 	b.lock.Unlock()
 
 	if err != nil {
@@ -39,7 +102,7 @@ func (b *WRRLoadBalancer) ServeUDP(conn *Conn) {
 		return
 	}
 
-	next.ServeUDP(conn)
+	next.handle(conn)
 }
 
 // AddServer appends a handler to the existing list.
@@ -50,14 +113,45 @@ func (b *WRRLoadBalancer) AddServer(serverHandler Handler) {
 
 // AddWeightedServer appends a handler to the existing list with a weight.
 func (b *WRRLoadBalancer) AddWeightedServer(serverHandler Handler, weight *int) {
+	b.addServer(server{Handler: serverHandler}, weight)
+}
+
+// AddServerWithAddress appends a handler to the existing list,
+// which can be selected by a Target with the given address.
+func (b *WRRLoadBalancer) AddServerWithAddress(address string, serverHandler Handler) {
+	w := 1
+	b.addServer(server{Handler: serverHandler, address: address}, &w)
+}
+
+// AddWeightedServerWithName appends a service handler to the existing list with a weight,
+// which can be selected by a Target with the given service name.
+func (b *WRRLoadBalancer) AddWeightedServerWithName(name string, serverHandler Handler, weight *int) {
+	b.addServer(server{Handler: serverHandler, service: name}, weight)
+}
+
+func (b *WRRLoadBalancer) addServer(srv server, weight *int) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	w := 1
+	srv.weight = 1
 	if weight != nil {
-		w = *weight
+		srv.weight = *weight
 	}
-	b.servers = append(b.servers, server{Handler: serverHandler, weight: w})
+	srv.active = &atomic.Int64{}
+	b.servers = append(b.servers, srv)
+	b.ring = nil
+}
+
+// targeted returns the server selected by the target, or false to fall back to the load-balancing.
+func (b *WRRLoadBalancer) targeted(target *Target) (server, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	i, ok := balancer.Targeted(serverList(b.servers), target.Service, target.ServerAddress, &target.serviceMatched)
+	if !ok {
+		return server{}, false
+	}
+	return b.servers[i], true
 }
 
 func (b *WRRLoadBalancer) maxWeight() int {
@@ -89,9 +183,46 @@ func gcd(a, b int) int {
 	return a
 }
 
-func (b *WRRLoadBalancer) next() (Handler, error) {
+// next returns the server of the session, selected with the balancing strategy.
+func (b *WRRLoadBalancer) next(conn *Conn) (server, error) {
+	if len(b.servers) == 0 {
+		return server{}, fmt.Errorf("no servers in the pool")
+	}
+
+	var index int
+	var ok bool
+	switch b.strategy {
+	case balancer.StrategyLeastConn:
+		index, ok = balancer.LeastConn(serverList(b.servers), b.index+1)
+	case balancer.StrategyP2C:
+		index, ok = balancer.P2C(serverList(b.servers), b.rand)
+	case balancer.StrategyHash:
+		if b.ring == nil {
+			b.ring = balancer.NewRing(serverList(b.servers))
+		}
+		index, ok = b.ring.Pick(serverList(b.servers), clientIP(conn))
+	default:
+		return b.nextWRR()
+	}
+
+	if !ok {
+		return server{}, errNoAvailableServer
+	}
+	b.index = index
+	return b.servers[index], nil
+}
+
+func clientIP(conn *Conn) string {
+	host, _, err := net.SplitHostPort(conn.rAddr.String())
+	if err != nil {
+		return conn.rAddr.String()
+	}
+	return host
+}
+
+func (b *WRRLoadBalancer) nextWRR() (server, error) {
 	if len(b.servers) == 0 {
-		return nil, fmt.Errorf("no servers in the pool")
+		return server{}, fmt.Errorf("no servers in the pool")
 	}
 
 	// The algorithm below may look messy,
@@ -101,7 +232,7 @@ func (b *WRRLoadBalancer) next() (Handler, error) {
 	// Maximum weight across all enabled servers
 	max := b.maxWeight()
 	if max == 0 {
-		return nil, fmt.Errorf("all servers have 0 weight")
+		return server{}, fmt.Errorf("all servers have 0 weight")
 	}
 
 	// GCD across all enabled servers